@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"text/template"
+)
+
+// Defaults for a BriefingTemplate that leaves them unset.
+const (
+	DefaultPenaltiesRange  = "penalties"
+	DefaultPenaltiesMarker = "[penalties]"
+)
+
+// defaultPlaceholders are the placeholders every briefing template has used,
+// in the order they are replaced.
+var defaultPlaceholders = []Placeholder{
+	{Text: "[num]", Value: "{{.Round.Number}}"},
+	{Text: "[Track Name]", Value: "{{.Round.Track}}"},
+	{Text: "[group1]", Value: "{{.Group1}}"},
+	{Text: "[group2]", Value: "{{.Group2}}"},
+	{Text: "[briefing time]", Value: "{{.BriefingTime}}"},
+	{Text: "[SEASON]", Value: "{{.Season}}"},
+}
+
+// BriefingTemplate describes how race setup fills in a copy of the briefing
+// template doc.
+type BriefingTemplate struct {
+	// PenaltiesRange names the named range in the template that the penalty
+	// sections replace. Defaults to DefaultPenaltiesRange.
+	PenaltiesRange string `yaml:"penalties_range"`
+	// PenaltiesMarker is the text of a paragraph in the template that the
+	// penalty sections replace when it has no PenaltiesRange. Defaults to
+	// DefaultPenaltiesMarker.
+	PenaltiesMarker string `yaml:"penalties_marker"`
+	// Placeholders maps text in the template to what replaces it, a
+	// text/template given the round, season, qualifying groups and briefing
+	// time, e.g. "[num]": "{{.Round.Number}}". They are added to the
+	// built-in placeholders, overriding any with the same text; an empty
+	// value drops one.
+	Placeholders map[string]string `yaml:"placeholders"`
+}
+
+// Placeholder is text in the briefing template and the text/template that
+// renders its replacement.
+type Placeholder struct {
+	Text  string
+	Value string
+}
+
+// Range returns the configured named range, or DefaultPenaltiesRange.
+func (b BriefingTemplate) Range() string {
+	if b.PenaltiesRange == "" {
+		return DefaultPenaltiesRange
+	}
+	return b.PenaltiesRange
+}
+
+// Marker returns the configured marker paragraph text, or
+// DefaultPenaltiesMarker.
+func (b BriefingTemplate) Marker() string {
+	if b.PenaltiesMarker == "" {
+		return DefaultPenaltiesMarker
+	}
+	return b.PenaltiesMarker
+}
+
+// PlaceholderList returns the placeholders to replace: the built-in ones in
+// their usual order with any overrides applied, then those the config adds,
+// sorted by text.
+func (b BriefingTemplate) PlaceholderList() []Placeholder {
+	var placeholders []Placeholder
+	for _, p := range defaultPlaceholders {
+		if value, ok := b.Placeholders[p.Text]; ok {
+			p.Value = value
+		}
+		if p.Value != "" {
+			placeholders = append(placeholders, p)
+		}
+	}
+	var added []string
+	for text, value := range b.Placeholders {
+		builtIn := slices.ContainsFunc(defaultPlaceholders, func(p Placeholder) bool { return p.Text == text })
+		if !builtIn && value != "" {
+			added = append(added, text)
+		}
+	}
+	slices.Sort(added)
+	for _, text := range added {
+		placeholders = append(placeholders, Placeholder{Text: text, Value: b.Placeholders[text]})
+	}
+	return placeholders
+}
+
+func (b BriefingTemplate) validate() error {
+	for text, value := range b.Placeholders {
+		if text == "" {
+			return fmt.Errorf("invalid briefing_template placeholders: placeholder text must not be empty")
+		}
+		if _, err := template.New(text).Option("missingkey=error").Parse(value); err != nil {
+			return fmt.Errorf("invalid briefing_template placeholder %q: %w", text, err)
+		}
+	}
+	return nil
+}
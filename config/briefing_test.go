@@ -0,0 +1,42 @@
+package config_test
+
+import (
+	"github.com/geofffranks/rookies-bot/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BriefingTemplate", func() {
+	It("defaults the penalties range and marker", func() {
+		Expect(config.BriefingTemplate{}.Range()).To(Equal("penalties"))
+		Expect(config.BriefingTemplate{}.Marker()).To(Equal("[penalties]"))
+		Expect(config.BriefingTemplate{PenaltiesRange: "bans", PenaltiesMarker: "{{penalties}}"}.Range()).To(Equal("bans"))
+		Expect(config.BriefingTemplate{PenaltiesRange: "bans", PenaltiesMarker: "{{penalties}}"}.Marker()).To(Equal("{{penalties}}"))
+	})
+
+	It("lists the built-in placeholders in order", func() {
+		var texts []string
+		for _, p := range (config.BriefingTemplate{}).PlaceholderList() {
+			texts = append(texts, p.Text)
+		}
+		Expect(texts).To(Equal([]string{"[num]", "[Track Name]", "[group1]", "[group2]", "[briefing time]", "[SEASON]"}))
+	})
+
+	It("overrides, drops and adds placeholders from the config", func() {
+		placeholders := config.BriefingTemplate{Placeholders: map[string]string{
+			"[Track Name]": "{{.Round.Track | printf \"%s GP\"}}",
+			"[group1]":     "",
+			"[group2]":     "",
+			"[round date]": "{{.Round.Date}}",
+			"[league]":     "Rookies",
+		}}.PlaceholderList()
+		Expect(placeholders).To(Equal([]config.Placeholder{
+			{Text: "[num]", Value: "{{.Round.Number}}"},
+			{Text: "[Track Name]", Value: "{{.Round.Track | printf \"%s GP\"}}"},
+			{Text: "[briefing time]", Value: "{{.BriefingTime}}"},
+			{Text: "[SEASON]", Value: "{{.Season}}"},
+			{Text: "[league]", Value: "Rookies"},
+			{Text: "[round date]", Value: "{{.Round.Date}}"},
+		}))
+	})
+})
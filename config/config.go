@@ -3,9 +3,12 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/scheduler"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,18 +17,41 @@ type Penalty struct {
 	QualiBansR2 []int `yaml:"quali_bans_r2"`
 	PitStartsR1 []int `yaml:"pit_starts_r1"`
 	PitStartsR2 []int `yaml:"pit_starts_r2"`
+	// Reasons records why the bot added a penalty, e.g. a missed briefing.
+	// Penalties from the stewards' decisions have no reason here.
+	Reasons []PenaltyReason `yaml:"reasons,omitempty"`
 }
 
 type Round struct {
 	Number             int    `yaml:"number"`
 	Track              string `yaml:"track"`
 	PenaltyTrackerLink string `yaml:"penalty_tracker_link"`
+	// Date is the race date, filled in from SimGrid when it has one. Either
+	// an RFC3339 timestamp or YYYY-MM-DD in the league timezone. When empty,
+	// the race is assumed to be on the next Schedule.RaceDay.
+	Date string `yaml:"date,omitempty"`
 }
 
 func (r Round) String() string {
 	return fmt.Sprintf("Round %d - %s", r.Number, r.Track)
 }
 
+// Message formats accepted by BotConfig.MessageFormat. An empty value is
+// treated as MessageFormatText.
+const (
+	MessageFormatText  = "text"
+	MessageFormatEmbed = "embed"
+)
+
+// How race setup treats a briefing doc already copied for the round, set by
+// BotConfig.ExistingDocs. An empty value is treated as ExistingDocsUpdate.
+const (
+	// ExistingDocsUpdate refills the doc's penalty sections in place.
+	ExistingDocsUpdate = "update"
+	// ExistingDocsKeep leaves the doc as it is.
+	ExistingDocsKeep = "keep"
+)
+
 type BotConfig struct {
 	SimGridApiToken string `yaml:"simgrid_api_token"`
 	ChampionshipId  string `yaml:"championship_id"`
@@ -36,11 +62,160 @@ type BotConfig struct {
 	BriefingFolderID          string `yaml:"briefing_folder_id"`
 	TrackerTemplateDocID      string `yaml:"tracker_template_doc_id"`
 	TrackerFolderID           string `yaml:"tracker_folder_id"`
+	// ExistingDocs selects what race setup does when the round's briefing
+	// doc is already in the briefing folder, e.g. from an earlier run:
+	// "update" (the default) refills its penalties, "keep" leaves it as it
+	// is. Either way it is reused rather than copied again, as is the
+	// round's penalty tracker.
+	ExistingDocs string `yaml:"existing_docs"`
+	// BriefingTemplate sets where the briefing template takes the penalty
+	// sections and which placeholders are filled in.
+	BriefingTemplate BriefingTemplate `yaml:"briefing_template"`
+	// Sharing sets who the briefing docs and penalty trackers race setup
+	// copies are shared with.
+	Sharing Sharing `yaml:"sharing"`
 
 	DiscordToken             string       `yaml:"discord_token"`
 	DiscordChannelId         snowflake.ID `yaml:"discord_channel_id"`
 	DiscordRoleName          string       `yaml:"discord_role_name"`
 	DiscordBriefingChannelId snowflake.ID `yaml:"discord_briefing_channel_id"`
+	// DiscordAdminChannelId is where the bot reports on work it does on its
+	// own, such as scheduled jobs, and the only channel it answers admin
+	// commands in. Optional; reports are logged when unset, and admin
+	// commands are answered in the announcement and briefing channels.
+	DiscordAdminChannelId snowflake.ID `yaml:"discord_admin_channel_id"`
+
+	// MessageFormat selects how race-day and penalty announcements are
+	// rendered: plain "text" (the default) or rich "embed".
+	MessageFormat string `yaml:"message_format"`
+
+	// TemplatesDir optionally names a directory of text/template files
+	// overriding the built-in announcement text. Files are matched by name
+	// (e.g. race_day.tmpl); anything missing or invalid uses the default.
+	TemplatesDir string `yaml:"templates_dir"`
+
+	// StateDir is where the bot persists state between restarts (driver
+	// identity links, round state). Defaults to a "state" directory next to
+	// the bot config file.
+	StateDir string `yaml:"state_dir"`
+
+	// ShutdownTimeout is how long the bot waits on shutdown for commands
+	// still running before cancelling them, e.g. "1m". Defaults to
+	// DefaultShutdownTimeout.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// Schedule sets the race night, briefing time and timezones.
+	Schedule Schedule `yaml:"schedule"`
+
+	// Jobs schedules race-week commands to run automatically.
+	Jobs Jobs `yaml:"jobs"`
+
+	// Reminders are posted to the league channel ahead of race night.
+	Reminders Reminders `yaml:"reminders"`
+
+	// AbsencePenalty is given to drivers who miss the drivers' briefing
+	// without an excuse.
+	AbsencePenalty AbsencePenalty `yaml:"absence_penalty"`
+
+	// RoleSync configures how `!sync-roles` and the role_sync job keep the
+	// season role in step with the championship's registered drivers.
+	RoleSync RoleSync `yaml:"role_sync"`
+
+	// Leagues lets one bot run several championships, each overriding the
+	// championship, Drive, Discord and schedule settings above. Optional; a
+	// bot without leagues runs the single championship configured above.
+	Leagues []League `yaml:"leagues"`
+
+	// League is the name of the league this config was derived for by
+	// LeagueConfigs. Empty for a bot without leagues.
+	League string `yaml:"-"`
+}
+
+// DefaultShutdownTimeout is the ShutdownTimeout of a config that doesn't set
+// one.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Reminders lists how long before the briefing and before the race start to
+// post a reminder, e.g. [24h, 1h, 10m]. Empty lists post no reminders.
+type Reminders struct {
+	BeforeBriefing []time.Duration `yaml:"before_briefing"`
+	BeforeRace     []time.Duration `yaml:"before_race"`
+}
+
+func (r Reminders) validate() error {
+	for _, offset := range append(append([]time.Duration{}, r.BeforeBriefing...), r.BeforeRace...) {
+		if offset <= 0 {
+			return fmt.Errorf("invalid reminder offset %s: must be positive", offset)
+		}
+	}
+	return nil
+}
+
+// RoleSync configures syncing DiscordRoleName with the drivers registered
+// for the championship. Registered drivers are always given the role.
+type RoleSync struct {
+	// RemoveWithdrawn also takes the role from members who are no longer
+	// registered.
+	RemoveWithdrawn bool `yaml:"remove_withdrawn"`
+}
+
+// Jobs holds five-field cron expressions (minute hour day-of-month month
+// day-of-week, in the league timezone) for commands the bot runs on its own
+// against the stored round config. An empty expression disables the job.
+type Jobs struct {
+	// AnnouncePenalties runs !announce-penalties, e.g. "0 18 * * thu".
+	AnnouncePenalties string `yaml:"announce_penalties"`
+	// RaceSetup runs !race-setup, e.g. "0 10 * * mon".
+	RaceSetup string `yaml:"race_setup"`
+	// RoleSync runs !sync-roles, e.g. "0 12 * * *".
+	RoleSync string `yaml:"role_sync"`
+}
+
+func (j Jobs) validate() error {
+	for name, expr := range map[string]string{"announce_penalties": j.AnnouncePenalties, "race_setup": j.RaceSetup, "role_sync": j.RoleSync} {
+		if expr == "" {
+			continue
+		}
+		if _, err := scheduler.Parse(expr); err != nil {
+			return fmt.Errorf("invalid jobs.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (b *BotConfig) validate() error {
+	switch b.MessageFormat {
+	case "", MessageFormatText, MessageFormatEmbed:
+	default:
+		return fmt.Errorf("invalid message_format %q (expected %q or %q)", b.MessageFormat, MessageFormatText, MessageFormatEmbed)
+	}
+	switch b.ExistingDocs {
+	case "", ExistingDocsUpdate, ExistingDocsKeep:
+	default:
+		return fmt.Errorf("invalid existing_docs %q (expected %q or %q)", b.ExistingDocs, ExistingDocsUpdate, ExistingDocsKeep)
+	}
+	if err := b.BriefingTemplate.validate(); err != nil {
+		return err
+	}
+	if err := b.Sharing.validate(); err != nil {
+		return err
+	}
+	if err := b.Schedule.validate(); err != nil {
+		return err
+	}
+	if err := b.Jobs.validate(); err != nil {
+		return err
+	}
+	if err := b.Reminders.validate(); err != nil {
+		return err
+	}
+	if err := b.AbsencePenalty.validate(); err != nil {
+		return err
+	}
+	if b.ShutdownTimeout < 0 {
+		return fmt.Errorf("invalid shutdown_timeout %s: must be positive", b.ShutdownTimeout)
+	}
+	return validateLeagues(b.Leagues)
 }
 
 type RoundConfig struct {
@@ -61,6 +236,15 @@ func Load(botConfigPath, roundConfigPath string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := botConfig.validate(); err != nil {
+		return nil, fmt.Errorf("invalid bot config %s: %w", botConfigPath, err)
+	}
+	if botConfig.StateDir == "" {
+		botConfig.StateDir = filepath.Join(filepath.Dir(botConfigPath), "state")
+	}
+	if botConfig.ShutdownTimeout == 0 {
+		botConfig.ShutdownTimeout = DefaultShutdownTimeout
+	}
 
 	roundConfig := &RoundConfig{}
 	if roundConfigPath != "" {
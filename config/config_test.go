@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/geofffranks/rookies-bot/config"
 	. "github.com/onsi/ginkgo/v2"
@@ -136,4 +137,167 @@ previous_round:
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed parsing"))
 	})
+
+	It("defaults state_dir to a directory next to the bot config", func() {
+		cfg, err := config.Load(botConfigPath, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.StateDir).To(Equal(filepath.Join(tmpDir, "state")))
+	})
+
+	It("defaults shutdown_timeout", func() {
+		cfg, err := config.Load(botConfigPath, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.ShutdownTimeout).To(Equal(config.DefaultShutdownTimeout))
+	})
+
+	It("loads shutdown_timeout as a duration", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("shutdown_timeout: 2m\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		cfg, err := config.Load(botConfigPath, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.ShutdownTimeout).To(Equal(2 * time.Minute))
+	})
+
+	It("returns an error when shutdown_timeout is negative", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("shutdown_timeout: -1s\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(MatchError(ContainSubstring("shutdown_timeout")))
+	})
+
+	It("returns an error when message_format is not recognized", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("message_format: html\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("message_format"))
+	})
+
+	It("returns an error when existing_docs is not recognized", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("existing_docs: replace\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(MatchError(ContainSubstring("invalid existing_docs \"replace\"")))
+	})
+
+	It("returns an error when a briefing template placeholder is not a valid template", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("briefing_template:\n  placeholders:\n    \"[num]\": \"{{.Round.Number\"\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(MatchError(ContainSubstring(`invalid briefing_template placeholder "[num]"`)))
+	})
+
+	It("returns an error when the sharing settings are invalid", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("sharing:\n  briefing_link: writer\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(MatchError(ContainSubstring(`invalid sharing briefing_link "writer"`)))
+	})
+
+	It("returns an error when a tracker editor is not an email address", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("sharing:\n  tracker_editors: [stewards]\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(MatchError(ContainSubstring(`invalid sharing tracker_editors: "stewards" is not an email address`)))
+	})
+
+	It("returns an error when the schedule is invalid", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("schedule:\n  race_day: Funday\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("race_day"))
+	})
+
+	It("returns an error when a job's cron expression is invalid", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("jobs:\n  race_setup: every monday\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("jobs.race_setup"))
+	})
+
+	It("loads the role sync job and settings", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("jobs:\n  role_sync: \"0 12 * * *\"\nrole_sync:\n  remove_withdrawn: true\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		cfg, err := config.Load(botConfigPath, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Jobs.RoleSync).To(Equal("0 12 * * *"))
+		Expect(cfg.RoleSync.RemoveWithdrawn).To(BeTrue())
+	})
+
+	It("loads reminder offsets as durations", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("reminders:\n  before_briefing: [24h, 1h]\n  before_race: [10m]\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		cfg, err := config.Load(botConfigPath, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Reminders.BeforeBriefing).To(Equal([]time.Duration{24 * time.Hour, time.Hour}))
+		Expect(cfg.Reminders.BeforeRace).To(Equal([]time.Duration{10 * time.Minute}))
+	})
+
+	It("returns an error when the absence penalty is unknown", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("absence_penalty:\n  penalty: pit_start\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(MatchError(ContainSubstring("invalid absence_penalty")))
+	})
+
+	It("returns an error when a reminder offset is not positive", func() {
+		f, err := os.OpenFile(botConfigPath, os.O_APPEND|os.O_WRONLY, 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("reminders:\n  before_race: [-10m]\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = config.Load(botConfigPath, "")
+		Expect(err).To(MatchError(ContainSubstring("invalid reminder offset")))
+	})
 })
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// League is one championship run by the bot, e.g. a second Rookies split.
+// Every field but Name is optional and falls back to the top-level bot
+// config value.
+type League struct {
+	// Name identifies the league in commands ("--league <name>") and names
+	// its state directory, so it is limited to letters, digits, "-" and "_".
+	Name string `yaml:"name"`
+
+	ChampionshipId string `yaml:"championship_id"`
+	Season         string `yaml:"season"`
+
+	BriefingTemplateDocID string `yaml:"briefing_template_doc_id"`
+	BriefingFolderID      string `yaml:"briefing_folder_id"`
+	TrackerTemplateDocID  string `yaml:"tracker_template_doc_id"`
+	TrackerFolderID       string `yaml:"tracker_folder_id"`
+
+	DiscordChannelId         snowflake.ID `yaml:"discord_channel_id"`
+	DiscordRoleName          string       `yaml:"discord_role_name"`
+	DiscordBriefingChannelId snowflake.ID `yaml:"discord_briefing_channel_id"`
+	DiscordAdminChannelId    snowflake.ID `yaml:"discord_admin_channel_id"`
+
+	Schedule *Schedule `yaml:"schedule"`
+	Jobs     *Jobs     `yaml:"jobs"`
+}
+
+var leagueNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func validateLeagues(leagues []League) error {
+	seen := map[string]bool{}
+	for i, league := range leagues {
+		if league.Name == "" {
+			return fmt.Errorf("invalid leagues[%d]: name is required", i)
+		}
+		if !leagueNamePattern.MatchString(league.Name) {
+			return fmt.Errorf("invalid league name %q: use only letters, digits, \"-\" and \"_\"", league.Name)
+		}
+		key := strings.ToLower(league.Name)
+		if seen[key] {
+			return fmt.Errorf("duplicate league name %q", league.Name)
+		}
+		seen[key] = true
+		if league.Schedule != nil {
+			if err := league.Schedule.validate(); err != nil {
+				return fmt.Errorf("invalid league %s: %w", league.Name, err)
+			}
+		}
+		if league.Jobs != nil {
+			if err := league.Jobs.validate(); err != nil {
+				return fmt.Errorf("invalid league %s: %w", league.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LeagueConfigs returns the bot config for each configured league: the
+// top-level config with the league's values laid over it, and its state kept
+// in its own directory under StateDir. A bot without leagues returns its own
+// config as the only one, with an empty League.
+func (b BotConfig) LeagueConfigs() []BotConfig {
+	if len(b.Leagues) == 0 {
+		return []BotConfig{b}
+	}
+
+	configs := make([]BotConfig, 0, len(b.Leagues))
+	for _, league := range b.Leagues {
+		c := b
+		c.Leagues = nil
+		c.League = league.Name
+		if c.StateDir != "" {
+			c.StateDir = filepath.Join(b.StateDir, "leagues", strings.ToLower(league.Name))
+		}
+
+		overlay(&c.ChampionshipId, league.ChampionshipId)
+		overlay(&c.Season, league.Season)
+		overlay(&c.BriefingTemplateDocID, league.BriefingTemplateDocID)
+		overlay(&c.BriefingFolderID, league.BriefingFolderID)
+		overlay(&c.TrackerTemplateDocID, league.TrackerTemplateDocID)
+		overlay(&c.TrackerFolderID, league.TrackerFolderID)
+		overlay(&c.DiscordChannelId, league.DiscordChannelId)
+		overlay(&c.DiscordRoleName, league.DiscordRoleName)
+		overlay(&c.DiscordBriefingChannelId, league.DiscordBriefingChannelId)
+		overlay(&c.DiscordAdminChannelId, league.DiscordAdminChannelId)
+		if league.Schedule != nil {
+			c.Schedule = *league.Schedule
+		}
+		if league.Jobs != nil {
+			c.Jobs = *league.Jobs
+		}
+		configs = append(configs, c)
+	}
+	return configs
+}
+
+// overlay sets *dst to value unless value is the zero value.
+func overlay[T comparable](dst *T, value T) {
+	var zero T
+	if value != zero {
+		*dst = value
+	}
+}
@@ -0,0 +1,116 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LeagueConfigs", func() {
+	base := config.BotConfig{
+		Season:           "2026 Fall",
+		ChampionshipId:   "100",
+		BriefingFolderID: "briefings",
+		TrackerFolderID:  "trackers",
+		DiscordChannelId: snowflake.ID(1),
+		DiscordRoleName:  "GT4 Rookies",
+		StateDir:         "/var/lib/rookies-bot",
+		Schedule:         config.Schedule{Timezone: "America/New_York"},
+		Jobs:             config.Jobs{RaceSetup: "0 12 * * 1"},
+	}
+
+	It("returns the config itself without leagues", func() {
+		Expect(base.LeagueConfigs()).To(Equal([]config.BotConfig{base}))
+	})
+
+	It("lays each league's values over the top-level config", func() {
+		conf := base
+		conf.Leagues = []config.League{
+			{Name: "GT4"},
+			{
+				Name:             "GT3",
+				ChampionshipId:   "200",
+				DiscordChannelId: snowflake.ID(2),
+				DiscordRoleName:  "GT3 Rookies",
+				Schedule:         &config.Schedule{Timezone: "Europe/London"},
+				Jobs:             &config.Jobs{},
+			},
+		}
+
+		configs := conf.LeagueConfigs()
+		Expect(configs).To(HaveLen(2))
+
+		gt4 := configs[0]
+		Expect(gt4.League).To(Equal("GT4"))
+		Expect(gt4.Leagues).To(BeNil())
+		Expect(gt4.ChampionshipId).To(Equal("100"))
+		Expect(gt4.DiscordChannelId).To(Equal(snowflake.ID(1)))
+		Expect(gt4.Jobs.RaceSetup).To(Equal("0 12 * * 1"))
+		Expect(gt4.StateDir).To(Equal(filepath.Join("/var/lib/rookies-bot", "leagues", "gt4")))
+
+		gt3 := configs[1]
+		Expect(gt3.League).To(Equal("GT3"))
+		Expect(gt3.ChampionshipId).To(Equal("200"))
+		Expect(gt3.Season).To(Equal("2026 Fall"))
+		Expect(gt3.BriefingFolderID).To(Equal("briefings"))
+		Expect(gt3.DiscordChannelId).To(Equal(snowflake.ID(2)))
+		Expect(gt3.DiscordRoleName).To(Equal("GT3 Rookies"))
+		Expect(gt3.Schedule.Timezone).To(Equal("Europe/London"))
+		Expect(gt3.Jobs).To(Equal(config.Jobs{}))
+		Expect(gt3.StateDir).To(Equal(filepath.Join("/var/lib/rookies-bot", "leagues", "gt3")))
+	})
+})
+
+var _ = Describe("leagues in the bot config", func() {
+	var botConfigPath string
+
+	load := func(leagues string) error {
+		Expect(os.WriteFile(botConfigPath, []byte("season: 2026 Fall\nleagues:\n"+leagues), 0600)).To(Succeed())
+		_, err := config.Load(botConfigPath, "")
+		return err
+	}
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "rookies-bot-league-test")
+		Expect(err).NotTo(HaveOccurred())
+		botConfigPath = filepath.Join(dir, "config.yml")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(botConfigPath))
+	})
+
+	It("loads leagues with their overrides", func() {
+		Expect(os.WriteFile(botConfigPath, []byte(`season: 2026 Fall
+leagues:
+- name: gt4
+  championship_id: "100"
+  discord_channel_id: 123
+  jobs:
+    race_setup: "0 12 * * 1"
+- name: gt3
+`), 0600)).To(Succeed())
+		cfg, err := config.Load(botConfigPath, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Leagues).To(HaveLen(2))
+		Expect(cfg.Leagues[0].ChampionshipId).To(Equal("100"))
+		Expect(cfg.Leagues[0].DiscordChannelId).To(Equal(snowflake.ID(123)))
+		Expect(cfg.Leagues[0].Jobs.RaceSetup).To(Equal("0 12 * * 1"))
+		Expect(cfg.Leagues[1].Jobs).To(BeNil())
+	})
+
+	It("requires a usable, unique name for each league", func() {
+		Expect(load("- championship_id: \"100\"\n")).To(MatchError(ContainSubstring("leagues[0]: name is required")))
+		Expect(load("- name: gt 4\n")).To(MatchError(ContainSubstring(`invalid league name "gt 4"`)))
+		Expect(load("- name: ../gt4\n")).To(MatchError(ContainSubstring(`invalid league name "../gt4"`)))
+		Expect(load("- name: gt4\n- name: GT4\n")).To(MatchError(ContainSubstring(`duplicate league name "GT4"`)))
+	})
+
+	It("validates each league's jobs", func() {
+		Expect(load("- name: gt4\n  jobs:\n    race_setup: every monday\n")).To(MatchError(ContainSubstring("invalid league gt4")))
+	})
+})
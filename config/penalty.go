@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Penalty kinds, matching the Penalty YAML keys.
+const (
+	PenaltyQualiBansR1 = "quali_bans_r1"
+	PenaltyQualiBansR2 = "quali_bans_r2"
+	PenaltyPitStartsR1 = "pit_starts_r1"
+	PenaltyPitStartsR2 = "pit_starts_r2"
+)
+
+// DefaultAbsencePenalty is the penalty for missing the briefing when
+// AbsencePenalty.Penalty is unset: a pit start in race 1.
+const DefaultAbsencePenalty = PenaltyPitStartsR1
+
+// PenaltyReason explains one penalty the bot added to a Penalty.
+type PenaltyReason struct {
+	CarNumber int    `yaml:"car_number"`
+	Penalty   string `yaml:"penalty"`
+	Reason    string `yaml:"reason"`
+}
+
+// AbsencePenalty configures the penalty for missing the drivers' briefing.
+type AbsencePenalty struct {
+	// Penalty is the penalty kind given, e.g. "pit_starts_r1" (the default).
+	Penalty string `yaml:"penalty"`
+	// MinAttendance is how long a driver must be in the briefing stage to
+	// count as attending, e.g. "15m". Any time at all counts when unset.
+	MinAttendance time.Duration `yaml:"min_attendance"`
+}
+
+// Kind returns the configured penalty kind, or DefaultAbsencePenalty.
+func (a AbsencePenalty) Kind() string {
+	if a.Penalty == "" {
+		return DefaultAbsencePenalty
+	}
+	return a.Penalty
+}
+
+func (a AbsencePenalty) validate() error {
+	if _, err := (&Penalty{}).list(a.Kind()); err != nil {
+		return fmt.Errorf("invalid absence_penalty: %w", err)
+	}
+	if a.MinAttendance < 0 {
+		return fmt.Errorf("invalid absence_penalty min_attendance %s: must not be negative", a.MinAttendance)
+	}
+	return nil
+}
+
+// Add gives carNumber the penalty kind, recording why. It returns false
+// without changing anything when the driver already has that penalty.
+func (p *Penalty) Add(kind string, carNumber int, reason string) (bool, error) {
+	list, err := p.list(kind)
+	if err != nil {
+		return false, err
+	}
+	if slices.Contains(*list, carNumber) {
+		return false, nil
+	}
+	*list = append(*list, carNumber)
+	p.Reasons = append(p.Reasons, PenaltyReason{CarNumber: carNumber, Penalty: kind, Reason: reason})
+	return true, nil
+}
+
+func (p *Penalty) list(kind string) (*[]int, error) {
+	switch kind {
+	case PenaltyQualiBansR1:
+		return &p.QualiBansR1, nil
+	case PenaltyQualiBansR2:
+		return &p.QualiBansR2, nil
+	case PenaltyPitStartsR1:
+		return &p.PitStartsR1, nil
+	case PenaltyPitStartsR2:
+		return &p.PitStartsR2, nil
+	}
+	return nil, fmt.Errorf("unknown penalty %q (expected %s, %s, %s or %s)", kind,
+		PenaltyQualiBansR1, PenaltyQualiBansR2, PenaltyPitStartsR1, PenaltyPitStartsR2)
+}
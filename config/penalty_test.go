@@ -0,0 +1,43 @@
+package config_test
+
+import (
+	"time"
+
+	"github.com/geofffranks/rookies-bot/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Penalty", func() {
+	Describe("Add", func() {
+		It("adds the car to the penalty and records the reason", func() {
+			p := config.Penalty{PitStartsR1: []int{7}}
+			added, err := p.Add(config.PenaltyPitStartsR1, 42, "Missed the briefing")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(BeTrue())
+			Expect(p.PitStartsR1).To(Equal([]int{7, 42}))
+			Expect(p.Reasons).To(Equal([]config.PenaltyReason{{CarNumber: 42, Penalty: "pit_starts_r1", Reason: "Missed the briefing"}}))
+		})
+
+		It("does nothing when the car already has the penalty", func() {
+			p := config.Penalty{QualiBansR2: []int{42}}
+			added, err := p.Add(config.PenaltyQualiBansR2, 42, "Missed the briefing")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(BeFalse())
+			Expect(p.QualiBansR2).To(Equal([]int{42}))
+			Expect(p.Reasons).To(BeEmpty())
+		})
+
+		It("rejects an unknown penalty", func() {
+			_, err := (&config.Penalty{}).Add("drive_through", 42, "")
+			Expect(err).To(MatchError(ContainSubstring(`unknown penalty "drive_through"`)))
+		})
+	})
+})
+
+var _ = Describe("AbsencePenalty", func() {
+	It("defaults to a race 1 pit start", func() {
+		Expect(config.AbsencePenalty{}.Kind()).To(Equal(config.PenaltyPitStartsR1))
+		Expect(config.AbsencePenalty{Penalty: config.PenaltyQualiBansR1, MinAttendance: time.Minute}.Kind()).To(Equal(config.PenaltyQualiBansR1))
+	})
+})
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule defaults, matching the league's original Monday 7:30PM Eastern
+// briefing.
+const (
+	DefaultRaceDay      = "Monday"
+	DefaultBriefingTime = "19:30"
+	DefaultTimezone     = "America/New_York"
+	// DefaultRaceDelay is how long after the briefing racing starts when
+	// Schedule.RaceTime is unset.
+	DefaultRaceDelay = 30 * time.Minute
+)
+
+// DefaultDisplayTimezones is used for the briefing doc text when
+// Schedule.DisplayTimezones is empty.
+var DefaultDisplayTimezones = []DisplayTimezone{
+	{Timezone: "America/New_York", Label: "Eastern"},
+	{Timezone: "America/Los_Angeles", Label: "Pacific"},
+}
+
+// Schedule describes when the league races. Every field is optional and falls
+// back to the defaults above.
+type Schedule struct {
+	// RaceDay is the weekday of race night, e.g. "Monday".
+	RaceDay string `yaml:"race_day"`
+	// BriefingTime is when the drivers' briefing starts on race night, as
+	// 24-hour "HH:MM" in Timezone.
+	BriefingTime string `yaml:"briefing_time"`
+	// RaceTime is when racing starts on race night, as 24-hour "HH:MM" in
+	// Timezone. Defaults to DefaultRaceDelay after the briefing.
+	RaceTime string `yaml:"race_time"`
+	// Timezone is the league's IANA timezone, e.g. "America/New_York".
+	Timezone string `yaml:"timezone"`
+	// DisplayTimezones are the timezones the briefing time is spelled out in
+	// on the briefing doc, e.g. "7:30PM Eastern/4:30PM Pacific".
+	DisplayTimezones []DisplayTimezone `yaml:"display_timezones"`
+}
+
+// DisplayTimezone is one timezone shown in the briefing doc. Label defaults to
+// the zone abbreviation (e.g. "CET").
+type DisplayTimezone struct {
+	Timezone string `yaml:"timezone"`
+	Label    string `yaml:"label"`
+}
+
+func (s Schedule) validate() error {
+	if _, err := s.Location(); err != nil {
+		return err
+	}
+	if _, err := s.Weekday(); err != nil {
+		return err
+	}
+	if _, _, err := s.clock(); err != nil {
+		return err
+	}
+	if s.RaceTime != "" {
+		if _, _, err := parseClock("race_time", s.RaceTime); err != nil {
+			return err
+		}
+	}
+	for _, display := range s.DisplayTimezones {
+		if _, err := time.LoadLocation(display.Timezone); err != nil {
+			return fmt.Errorf("invalid schedule display timezone %q: %w", display.Timezone, err)
+		}
+	}
+	return nil
+}
+
+// Location returns the league's timezone.
+func (s Schedule) Location() (*time.Location, error) {
+	name := s.Timezone
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// Weekday returns the race night weekday.
+func (s Schedule) Weekday() (time.Weekday, error) {
+	name := s.RaceDay
+	if name == "" {
+		name = DefaultRaceDay
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) || strings.EqualFold(day.String()[:3], name) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid schedule race_day %q", name)
+}
+
+func (s Schedule) clock() (int, int, error) {
+	value := s.BriefingTime
+	if value == "" {
+		value = DefaultBriefingTime
+	}
+	return parseClock("briefing_time", value)
+}
+
+func parseClock(name, value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid schedule %s %q (expected HH:MM)", name, value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// RaceTimeFor returns when racing starts on the night of the given briefing.
+func (s Schedule) RaceTimeFor(briefing time.Time) (time.Time, error) {
+	if s.RaceTime == "" {
+		return briefing.Add(DefaultRaceDelay), nil
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	hour, minute, err := parseClock("race_time", s.RaceTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day := briefing.In(loc)
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc), nil
+}
+
+// BriefingTimeFor returns when the drivers' briefing for round starts. The
+// round's Date (from the SimGrid race date) wins when set; otherwise it is
+// the next race day on or after now.
+func (s Schedule) BriefingTimeFor(round Round, now time.Time) (time.Time, error) {
+	loc, err := s.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	hour, minute, err := s.clock()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var day time.Time
+	if round.Date != "" {
+		day, err = round.date(loc)
+		if err != nil {
+			return time.Time{}, err
+		}
+	} else {
+		weekday, err := s.Weekday()
+		if err != nil {
+			return time.Time{}, err
+		}
+		day = now.In(loc)
+		day = day.AddDate(0, 0, int((weekday+7-day.Weekday())%7))
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc), nil
+}
+
+// DisplayTime spells t out in each display timezone, e.g.
+// "7:30PM Eastern/4:30PM Pacific".
+func (s Schedule) DisplayTime(t time.Time) (string, error) {
+	zones := s.DisplayTimezones
+	if len(zones) == 0 {
+		zones = DefaultDisplayTimezones
+	}
+
+	parts := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		loc, err := time.LoadLocation(zone.Timezone)
+		if err != nil {
+			return "", fmt.Errorf("invalid schedule display timezone %q: %w", zone.Timezone, err)
+		}
+		local := t.In(loc)
+		label := zone.Label
+		if label == "" {
+			label = local.Format("MST")
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", local.Format("3:04PM"), label))
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+// date parses Round.Date, either an RFC3339 timestamp (as SimGrid reports
+// race start times) or a plain YYYY-MM-DD, into a day in loc.
+func (r Round) date(loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, r.Date); err == nil {
+		return t.In(loc), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", r.Date, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q for %s (expected YYYY-MM-DD)", r.Date, r)
+	}
+	return t, nil
+}
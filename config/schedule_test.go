@@ -0,0 +1,100 @@
+package config_test
+
+import (
+	"time"
+
+	"github.com/geofffranks/rookies-bot/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	var (
+		eastern *time.Location
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		eastern, err = time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+		// Wednesday 4 March 2026, noon Eastern.
+		now = time.Date(2026, time.March, 4, 12, 0, 0, 0, eastern)
+	})
+
+	Describe("BriefingTimeFor", func() {
+		It("defaults to the next Monday at 7:30PM Eastern", func() {
+			t, err := config.Schedule{}.BriefingTimeFor(config.Round{}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally("==", time.Date(2026, time.March, 9, 19, 30, 0, 0, eastern)))
+		})
+
+		It("uses race night itself when run on race day", func() {
+			monday := time.Date(2026, time.March, 9, 9, 0, 0, 0, eastern)
+			t, err := config.Schedule{}.BriefingTimeFor(config.Round{}, monday)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally("==", time.Date(2026, time.March, 9, 19, 30, 0, 0, eastern)))
+		})
+
+		It("honors the configured race day, briefing time and timezone", func() {
+			schedule := config.Schedule{RaceDay: "thu", BriefingTime: "20:00", Timezone: "Europe/London"}
+			london, err := time.LoadLocation("Europe/London")
+			Expect(err).NotTo(HaveOccurred())
+
+			t, err := schedule.BriefingTimeFor(config.Round{}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally("==", time.Date(2026, time.March, 5, 20, 0, 0, 0, london)))
+		})
+
+		It("prefers the round's SimGrid race date, converted to the league timezone", func() {
+			round := config.Round{Number: 3, Track: "Spa", Date: "2026-03-17T00:30:00Z"}
+			t, err := config.Schedule{}.BriefingTimeFor(round, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally("==", time.Date(2026, time.March, 16, 19, 30, 0, 0, eastern)))
+		})
+
+		It("accepts a plain race date", func() {
+			round := config.Round{Number: 3, Track: "Spa", Date: "2026-03-18"}
+			t, err := config.Schedule{}.BriefingTimeFor(round, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally("==", time.Date(2026, time.March, 18, 19, 30, 0, 0, eastern)))
+		})
+
+		It("rejects an unparseable race date", func() {
+			round := config.Round{Number: 3, Track: "Spa", Date: "next week"}
+			_, err := config.Schedule{}.BriefingTimeFor(round, now)
+			Expect(err).To(MatchError(ContainSubstring(`invalid date "next week" for Round 3 - Spa`)))
+		})
+	})
+
+	Describe("RaceTimeFor", func() {
+		It("defaults to 30 minutes after the briefing", func() {
+			briefing := time.Date(2026, time.March, 9, 19, 30, 0, 0, eastern)
+			t, err := config.Schedule{}.RaceTimeFor(briefing)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally("==", time.Date(2026, time.March, 9, 20, 0, 0, 0, eastern)))
+		})
+
+		It("uses the configured race time on the briefing's day", func() {
+			briefing := time.Date(2026, time.March, 9, 19, 30, 0, 0, eastern)
+			t, err := config.Schedule{RaceTime: "20:15"}.RaceTimeFor(briefing)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally("==", time.Date(2026, time.March, 9, 20, 15, 0, 0, eastern)))
+		})
+	})
+
+	Describe("DisplayTime", func() {
+		It("defaults to Eastern and Pacific", func() {
+			text, err := config.Schedule{}.DisplayTime(time.Date(2026, time.March, 9, 19, 30, 0, 0, eastern))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(text).To(Equal("7:30PM Eastern/4:30PM Pacific"))
+		})
+
+		It("falls back to the zone abbreviation when no label is set", func() {
+			schedule := config.Schedule{DisplayTimezones: []config.DisplayTimezone{{Timezone: "UTC"}}}
+			text, err := schedule.DisplayTime(time.Date(2026, time.March, 9, 19, 30, 0, 0, eastern))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(text).To(Equal("11:30PM UTC"))
+		})
+	})
+})
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Roles anyone with a briefing doc's link can be given, as Drive names them.
+const (
+	LinkRoleReader    = "reader"
+	LinkRoleCommenter = "commenter"
+)
+
+// Sharing sets who race setup shares the briefing docs and penalty trackers
+// it copies with, on top of whatever their folders already share. Unset, the
+// copies share only what their folders do.
+type Sharing struct {
+	// BriefingLink lets anyone with a briefing doc's link open it as a
+	// "reader" or "commenter".
+	BriefingLink string `yaml:"briefing_link"`
+	// TrackerEditors are the emails of the stewards who can edit the
+	// penalty trackers.
+	TrackerEditors []string `yaml:"tracker_editors"`
+}
+
+func (s Sharing) validate() error {
+	switch s.BriefingLink {
+	case "", LinkRoleReader, LinkRoleCommenter:
+	default:
+		return fmt.Errorf("invalid sharing briefing_link %q (expected %q or %q)", s.BriefingLink, LinkRoleReader, LinkRoleCommenter)
+	}
+	for _, email := range s.TrackerEditors {
+		if !strings.Contains(email, "@") {
+			return fmt.Errorf("invalid sharing tracker_editors: %q is not an email address", email)
+		}
+	}
+	return nil
+}
@@ -41,6 +41,62 @@ func UpdateBotConfigFile(path string, updates map[string]string) error {
 	return nil
 }
 
+// UpdateLeagueConfigFile is UpdateBotConfigFile for the entry named league
+// under the leagues key of the bot config file at path.
+func UpdateLeagueConfigFile(path, league string, updates map[string]string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-supplied CLI config path
+	if err != nil {
+		return fmt.Errorf("failed reading %s: %w", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("failed parsing %s: %w", path, err)
+	}
+
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("config file %s is not a YAML mapping", path)
+	}
+	mapping, err := leagueMapping(root.Content[0], league)
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	for key, value := range updates {
+		setMappingValue(mapping, key, value)
+	}
+
+	out, err := yaml.Marshal(&root)
+	if err != nil {
+		return fmt.Errorf("failed serializing updated config %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, out, 0600); err != nil {
+		return fmt.Errorf("failed writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// leagueMapping finds the mapping node for the league named name in the
+// leagues sequence of the top-level mapping.
+func leagueMapping(mapping *yaml.Node, name string) (*yaml.Node, error) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != "leagues" || mapping.Content[i+1].Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, league := range mapping.Content[i+1].Content {
+			if league.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j+1 < len(league.Content); j += 2 {
+				if league.Content[j].Value == "name" && league.Content[j+1].Value == name {
+					return league, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("no league named %q", name)
+}
+
 // setMappingValue sets the scalar value for key in a YAML mapping node,
 // appending the key/value pair if key is not already present. Mapping node
 // Content is a flat slice of [key0, val0, key1, val1, ...].
@@ -88,3 +88,53 @@ tracker_folder_id: old-tracker
 		Expect(err.Error()).To(ContainSubstring("not a YAML mapping"))
 	})
 })
+
+var _ = Describe("UpdateLeagueConfigFile", func() {
+	var path string
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "rookies-bot-update-test")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "config.yml")
+		err = os.WriteFile(path, []byte(`# rookies-bot config
+discord_token: super-secret-token
+season: 2026 Fall
+leagues:
+  # the original league
+  - name: gt4
+    championship_id: "9485"
+  - name: gt3
+    championship_id: "9486"
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(path))
+	})
+
+	It("updates only the named league", func() {
+		err := config.UpdateLeagueConfigFile(path, "gt3", map[string]string{
+			"season":          "2027 Winter",
+			"championship_id": "24877",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("# the original league"))
+
+		var parsed config.BotConfig
+		Expect(yaml.Unmarshal(data, &parsed)).To(Succeed())
+		Expect(parsed.Season).To(Equal("2026 Fall"))
+		Expect(parsed.Leagues[0].ChampionshipId).To(Equal("9485"))
+		Expect(parsed.Leagues[0].Season).To(BeEmpty())
+		Expect(parsed.Leagues[1].ChampionshipId).To(Equal("24877"))
+		Expect(parsed.Leagues[1].Season).To(Equal("2027 Winter"))
+	})
+
+	It("returns an error when the league is not in the file", func() {
+		err := config.UpdateLeagueConfigFile(path, "gt2", map[string]string{"season": "2027 Winter"})
+		Expect(err).To(MatchError(ContainSubstring(`no league named "gt2"`)))
+	})
+})
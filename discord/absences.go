@@ -0,0 +1,393 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/simgrid"
+)
+
+// excusesDocument is the state document holding the excuses drivers posted
+// for missing one round's briefing.
+func excusesDocument(season string, round config.Round) string {
+	return roundDocument("excuses", season, round)
+}
+
+// absencePenaltiesDocument is the state document holding the absence
+// penalties given for missing one round's briefing. They are kept apart from
+// the stored round config, which `!set-round` and race setup replace, and
+// merged into a round config whenever its penalties are stored or used.
+func absencePenaltiesDocument(season string, round config.Round) string {
+	return roundDocument("absence-penalties", season, round)
+}
+
+// absencePenalties records the penalties given for missing a briefing.
+type absencePenalties struct {
+	Round     config.Round   `yaml:"round"`
+	Penalties config.Penalty `yaml:"penalties"`
+}
+
+// mergeAbsencePenalties adds the absence penalties stored for rc's previous
+// round in season to rc, skipping drivers who already have that penalty.
+func (d *DiscordClient) mergeAbsencePenalties(season string, rc *config.RoundConfig) error {
+	var stored absencePenalties
+	if err := d.state.Load(absencePenaltiesDocument(season, rc.PreviousRound), &stored); err != nil {
+		return err
+	}
+	return addPenalties(&rc.Penalties, stored.Penalties)
+}
+
+// addPenalties adds every penalty with a recorded reason in src to dst.
+func addPenalties(dst *config.Penalty, src config.Penalty) error {
+	for _, r := range src.Reasons {
+		if _, err := dst.Add(r.Penalty, r.CarNumber, r.Reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excuseList records each excused driver's reason, keyed by Discord user ID.
+type excuseList struct {
+	Round   config.Round      `yaml:"round"`
+	Excuses map[string]excuse `yaml:"excuses"`
+}
+
+type excuse struct {
+	Reason string    `yaml:"reason"`
+	At     time.Time `yaml:"at"`
+}
+
+// excuseRound returns the briefing an excuse posted now applies to: the one
+// being tracked for attendance if race setup has run, otherwise the next
+// round in the stored round config.
+func (d *DiscordClient) excuseRound() (string, config.Round, error) {
+	var tracker attendanceTracker
+	if err := d.state.Load(attendanceDocument, &tracker); err != nil {
+		return "", config.Round{}, err
+	}
+	if tracker.Session != nil {
+		return tracker.Session.Season, tracker.Session.Round, nil
+	}
+	rc, err := d.loadCurrentRound()
+	if err != nil {
+		return "", config.Round{}, fmt.Errorf("there is no upcoming briefing to excuse yourself from yet")
+	}
+	return d.snapshotConfig().Season, rc.NextRound, nil
+}
+
+func (d *DiscordClient) excuse(event *events.MessageCreate, args []string) {
+	var msg, attachment string
+	defer func() { sendBotResponse(event, msg, attachment) }()
+	var err error
+	msg, attachment, err = d.runExcuse(event.Message.Author.ID, args, time.Now())
+	if err != nil {
+		msg = err.Error()
+	}
+}
+
+// runExcuse records that userID will miss the upcoming briefing, and why.
+func (d *DiscordClient) runExcuse(userID snowflake.ID, args []string, now time.Time) (string, string, error) {
+	reason := strings.TrimSpace(strings.Join(args, " "))
+	if reason == "" {
+		return "", "", fmt.Errorf("usage: `!excuse <reason>`, e.g. `!excuse working late`")
+	}
+	season, round, err := d.excuseRound()
+	if err != nil {
+		return "", "", err
+	}
+
+	var excuses excuseList
+	err = d.state.Update(excusesDocument(season, round), &excuses, func() error {
+		excuses.Round = round
+		if excuses.Excuses == nil {
+			excuses.Excuses = map[string]excuse{}
+		}
+		excuses.Excuses[userID.String()] = excuse{Reason: reason, At: now}
+		return nil
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed saving your excuse: %w", err)
+	}
+	return fmt.Sprintf("Thanks, you're excused from the %s drivers' briefing: %s", round, reason), "", nil
+}
+
+func (d *DiscordClient) absencePenalties(event *events.MessageCreate, args []string) {
+	var msg, attachment string
+	defer func() { sendBotResponse(event, msg, attachment) }()
+
+	var attended []int
+	if attachments := event.Message.Attachments; len(attachments) > 0 {
+		if len(attachments) > 1 {
+			msg = "Too many attachments, please only submit one CSV of attending car numbers"
+			return
+		}
+		content, err := downloadAttachment(attachments[0].URL)
+		if err != nil {
+			msg = fmt.Sprintf("Unexpected error downloading the attached file: %s", err)
+			return
+		}
+		attended, err = parseCarNumbersCSV(content)
+		if err != nil {
+			msg = fmt.Sprintf("Unable to parse the attendance CSV: %s", err)
+			return
+		}
+	}
+
+	sgClient := d.newSimGridClient()
+	var err error
+	msg, attachment, err = d.runAbsencePenalties(args, attended, sgClient)
+	if err != nil {
+		msg = err.Error()
+	}
+}
+
+// parseCarNumbersCSV reads car numbers from a CSV, in any layout: every
+// field is a car number (optionally prefixed with "#"). A first row that is
+// not numeric is taken to be a header and skipped.
+func parseCarNumbersCSV(content []byte) ([]int, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var cars []int
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var numbers []int
+		for _, field := range record {
+			field = strings.TrimPrefix(strings.TrimSpace(field), "#")
+			if field == "" {
+				continue
+			}
+			number, err := strconv.Atoi(field)
+			if err != nil {
+				if row == 1 {
+					numbers = nil
+					break
+				}
+				return nil, fmt.Errorf("invalid car number %q on line %d", field, row)
+			}
+			numbers = append(numbers, number)
+		}
+		cars = append(cars, numbers...)
+	}
+	if len(cars) == 0 {
+		return nil, fmt.Errorf("no car numbers found")
+	}
+	return cars, nil
+}
+
+// absence is a registered driver who did not attend a briefing.
+type absence struct {
+	driver models.Driver
+	// discordID is zero when the driver has no known Discord account.
+	discordID snowflake.ID
+	// attended is set for drivers who joined but left too soon.
+	attended time.Duration
+}
+
+// runAbsencePenalties gives the configured absence penalty to every driver
+// who missed the briefing for the round in args without an excuse. Attendance
+// comes from attended car numbers when given, otherwise from the attendance
+// the bot recorded. The penalties are stored for the round and merged into
+// the stored round config, whose previous round must be the round in
+// question, and the updated config is attached.
+func (d *DiscordClient) runAbsencePenalties(args []string, attended []int, sgClient *simgrid.SimGridClient) (string, string, error) {
+	if len(args) != 1 {
+		return "", "", fmt.Errorf("usage: `!absence-penalties <round number>`, optionally attaching a CSV of attending car numbers")
+	}
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		return "", "", fmt.Errorf("invalid round number %q", args[0])
+	}
+	conf := d.snapshotConfig()
+
+	rc, err := d.loadCurrentRound()
+	if err != nil {
+		return "", "", err
+	}
+	if rc.PreviousRound.Number != number {
+		return "", "", fmt.Errorf("the stored round config takes penalties from %s, not round %d. Absence penalties are served the round after the missed briefing, so run this after race setup for round %d", rc.PreviousRound, number, number)
+	}
+	round := rc.PreviousRound
+
+	driverLookup, err := sgClient.BuildDriverLookup(conf.ChampionshipId)
+	if err != nil {
+		return "", "", err
+	}
+
+	var absences, unchecked []absence
+	if attended != nil {
+		absences, err = d.absencesFromCarNumbers(driverLookup, attended)
+	} else {
+		absences, unchecked, err = d.absencesFromReport(driverLookup, conf.Season, round, conf.AbsencePenalty.MinAttendance)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	var excuses excuseList
+	if err := d.state.Load(excusesDocument(conf.Season, round), &excuses); err != nil {
+		return "", "", err
+	}
+
+	kind := conf.AbsencePenalty.Kind()
+	var given config.Penalty
+	var penalized, alreadyPenalized, excused []string
+	for _, a := range absences {
+		label := fmt.Sprintf("#%d %s", a.driver.CarNumber, a.driver.Name())
+		if a.discordID != 0 {
+			label = fmt.Sprintf("%s (<@%s>)", label, a.discordID)
+			if e, ok := excuses.Excuses[a.discordID.String()]; ok {
+				excused = append(excused, fmt.Sprintf("%s: %s", label, e.Reason))
+				continue
+			}
+		}
+		reason := fmt.Sprintf("Missed the %s drivers' briefing", round)
+		if a.attended > 0 {
+			reason = fmt.Sprintf("%s (attended %s)", reason, a.attended.Round(time.Minute))
+			label = fmt.Sprintf("%s, attended %s", label, a.attended.Round(time.Minute))
+		}
+		if _, err := given.Add(kind, a.driver.CarNumber, reason); err != nil {
+			return "", "", err
+		}
+		added, err := rc.Penalties.Add(kind, a.driver.CarNumber, reason)
+		if err != nil {
+			return "", "", err
+		}
+		if added {
+			penalized = append(penalized, label)
+		} else {
+			alreadyPenalized = append(alreadyPenalized, label)
+		}
+	}
+
+	var stored absencePenalties
+	err = d.state.Update(absencePenaltiesDocument(conf.Season, round), &stored, func() error {
+		stored.Round = round
+		return addPenalties(&stored.Penalties, given)
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed saving absence penalties: %w", err)
+	}
+	if err := d.saveCurrentRound(rc); err != nil {
+		return "", "", fmt.Errorf("failed saving round config: %w", err)
+	}
+	attachment, err := writeNextRoundConfig(rc, conf.Season)
+	if err != nil {
+		return "", "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Absence penalties for the %s briefing** (`%s`, served at %s)\n", round, kind, rc.NextRound)
+	writeList := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n**%s**\n", title)
+		for _, line := range lines {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+	}
+	if len(penalized)+len(alreadyPenalized) == 0 {
+		fmt.Fprintf(&b, "\nNobody missed the briefing without an excuse. 🎉\n")
+	}
+	writeList("Penalized", penalized)
+	writeList("Already penalized", alreadyPenalized)
+	writeList("Excused", excused)
+	var uncheckedLines []string
+	for _, a := range unchecked {
+		uncheckedLines = append(uncheckedLines, fmt.Sprintf("#%d %s", a.driver.CarNumber, a.driver.Name()))
+	}
+	writeList("Not checked (no Discord account found, see `!unlinked`)", uncheckedLines)
+	if err := d.postToStewards(round, b.String()); err != nil {
+		fmt.Fprintf(&b, "\nCould not post to the %s stewards thread: %s\n", round, err)
+	}
+	fmt.Fprintf(&b, "\nThe stored round config is updated and attached.")
+	return b.String(), attachment, nil
+}
+
+// absencesFromCarNumbers returns every registered driver whose car number is
+// not in attended.
+func (d *DiscordClient) absencesFromCarNumbers(driverLookup models.DriverLookup, attended []int) ([]absence, error) {
+	present := map[int]bool{}
+	for _, car := range attended {
+		present[car] = true
+	}
+	var absences []absence
+	for _, driver := range driverLookup {
+		if present[driver.CarNumber] {
+			continue
+		}
+		a := absence{driver: driver}
+		id, err := d.getDriverId(driver)
+		if err != nil && !errors.Is(err, DiscordHandleNotFoundError{}) {
+			return nil, err
+		}
+		if err == nil {
+			a.discordID = id
+		}
+		absences = append(absences, a)
+	}
+	sortAbsences(absences)
+	return absences, nil
+}
+
+// absencesFromReport returns the registered drivers the recorded attendance
+// report has absent, or present for less than minAttendance. Absent drivers
+// without a known Discord account could not have been seen in the stage, so
+// they are returned separately as unchecked.
+func (d *DiscordClient) absencesFromReport(driverLookup models.DriverLookup, season string, round config.Round, minAttendance time.Duration) ([]absence, []absence, error) {
+	report, err := d.loadAttendanceReport(season, round)
+	if err != nil {
+		return nil, nil, err
+	}
+	if report == nil {
+		return nil, nil, fmt.Errorf("no briefing attendance was recorded for round %d. Attach a CSV of the car numbers that attended instead", round.Number)
+	}
+
+	var absences, unchecked []absence
+	for _, entry := range report.Absent {
+		driver, ok := driverLookup[entry.CarNumber]
+		if !ok {
+			// Withdrew since the briefing.
+			continue
+		}
+		if entry.DiscordID == 0 {
+			unchecked = append(unchecked, absence{driver: driver})
+			continue
+		}
+		absences = append(absences, absence{driver: driver, discordID: entry.DiscordID})
+	}
+	for _, entry := range report.Attended {
+		driver, ok := driverLookup[entry.CarNumber]
+		if !ok || entry.Duration >= minAttendance {
+			continue
+		}
+		absences = append(absences, absence{driver: driver, discordID: entry.DiscordID, attended: entry.Duration})
+	}
+	sortAbsences(absences)
+	sortAbsences(unchecked)
+	return absences, unchecked, nil
+}
+
+func sortAbsences(absences []absence) {
+	sort.Slice(absences, func(i, j int) bool { return absences[i].driver.CarNumber < absences[j].driver.CarNumber })
+}
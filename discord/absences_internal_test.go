@@ -0,0 +1,180 @@
+package discord
+
+import (
+	"os"
+	"time"
+
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parseCarNumbersCSV", func() {
+	It("reads car numbers from any layout", func() {
+		cars, err := parseCarNumbersCSV([]byte("car,name\n#1, Max\n4\n81,\n"))
+		Expect(err).To(MatchError(ContainSubstring(`invalid car number "Max" on line 2`)))
+		Expect(cars).To(BeNil())
+
+		cars, err = parseCarNumbersCSV([]byte("car number\n#1\n4, 81\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cars).To(Equal([]int{1, 4, 81}))
+	})
+
+	It("rejects a CSV without car numbers", func() {
+		_, err := parseCarNumbersCSV([]byte("car number\n"))
+		Expect(err).To(MatchError("no car numbers found"))
+	})
+})
+
+var _ = Describe("absence penalties", func() {
+	var (
+		league   *testLeague
+		client   *DiscordClient
+		sgClient *simgrid.SimGridClient
+		rc       *config.RoundConfig
+		monza    config.Round
+	)
+
+	BeforeEach(func() {
+		league = newTestLeague("absences", config.BotConfig{}, nil)
+		client, sgClient = league.client, league.sgClient
+		league.entries = `{"entries":[
+			{"drivers":[{"firstName":"Max","lastName":"Verstappen","playerId":"S111"}],"raceNumber":1},
+			{"drivers":[{"firstName":"Lando","lastName":"Norris","playerId":"S222"}],"raceNumber":4},
+			{"drivers":[{"firstName":"Oscar","lastName":"Piastri","playerId":"S333"}],"raceNumber":81}]}`
+		league.users = `[
+			{"steam64_id":"111","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"},
+			{"steam64_id":"222","username":"lando.norris","first_name":"Lando","last_name":"Norris"},
+			{"steam64_id":"333","username":"oscar.piastri","first_name":"Oscar","last_name":"Piastri"}]`
+
+		monza = config.Round{Number: 4, Track: "Monza"}
+		rc = &config.RoundConfig{
+			PreviousRound: monza,
+			NextRound:     config.Round{Number: 5, Track: "Imola"},
+			Penalties:     config.Penalty{QualiBansR1: []int{1}},
+		}
+		Expect(client.saveCurrentRound(rc)).To(Succeed())
+
+		Expect(client.state.Save(attendanceReportDocument("2026 Fall", monza), &AttendanceReport{
+			Season:   "2026 Fall",
+			Round:    monza,
+			Attended: []AttendanceEntry{{CarNumber: 1, Name: "Max Verstappen", DiscordID: snowflakeID(42), Duration: 30 * time.Minute}},
+			Absent: []AttendanceEntry{
+				{CarNumber: 4, Name: "Lando Norris", DiscordID: snowflakeID(43)},
+				{CarNumber: 81, Name: "Oscar Piastri"},
+			},
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		league.close()
+	})
+
+	run := func(attended []int) (string, *config.RoundConfig) {
+		msg, attachment, err := client.runAbsencePenalties([]string{"4"}, attended, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attachment).NotTo(BeEmpty())
+		Expect(os.Remove(attachment)).To(Succeed())
+		stored, err := client.loadCurrentRound()
+		Expect(err).NotTo(HaveOccurred())
+		return msg, stored
+	}
+
+	It("penalizes unexcused absences from the recorded attendance", func() {
+		msg, stored := run(nil)
+		Expect(stored.Penalties.PitStartsR1).To(Equal([]int{4}))
+		Expect(stored.Penalties.QualiBansR1).To(Equal([]int{1}))
+		Expect(stored.Penalties.Reasons).To(Equal([]config.PenaltyReason{
+			{CarNumber: 4, Penalty: config.PenaltyPitStartsR1, Reason: "Missed the Round 4 - Monza drivers' briefing"},
+		}))
+		Expect(msg).To(ContainSubstring("(`pit_starts_r1`, served at Round 5 - Imola)"))
+		Expect(msg).To(ContainSubstring("**Penalized**\n- #4 Lando Norris (<@43>)\n"))
+		Expect(msg).To(ContainSubstring("**Not checked (no Discord account found, see `!unlinked`)**\n- #81 Oscar Piastri\n"))
+	})
+
+	It("skips drivers who excused themselves", func() {
+		// Without a briefing being tracked, excuses are for the stored next round.
+		reply, _, err := client.runExcuse(snowflakeID(43), []string{"on", "holiday"}, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reply).To(ContainSubstring("Round 5 - Imola"))
+
+		Expect(client.state.Save(attendanceDocument, &attendanceTracker{Session: &briefingSession{Season: "2026 Fall", Round: monza}})).To(Succeed())
+		reply, _, err = client.runExcuse(snowflakeID(43), []string{"working", "late"}, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reply).To(Equal("Thanks, you're excused from the Round 4 - Monza drivers' briefing: working late"))
+
+		msg, stored := run(nil)
+		Expect(stored.Penalties.PitStartsR1).To(BeEmpty())
+		Expect(msg).To(ContainSubstring("Nobody missed the briefing without an excuse."))
+		Expect(msg).To(ContainSubstring("**Excused**\n- #4 Lando Norris (<@43>): working late\n"))
+	})
+
+	It("penalizes drivers who left before the minimum attendance", func() {
+		client.conf.AbsencePenalty = config.AbsencePenalty{Penalty: config.PenaltyQualiBansR2, MinAttendance: 45 * time.Minute}
+		msg, stored := run(nil)
+		Expect(stored.Penalties.QualiBansR2).To(Equal([]int{1, 4}))
+		Expect(msg).To(ContainSubstring("- #1 Max Verstappen (<@42>), attended 30m0s\n"))
+		Expect(stored.Penalties.Reasons[0].Reason).To(Equal("Missed the Round 4 - Monza drivers' briefing (attended 30m0s)"))
+	})
+
+	It("uses uploaded car numbers instead of the recorded attendance", func() {
+		msg, stored := run([]int{4})
+		Expect(stored.Penalties.PitStartsR1).To(Equal([]int{1, 81}))
+		Expect(msg).To(ContainSubstring("- #81 Oscar Piastri\n"))
+		Expect(msg).NotTo(ContainSubstring("Not checked"))
+	})
+
+	It("does not penalize a driver twice", func() {
+		run(nil)
+		msg, stored := run(nil)
+		Expect(stored.Penalties.PitStartsR1).To(Equal([]int{4}))
+		Expect(stored.Penalties.Reasons).To(HaveLen(1))
+		Expect(msg).To(ContainSubstring("**Already penalized**\n- #4 Lando Norris"))
+	})
+
+	It("keeps the penalties when an admin replaces the stored round config", func() {
+		run(nil)
+		_, err := client.runSetRound(&config.RoundConfig{
+			PreviousRound: monza,
+			NextRound:     config.Round{Number: 5, Track: "Imola"},
+			Penalties:     config.Penalty{QualiBansR2: []int{81}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		stored, err := client.loadCurrentRound()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Penalties.QualiBansR2).To(Equal([]int{81}))
+		Expect(stored.Penalties.PitStartsR1).To(Equal([]int{4}))
+		Expect(stored.Penalties.Reasons).To(HaveLen(1))
+
+		_, err = client.runSetRound(&config.RoundConfig{
+			PreviousRound: config.Round{Number: 5, Track: "Imola"},
+			NextRound:     config.Round{Number: 6, Track: "Spa"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		stored, err = client.loadCurrentRound()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Penalties.PitStartsR1).To(BeEmpty())
+	})
+
+	It("only merges into the round config serving the missed round's penalties", func() {
+		_, _, err := client.runAbsencePenalties([]string{"5"}, nil, sgClient)
+		Expect(err).To(MatchError(ContainSubstring("takes penalties from Round 4 - Monza, not round 5")))
+	})
+
+	It("needs recorded attendance or a CSV", func() {
+		Expect(client.state.Delete(attendanceReportDocument("2026 Fall", monza))).To(Succeed())
+		_, _, err := client.runAbsencePenalties([]string{"4"}, nil, sgClient)
+		Expect(err).To(MatchError(ContainSubstring("Attach a CSV")))
+	})
+
+	It("requires a reason and an upcoming briefing to excuse yourself", func() {
+		_, _, err := client.runExcuse(snowflakeID(43), nil, time.Now())
+		Expect(err).To(MatchError(ContainSubstring("usage")))
+
+		Expect(client.state.Delete(currentRoundDocument)).To(Succeed())
+		_, _, err = client.runExcuse(snowflakeID(43), []string{"sick"}, time.Now())
+		Expect(err).To(MatchError(ContainSubstring("no upcoming briefing")))
+	})
+})
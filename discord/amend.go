@@ -0,0 +1,386 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/simgrid"
+)
+
+// announcementDocument is the state document recording the announcements
+// posted with one round's penalties, so they can be amended in place.
+func announcementDocument(season string, round config.Round) string {
+	return roundDocument("announcements", season, round)
+}
+
+// messageRef locates a posted message.
+type messageRef struct {
+	ChannelID snowflake.ID `yaml:"channel_id"`
+	MessageID snowflake.ID `yaml:"message_id"`
+}
+
+// postedAnnouncement records the messages and briefing doc showing the
+// penalties from RoundConfig.PreviousRound, and the config they show.
+type postedAnnouncement struct {
+	RoundConfig config.RoundConfig `yaml:"round_config"`
+	// Penalties is the !announce-penalties message.
+	Penalties *messageRef `yaml:"penalties,omitempty"`
+	// RaceDay is the race setup message, with its briefing doc and time.
+	RaceDay        *messageRef        `yaml:"race_day,omitempty"`
+	BriefingDocURL string             `yaml:"briefing_doc_url,omitempty"`
+	BriefingTime   time.Time          `yaml:"briefing_time,omitempty"`
+	Edits          []announcementEdit `yaml:"edits,omitempty"`
+}
+
+// announcementEdit is one amendment to a round's penalties.
+type announcementEdit struct {
+	Reason string       `yaml:"reason"`
+	By     snowflake.ID `yaml:"by"`
+	At     time.Time    `yaml:"at"`
+}
+
+// PenaltyAmendedDMData is the data model for penalty_amended_dm.tmpl.
+type PenaltyAmendedDMData struct {
+	// Round is the upcoming round where the penalties are served.
+	Round config.Round
+	// PreviousRound is the round whose stewarding produced the penalties.
+	PreviousRound config.Round
+	Driver        models.Driver
+	// Reason is why the stewards amended the penalties.
+	Reason string
+	// Added and Removed list the driver's penalties the amendment added and
+	// took away.
+	Added   []driverPenalty
+	Removed []driverPenalty
+	// TrackerURL links the previous round's penalty tracker.
+	TrackerURL string
+}
+
+// recordAnnouncement stores a posted announcement for the penalties in
+// roundConfig, letting update fill in what was posted. It returns a note for
+// the admin response; the announcement is already out, so failures don't
+// fail the command.
+func (d *DiscordClient) recordAnnouncement(roundConfig *config.RoundConfig, update func(*postedAnnouncement)) string {
+	if d.state.Dir() == "" {
+		return ""
+	}
+	var posted postedAnnouncement
+	err := d.state.Update(announcementDocument(d.snapshotConfig().Season, roundConfig.PreviousRound), &posted, func() error {
+		posted.RoundConfig = *roundConfig
+		update(&posted)
+		return nil
+	})
+	if err != nil {
+		return fmt.Sprintf("\n\nCould not record the announcement for `!amend-penalties`: %s", err)
+	}
+	return ""
+}
+
+func (d *DiscordClient) amendPenalties(event *events.MessageCreate, args []string) {
+	var msg, attachment string
+	defer func() { sendBotResponse(event, msg, attachment) }()
+	roundConfig, err := getRoundConfig(event)
+	if err != nil {
+		msg = err.Error()
+		return
+	}
+	sgClient := d.newSimGridClient()
+	gcClient, err := d.newGCloudClient()
+	if err != nil {
+		msg = err.Error()
+		return
+	}
+	msg, attachment, err = d.runAmendPenalties(event.Message.Author.ID, strings.Join(args, " "), roundConfig, sgClient, gcClient, time.Now())
+	if err != nil {
+		msg = err.Error()
+	}
+}
+
+// runAmendPenalties corrects the announced penalties from corrected's
+// previous round: it edits the posted announcements in place with an
+// "edited" footer, rewrites the briefing doc's penalties and re-attaches its
+// PDF, updates the stored round config, and DMs the drivers whose penalties
+// changed.
+func (d *DiscordClient) runAmendPenalties(adminID snowflake.ID, reason string, corrected *config.RoundConfig, sgClient *simgrid.SimGridClient, gcClient *gcloud.Client, now time.Time) (string, string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", "", fmt.Errorf("usage: `!amend-penalties <reason>`, attaching the corrected round penalty YAML")
+	}
+	conf := d.snapshotConfig()
+
+	var posted postedAnnouncement
+	if err := d.state.Load(announcementDocument(conf.Season, corrected.PreviousRound), &posted); err != nil {
+		return "", "", err
+	}
+	if posted.Penalties == nil && posted.RaceDay == nil {
+		return "", "", fmt.Errorf("no announcement of the penalties from %s was recorded, so there is nothing to amend", corrected.PreviousRound)
+	}
+
+	driverLookup, err := sgClient.BuildDriverLookup(conf.ChampionshipId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed building driver list: %w", err)
+	}
+	penalties, err := buildPenaltyList(driverLookup, corrected)
+	if err != nil {
+		return "", "", err
+	}
+	announced, err := buildPenaltyList(driverLookup, &posted.RoundConfig)
+	if err != nil {
+		return "", "", fmt.Errorf("failed building the announced penalties: %w", err)
+	}
+
+	posted.Edits = append(posted.Edits, announcementEdit{Reason: reason, By: adminID, At: now})
+	footer := editFooter(posted.Edits)
+
+	var edited []string
+	if posted.Penalties != nil {
+		msg, err := d.BuildPenaltyMessage(penalties, corrected)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to generate penalty message: %w", err)
+		}
+		if _, err := d.rest.UpdateMessage(posted.Penalties.ChannelID, posted.Penalties.MessageID, withEditFooter(msg, footer)); err != nil {
+			return "", "", fmt.Errorf("failed editing the penalty announcement: %w", err)
+		}
+		edited = append(edited, "the penalty announcement")
+	}
+	if posted.RaceDay != nil {
+		msg, err := d.BuildBriefingMessage(penalties, posted.BriefingDocURL, corrected, posted.BriefingTime)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to generate briefing message: %w", err)
+		}
+		if _, err := d.rest.UpdateMessage(posted.RaceDay.ChannelID, posted.RaceDay.MessageID, withEditFooter(msg, footer)); err != nil {
+			return "", "", fmt.Errorf("failed editing the race-day announcement: %w", err)
+		}
+		edited = append(edited, "the race-day announcement")
+	}
+
+	// The messages are edited; from here on failures are noted, not returned.
+	var notes []string
+	posted.RoundConfig = *corrected
+	if err := d.state.Save(announcementDocument(conf.Season, corrected.PreviousRound), &posted); err != nil {
+		notes = append(notes, fmt.Sprintf("Could not record the amendment: %s", err))
+	}
+
+	// Editing keeps the message pinned, unless an admin unpinned it since.
+	latest := posted.Penalties
+	if posted.RaceDay != nil {
+		latest = posted.RaceDay
+	}
+	if err := d.rest.PinMessage(latest.ChannelID, latest.MessageID); err != nil {
+		notes = append(notes, fmt.Sprintf("Could not pin the amended announcement: %s", err))
+	}
+
+	if posted.BriefingDocURL != "" {
+		if err := gcClient.UpdateBriefingPenalties(gcloud.BriefingDocID(posted.BriefingDocURL), penalties); err != nil {
+			notes = append(notes, fmt.Sprintf("Could not update the briefing doc: %s", err))
+		} else {
+			edited = append(edited, "the briefing doc")
+			if posted.RaceDay != nil {
+				if err := d.refreshBriefingPDF(gcClient, posted.BriefingDocURL, corrected.NextRound, posted.RaceDay); err != nil {
+					notes = append(notes, fmt.Sprintf("Could not refresh the briefing PDF: %s", err))
+				} else {
+					edited = append(edited, "the briefing PDF")
+				}
+			}
+		}
+	}
+
+	attachment, note := d.amendStoredRound(corrected, penalties)
+	if note != "" {
+		notes = append(notes, note)
+	}
+
+	notice := buildMessage(fmt.Sprintf("Penalties from %s were amended: %s", corrected.PreviousRound, reason))
+	if _, err := d.postToRoundThread(conf.Season, corrected.PreviousRound, threadDiscussion, notice); err != nil {
+		notes = append(notes, fmt.Sprintf("Could not post to the %s discussion thread: %s", corrected.PreviousRound, err))
+	}
+	if err := d.postToStewards(corrected.PreviousRound, fmt.Sprintf("<@%s> amended the penalties from %s: %s", adminID, corrected.PreviousRound, reason)); err != nil {
+		notes = append(notes, fmt.Sprintf("Could not post to the %s stewards thread: %s", corrected.PreviousRound, err))
+	}
+
+	changed := amendedPenalties(announced, penalties)
+	sent, failed, err := d.sendAmendedPenaltyDMs(changed, corrected, reason)
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("Failed sending penalty DMs: %s", err))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Ok, I have amended the penalties from %s in %s.", corrected.PreviousRound, strings.Join(edited, ", "))
+	if len(changed) == 0 {
+		fmt.Fprintf(&b, "\n\nNo driver's penalties changed.")
+	} else {
+		fmt.Fprintf(&b, "\n\n**Changed penalties**")
+		for _, c := range changed {
+			fmt.Fprintf(&b, "\n- #%d %s", c.driver.CarNumber, c.driver.Name())
+			if len(c.added) > 0 {
+				fmt.Fprintf(&b, ", added: %s", penaltyTitles(c.added))
+			}
+			if len(c.removed) > 0 {
+				fmt.Fprintf(&b, ", removed: %s", penaltyTitles(c.removed))
+			}
+		}
+		fmt.Fprintf(&b, "\n\n%s", penaltyDMReport(sent, failed))
+	}
+	for _, note := range notes {
+		fmt.Fprintf(&b, "\n\n%s", note)
+	}
+	return d.reportUnresolvedDrivers(b.String(), penalties, corrected.PreviousRound), attachment, nil
+}
+
+// amendStoredRound brings the stored round config in line with corrected.
+// Before race setup it is the announced config itself; after, it is the
+// following round's config, carrying the penalties over. It returns the
+// updated config's file when it changed, and a note when it could not be
+// updated.
+func (d *DiscordClient) amendStoredRound(corrected *config.RoundConfig, penalties *models.Penalties) (string, string) {
+	rc, err := d.loadCurrentRound()
+	if err != nil {
+		return "", ""
+	}
+	switch rc.PreviousRound.Number {
+	case corrected.PreviousRound.Number:
+		rc = corrected
+	case corrected.NextRound.Number:
+		rc.CarriedOverPenalties = penalties.Consolidate()
+	default:
+		return "", ""
+	}
+	if err := d.saveCurrentRound(rc); err != nil {
+		return "", fmt.Sprintf("Could not update the stored round config: %s", err)
+	}
+	attachment, err := writeNextRoundConfig(rc, d.snapshotConfig().Season)
+	if err != nil {
+		return "", fmt.Sprintf("The stored round config is updated, but I could not attach it: %s", err)
+	}
+	return attachment, ""
+}
+
+// editFooter renders the "edited" footer listing every amendment.
+func editFooter(edits []announcementEdit) string {
+	lines := make([]string, 0, len(edits))
+	for _, edit := range edits {
+		lines = append(lines, fmt.Sprintf("-# ✏️ Edited <t:%d:f>: %s", edit.At.Unix(), edit.Reason))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// withEditFooter turns a freshly built announcement into an edit of the
+// posted one, with footer appended to its content.
+func withEditFooter(msg discord.MessageCreate, footer string) discord.MessageUpdate {
+	content := strings.TrimRight(msg.Content, "\n") + "\n\n" + footer
+	embeds := msg.Embeds
+	if embeds == nil {
+		embeds = []discord.Embed{}
+	}
+	components := msg.Components
+	if components == nil {
+		components = []discord.LayoutComponent{}
+	}
+	return discord.MessageUpdate{Content: &content, Embeds: &embeds, Components: &components}
+}
+
+// penaltyChange is how an amendment changed one driver's penalties.
+type penaltyChange struct {
+	driver  models.Driver
+	added   []driverPenalty
+	removed []driverPenalty
+}
+
+// amendedPenalties compares each driver's penalties before and after an
+// amendment, returning the drivers whose penalties changed by car number.
+func amendedPenalties(before, after *models.Penalties) []penaltyChange {
+	beforeByCar, afterByCar := penaltiesByCar(before), penaltiesByCar(after)
+	drivers := map[int]models.Driver{}
+	for _, driver := range append(before.UniqueDrivers(), after.UniqueDrivers()...) {
+		drivers[driver.CarNumber] = driver
+	}
+
+	var changes []penaltyChange
+	for car, driver := range drivers {
+		change := penaltyChange{
+			driver:  driver,
+			added:   missingPenalties(afterByCar[car], beforeByCar[car]),
+			removed: missingPenalties(beforeByCar[car], afterByCar[car]),
+		}
+		if len(change.added)+len(change.removed) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].driver.CarNumber < changes[j].driver.CarNumber })
+	return changes
+}
+
+// missingPenalties returns the penalties in from that are not in other.
+func missingPenalties(from, other []driverPenalty) []driverPenalty {
+	var missing []driverPenalty
+	for _, p := range from {
+		if !slices.Contains(other, p) {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+func penaltyTitles(penalties []driverPenalty) string {
+	titles := make([]string, 0, len(penalties))
+	for _, p := range penalties {
+		title := p.Title
+		if p.CarriedOver {
+			title += " (carried over)"
+		}
+		titles = append(titles, title)
+	}
+	return strings.Join(titles, ", ")
+}
+
+// sendAmendedPenaltyDMs tells each driver whose penalties changed what was
+// added and removed, the same way sendPenaltyDMs announces them.
+func (d *DiscordClient) sendAmendedPenaltyDMs(changes []penaltyChange, roundConfig *config.RoundConfig, reason string) (int, []string, error) {
+	sent := 0
+	var failed []string
+	for _, change := range changes {
+		userID, err := d.getDriverId(change.driver)
+		if err != nil {
+			if errors.Is(err, DiscordHandleNotFoundError{}) {
+				continue
+			}
+			return sent, failed, err
+		}
+		optedOut, err := d.penaltyDMsOptedOut(userID)
+		if err != nil {
+			return sent, failed, err
+		}
+		if optedOut {
+			continue
+		}
+
+		content, err := d.messages.render(templatePenaltyAmendedDM, PenaltyAmendedDMData{
+			Round:         roundConfig.NextRound,
+			PreviousRound: roundConfig.PreviousRound,
+			Driver:        change.driver,
+			Reason:        reason,
+			Added:         change.added,
+			Removed:       change.removed,
+			TrackerURL:    roundConfig.PreviousRound.PenaltyTrackerLink,
+		})
+		if err != nil {
+			return sent, failed, err
+		}
+		if err := d.sendDM(userID, discord.MessageCreate{Content: content}); err != nil {
+			failed = append(failed, fmt.Sprintf("#%d %s (<@%s>): %s", change.driver.CarNumber, change.driver.Name(), userID, err))
+			continue
+		}
+		sent++
+	}
+	return sent, failed, nil
+}
@@ -0,0 +1,285 @@
+package discord
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	"github.com/geofffranks/rookies-bot/gcloud/fakes"
+	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/docs/v1"
+)
+
+var _ = Describe("amending penalties", func() {
+	var (
+		client     *DiscordClient
+		stub       *stubRest
+		stateDir   string
+		sgServer   *httptest.Server
+		sgClient   *simgrid.SimGridClient
+		gcClient   *gcloud.Client
+		fakeDocs   *fakes.FakeDocsServicer
+		fakeDrive  *fakes.FakeDriveServicer
+		announced  *config.RoundConfig
+		corrected  *config.RoundConfig
+		updates    map[snowflake.ID]dgo.MessageUpdate
+		reattached map[snowflake.ID][]string
+		posted     map[snowflake.ID][]string
+		pinned     []snowflake.ID
+		now        time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = os.MkdirTemp("", "rookies-bot-amend")
+		Expect(err).NotTo(HaveOccurred())
+
+		updates = map[snowflake.ID]dgo.MessageUpdate{}
+		reattached = map[snowflake.ID][]string{}
+		posted = map[snowflake.ID][]string{}
+		pinned = nil
+		nextMessage := uint64(600)
+		stub = &stubRest{
+			getMembersFn: func(guildID snowflake.ID, limit int, after snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Member, error) {
+				return pagedMembers(
+					dgo.Member{User: dgo.User{ID: snowflakeID(42), Username: "max.verstappen"}},
+					dgo.Member{User: dgo.User{ID: snowflakeID(43), Username: "lando.norris"}},
+				)(after)
+			},
+			getRolesFn: func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+				return []dgo.Role{{Name: "Rookies", ID: snowflakeID(777)}}, nil
+			},
+			createDMChannelFn: func(userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.DMChannel, error) {
+				return dmChannel(userID + 1000), nil
+			},
+			createMessageFn: func(channelID snowflake.ID, m dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+				posted[channelID] = append(posted[channelID], m.Content)
+				nextMessage++
+				return &dgo.Message{ID: snowflakeID(nextMessage)}, nil
+			},
+			updateMessageFn: func(channelID snowflake.ID, messageID snowflake.ID, u dgo.MessageUpdate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+				Expect(channelID).To(Equal(snowflakeID(111)))
+				if u.Files != nil {
+					Expect(*u.Attachments).To(BeEmpty())
+					for _, f := range u.Files {
+						reattached[messageID] = append(reattached[messageID], f.Name)
+					}
+					return &dgo.Message{ID: messageID}, nil
+				}
+				updates[messageID] = u
+				return &dgo.Message{ID: messageID}, nil
+			},
+			pinMessageFn: func(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error {
+				pinned = append(pinned, messageID)
+				return nil
+			},
+		}
+		client = NewTestDiscordClient(stub, snowflakeID(1), &config.Config{BotConfig: config.BotConfig{
+			Season:           "2026 Fall",
+			StateDir:         stateDir,
+			DiscordChannelId: snowflakeID(111),
+			DiscordRoleName:  "Rookies",
+		}}, nil)
+
+		sgServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(r.URL.Path, "entrylist") {
+				_, _ = w.Write([]byte(`{"entries":[
+					{"drivers":[{"firstName":"Max","lastName":"Verstappen","playerId":"S111"}],"raceNumber":1},
+					{"drivers":[{"firstName":"Lando","lastName":"Norris","playerId":"S222"}],"raceNumber":4}]}`))
+			} else {
+				_, _ = w.Write([]byte(`[
+					{"steam64_id":"111","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"},
+					{"steam64_id":"222","username":"lando.norris","first_name":"Lando","last_name":"Norris"}]`))
+			}
+		}))
+		sgClient = simgrid.NewClient("test-token")
+		sgClient.BaseURL = sgServer.URL
+
+		fakeDocs = &fakes.FakeDocsServicer{}
+		fakeDocs.GetDocumentReturns(&docs.Document{Body: &docs.Body{Content: []*docs.StructuralElement{
+			{StartIndex: 10, Paragraph: &docs.Paragraph{
+				ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "HEADING_3"},
+				Elements:       []*docs.ParagraphElement{{TextRun: &docs.TextRun{Content: "Drivers Serving Penalties Tonight\n"}}},
+			}},
+			{StartIndex: 80, Paragraph: &docs.Paragraph{
+				ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "HEADING_3"},
+				Elements:       []*docs.ParagraphElement{{TextRun: &docs.TextRun{Content: "Stream\n"}}},
+			}},
+		}}}, nil)
+		fakeDrive = &fakes.FakeDriveServicer{}
+		fakeDrive.ExportFileReturns([]byte("%PDF-amended"), nil)
+		gcClient = &gcloud.Client{Docs: fakeDocs, Drive: fakeDrive}
+
+		announced = &config.RoundConfig{
+			PreviousRound: config.Round{Number: 4, Track: "Monza"},
+			NextRound:     config.Round{Number: 5, Track: "Imola"},
+			Penalties:     config.Penalty{QualiBansR1: []int{1}},
+		}
+		corrected = &config.RoundConfig{
+			PreviousRound: announced.PreviousRound,
+			NextRound:     announced.NextRound,
+			Penalties:     config.Penalty{PitStartsR1: []int{4}},
+		}
+		now = time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+		_, _, err = client.runAnnouncePenalties(announced, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		posted = map[snowflake.ID][]string{}
+		pinned = nil
+	})
+
+	AfterEach(func() {
+		sgServer.Close()
+		os.RemoveAll(stateDir)
+	})
+
+	amend := func(reason string) string {
+		msg, attachment, err := client.runAmendPenalties(snowflakeID(9), reason, corrected, sgClient, gcClient, now)
+		Expect(err).NotTo(HaveOccurred())
+		if attachment != "" {
+			Expect(os.Remove(attachment)).To(Succeed())
+		}
+		return msg
+	}
+
+	It("edits the penalty announcement in place with an edited footer", func() {
+		msg := amend("contact in turn 1 was a racing incident")
+		Expect(msg).To(ContainSubstring("Ok, I have amended the penalties from Round 4 - Monza in the penalty announcement."))
+
+		Expect(updates).To(HaveKey(snowflakeID(601)))
+		content := *updates[snowflakeID(601)].Content
+		Expect(content).To(ContainSubstring("<@43>"))
+		Expect(content).NotTo(ContainSubstring("<@42>"))
+		Expect(content).To(HaveSuffix("\n\n-# ✏️ Edited <t:1773144000:f>: contact in turn 1 was a racing incident"))
+		Expect(pinned).To(ContainElement(snowflakeID(601)))
+
+		now = now.Add(time.Hour)
+		amend("typo")
+		Expect(*updates[snowflakeID(601)].Content).To(HaveSuffix("racing incident\n-# ✏️ Edited <t:1773147600:f>: typo"))
+	})
+
+	It("DMs the drivers whose penalties changed", func() {
+		msg := amend("contact in turn 1 was a racing incident")
+		Expect(msg).To(ContainSubstring("- #1 Max Verstappen, removed: Quali Bans R1"))
+		Expect(msg).To(ContainSubstring("- #4 Lando Norris, added: Pit Starts R1"))
+		Expect(msg).To(ContainSubstring("I DMed 2 penalized driver(s)"))
+
+		Expect(posted[snowflakeID(1042)]).To(HaveLen(1))
+		Expect(posted[snowflakeID(1042)][0]).To(ContainSubstring("no longer serve at Round 5 - Imola:\n- Quali Bans R1"))
+		Expect(posted[snowflakeID(1043)][0]).To(ContainSubstring("now also serve at Round 5 - Imola:\n- Pit Starts R1"))
+		Expect(posted[snowflakeID(1043)][0]).To(ContainSubstring("amended the penalties from Round 4 - Monza: contact in turn 1 was a racing incident"))
+	})
+
+	It("gives the reason in the round's stewards thread", func() {
+		Expect(client.state.Save(threadsDocument, &threadIndex{Rounds: map[string]*roundThreads{
+			roundKey("2026 Fall", announced.PreviousRound): {DiscussionID: snowflakeID(801), StewardsID: snowflakeID(802)},
+		}})).To(Succeed())
+
+		amend("contact in turn 1 was a racing incident")
+		Expect(posted[snowflakeID(801)]).To(Equal([]string{"Penalties from Round 4 - Monza were amended: contact in turn 1 was a racing incident"}))
+		Expect(posted[snowflakeID(802)]).To(Equal([]string{"<@9> amended the penalties from Round 4 - Monza: contact in turn 1 was a racing incident"}))
+	})
+
+	It("updates the stored round config before race setup", func() {
+		amend("fix")
+		stored, err := client.loadCurrentRound()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Penalties.PitStartsR1).To(Equal([]int{4}))
+		Expect(stored.Penalties.QualiBansR1).To(BeEmpty())
+	})
+
+	It("also edits the race-day announcement, briefing doc and PDF, and carried-over penalties after race setup", func() {
+		briefing := time.Date(2026, time.March, 16, 23, 30, 0, 0, time.UTC)
+		client.recordAnnouncement(announced, func(p *postedAnnouncement) {
+			p.RaceDay = &messageRef{ChannelID: snowflakeID(111), MessageID: snowflakeID(700)}
+			p.BriefingDocURL = "https://docs.google.com/document/d/briefing-id"
+			p.BriefingTime = briefing
+		})
+		Expect(client.saveCurrentRound(&config.RoundConfig{
+			PreviousRound:        announced.NextRound,
+			NextRound:            config.Round{Number: 6, Track: "Spa"},
+			CarriedOverPenalties: config.Penalty{QualiBansR1: []int{1}},
+		})).To(Succeed())
+
+		msg := amend("fix")
+		Expect(msg).To(ContainSubstring("in the penalty announcement, the race-day announcement, the briefing doc, the briefing PDF."))
+		Expect(*updates[snowflakeID(700)].Content).To(ContainSubstring("<@43>"))
+		Expect(pinned).To(Equal([]snowflake.ID{snowflakeID(700)}))
+
+		Expect(fakeDocs.BatchUpdateDocumentCallCount()).To(Equal(1))
+		_, docID, _ := fakeDocs.BatchUpdateDocumentArgsForCall(0)
+		Expect(docID).To(Equal("briefing-id"))
+
+		Expect(reattached[snowflakeID(700)]).To(Equal([]string{"Drivers Briefing Round 5.pdf"}))
+		_, id, _ := fakeDrive.ExportFileArgsForCall(0)
+		Expect(id).To(Equal("briefing-id"))
+		pdf, err := os.ReadFile(filepath.Join(stateDir, "briefings", "2026-fall", "round-5.pdf"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(pdf)).To(Equal("%PDF-amended"))
+
+		stored, err := client.loadCurrentRound()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.NextRound.Number).To(Equal(6))
+		Expect(stored.CarriedOverPenalties.PitStartsR1).To(Equal([]int{4}))
+		Expect(stored.CarriedOverPenalties.QualiBansR1).To(BeEmpty())
+	})
+
+	It("notes when the briefing PDF can't be refreshed", func() {
+		client.recordAnnouncement(announced, func(p *postedAnnouncement) {
+			p.RaceDay = &messageRef{ChannelID: snowflakeID(111), MessageID: snowflakeID(700)}
+			p.BriefingDocURL = "https://docs.google.com/document/d/briefing-id"
+		})
+		fakeDrive.ExportFileReturns(nil, errors.New("export size limit exceeded"))
+
+		msg := amend("fix")
+		Expect(msg).To(ContainSubstring("the briefing doc."))
+		Expect(msg).To(ContainSubstring("Could not refresh the briefing PDF: failed exporting the Briefing Doc as PDF: export size limit exceeded"))
+		Expect(reattached).To(BeEmpty())
+	})
+
+	It("notes follow-up failures once the announcements are edited", func() {
+		stub.pinMessageFn = func(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error {
+			return errors.New("missing permissions")
+		}
+		msg := amend("fix")
+		Expect(msg).To(ContainSubstring("Could not pin the amended announcement: missing permissions"))
+	})
+
+	It("requires a reason and a recorded announcement", func() {
+		_, _, err := client.runAmendPenalties(snowflakeID(9), " ", corrected, sgClient, gcClient, now)
+		Expect(err).To(MatchError(ContainSubstring("usage")))
+
+		corrected.PreviousRound = config.Round{Number: 3, Track: "Spa"}
+		_, _, err = client.runAmendPenalties(snowflakeID(9), "fix", corrected, sgClient, gcClient, now)
+		Expect(err).To(MatchError("no announcement of the penalties from Round 3 - Spa was recorded, so there is nothing to amend"))
+	})
+})
+
+var _ = Describe("amendedPenalties", func() {
+	It("lists what each driver gained and lost", func() {
+		max := models.Driver{CarNumber: 1, FirstName: "Max"}
+		lando := models.Driver{CarNumber: 4, FirstName: "Lando"}
+		changes := amendedPenalties(
+			&models.Penalties{QualiBansR1: []models.Driver{max}, PitStartsR2CarriedOver: []models.Driver{lando}},
+			&models.Penalties{QualiBansR1: []models.Driver{max}, PitStartsR2: []models.Driver{lando}},
+		)
+		Expect(changes).To(Equal([]penaltyChange{{
+			driver:  lando,
+			added:   []driverPenalty{{Title: "Pit Starts R2"}},
+			removed: []driverPenalty{{Title: "Pit Starts R2", CarriedOver: true}},
+		}}))
+	})
+})
@@ -0,0 +1,449 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/simgrid"
+)
+
+// attendanceDocument is the state document holding the briefing whose
+// attendance is being tracked.
+const attendanceDocument = "attendance.yml"
+
+// attendanceEarlyJoin is how long before the briefing starts joins are
+// recorded, so drivers who arrive early count from the start.
+const attendanceEarlyJoin = 15 * time.Minute
+
+// attendancePollInterval is how often the attendance loop checks whether the
+// tracked briefing has ended.
+const attendancePollInterval = time.Minute
+
+// attendanceReportDocument is the state document holding the attendance
+// report for one round, e.g. "attendance/2026-fall/round-4.yml".
+func attendanceReportDocument(season string, round config.Round) string {
+	return roundDocument("attendance", season, round)
+}
+
+// briefingSession is a briefing being tracked. Joins by season role members
+// are recorded from attendanceEarlyJoin before Start until End, and only
+// time spent between Start and End counts.
+type briefingSession struct {
+	Season string       `yaml:"season"`
+	Round  config.Round `yaml:"round"`
+	Start  time.Time    `yaml:"start"`
+	End    time.Time    `yaml:"end"`
+	RoleID snowflake.ID `yaml:"role_id"`
+	// Seeded is set once members already in the stage when tracking opened
+	// have been recorded.
+	Seeded bool `yaml:"seeded"`
+	// Attendees is keyed by Discord user ID.
+	Attendees map[string]attendee `yaml:"attendees"`
+}
+
+type attendee struct {
+	// JoinedAt is set while the member is in the stage.
+	JoinedAt time.Time     `yaml:"joined_at,omitempty"`
+	Duration time.Duration `yaml:"duration"`
+}
+
+type attendanceTracker struct {
+	Session *briefingSession `yaml:"session"`
+}
+
+// AttendanceReport is the persisted briefing attendance for a round.
+type AttendanceReport struct {
+	Season string       `yaml:"season"`
+	Round  config.Round `yaml:"round"`
+	Start  time.Time    `yaml:"start"`
+	End    time.Time    `yaml:"end"`
+	// Attended lists registered drivers who were in the stage, with how long.
+	Attended []AttendanceEntry `yaml:"attended"`
+	// Absent lists registered drivers who never joined, including drivers
+	// the bot cannot map to a Discord member.
+	Absent []AttendanceEntry `yaml:"absent"`
+	// NotEntered lists season role members who attended but are not in the
+	// SimGrid entry list.
+	NotEntered []AttendanceEntry `yaml:"not_entered,omitempty"`
+}
+
+// AttendanceEntry is one driver or member in an AttendanceReport. DiscordID
+// is zero for a driver with no known Discord account; CarNumber is zero for a
+// member who is not entered.
+type AttendanceEntry struct {
+	CarNumber int           `yaml:"car_number,omitempty"`
+	Name      string        `yaml:"name,omitempty"`
+	DiscordID snowflake.ID  `yaml:"discord_id,omitempty"`
+	Duration  time.Duration `yaml:"duration,omitempty"`
+}
+
+// trackAttendance starts tracking the briefing for the round being set up,
+// replacing any briefing still being tracked. It returns a note for the admin
+// response when tracking could not start.
+func (d *DiscordClient) trackAttendance(roundConfig *config.RoundConfig, briefingTime time.Time) string {
+	conf := d.snapshotConfig()
+	if conf.DiscordBriefingChannelId == 0 {
+		return ""
+	}
+	end, err := conf.Schedule.RaceTimeFor(briefingTime)
+	if err != nil {
+		return fmt.Sprintf("\n\nCould not track briefing attendance: %s", err)
+	}
+	role, err := d.lookupRole(conf.DiscordRoleName)
+	if err != nil {
+		return fmt.Sprintf("\n\nCould not track briefing attendance: %s", err)
+	}
+
+	session := &briefingSession{
+		Season:    conf.Season,
+		Round:     roundConfig.NextRound,
+		Start:     briefingTime,
+		End:       end,
+		RoleID:    role.ID,
+		Attendees: map[string]attendee{},
+	}
+	if err := d.state.Save(attendanceDocument, &attendanceTracker{Session: session}); err != nil {
+		return fmt.Sprintf("\n\nCould not track briefing attendance: %s", err)
+	}
+	return ""
+}
+
+// stopTrackingAttendance drops the tracked briefing if it is the round's,
+// reporting whether it was.
+func (d *DiscordClient) stopTrackingAttendance(season string, round config.Round) (bool, error) {
+	var tracker attendanceTracker
+	if err := d.state.Load(attendanceDocument, &tracker); err != nil {
+		return false, err
+	}
+	session := tracker.Session
+	if session == nil || session.Season != season || session.Round.Number != round.Number {
+		return false, nil
+	}
+	return true, d.state.Delete(attendanceDocument)
+}
+
+func (d *DiscordClient) onGuildVoiceStateUpdate(event *events.GuildVoiceStateUpdate) {
+	now := time.Now()
+	for _, league := range d.leagueClients() {
+		err := league.recordVoiceState(event.VoiceState, event.Member.RoleIDs, now)
+		if err != nil {
+			fmt.Printf("Error recording briefing attendance: %s\n", err)
+		}
+	}
+}
+
+// recordVoiceState updates the tracked briefing when a member joins or
+// leaves the briefing stage. Members without the season role are ignored.
+func (d *DiscordClient) recordVoiceState(state discord.VoiceState, roleIDs []snowflake.ID, now time.Time) error {
+	var tracker attendanceTracker
+	if err := d.state.Load(attendanceDocument, &tracker); err != nil {
+		return err
+	}
+	if !tracker.Session.open(now) {
+		return nil
+	}
+
+	channel := d.snapshotConfig().DiscordBriefingChannelId
+	inStage := state.ChannelID != nil && *state.ChannelID == channel
+	return d.state.Update(attendanceDocument, &tracker, func() error {
+		session := tracker.Session
+		if !session.open(now) {
+			return nil
+		}
+		key := state.UserID.String()
+		a, tracked := session.Attendees[key]
+		switch {
+		case inStage && a.JoinedAt.IsZero():
+			if !tracked && !hasRole(roleIDs, session.RoleID) {
+				return nil
+			}
+			a.JoinedAt = now
+		case !inStage && !a.JoinedAt.IsZero():
+			a.Duration += session.overlap(a.JoinedAt, now)
+			a.JoinedAt = time.Time{}
+		default:
+			return nil
+		}
+		if session.Attendees == nil {
+			session.Attendees = map[string]attendee{}
+		}
+		session.Attendees[key] = a
+		return nil
+	})
+}
+
+// open reports whether joins are being recorded at now.
+func (s *briefingSession) open(now time.Time) bool {
+	return s != nil && !now.Before(s.Start.Add(-attendanceEarlyJoin)) && now.Before(s.End)
+}
+
+// overlap returns how much of from..to falls within the briefing.
+func (s *briefingSession) overlap(from, to time.Time) time.Duration {
+	if from.Before(s.Start) {
+		from = s.Start
+	}
+	if to.After(s.End) {
+		to = s.End
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+func hasRole(roleIDs []snowflake.ID, role snowflake.ID) bool {
+	for _, id := range roleIDs {
+		if id == role {
+			return true
+		}
+	}
+	return false
+}
+
+// seedAttendance records season role members already in the stage when
+// tracking opens, since they will not send a voice state update until they
+// leave. It needs the gateway's voice state cache, so it does nothing without
+// a gateway connection.
+func (d *DiscordClient) seedAttendance(now time.Time) error {
+	var tracker attendanceTracker
+	if err := d.state.Load(attendanceDocument, &tracker); err != nil {
+		return err
+	}
+	if !tracker.Session.open(now) || tracker.Session.Seeded || d.botClient == nil {
+		return nil
+	}
+	guildID, err := d.getGuild()
+	if err != nil {
+		return err
+	}
+
+	channel := d.snapshotConfig().DiscordBriefingChannelId
+	for state := range d.botClient.Caches.VoiceStates(guildID) {
+		if state.ChannelID == nil || *state.ChannelID != channel {
+			continue
+		}
+		member, ok := d.botClient.Caches.Member(guildID, state.UserID)
+		if !ok {
+			continue
+		}
+		if err := d.recordVoiceState(state, member.RoleIDs, now); err != nil {
+			return err
+		}
+	}
+	return d.state.Update(attendanceDocument, &tracker, func() error {
+		if tracker.Session != nil {
+			tracker.Session.Seeded = true
+		}
+		return nil
+	})
+}
+
+// closeAttendance finishes the tracked briefing once it has ended: it maps
+// attendees to registered drivers, stores the report for the round, and
+// returns it formatted for the admins. It returns "" while no briefing has
+// ended.
+func (d *DiscordClient) closeAttendance(now time.Time, sgClient *simgrid.SimGridClient) (string, error) {
+	var tracker attendanceTracker
+	if err := d.state.Load(attendanceDocument, &tracker); err != nil {
+		return "", err
+	}
+	session := tracker.Session
+	if session == nil || now.Before(session.End) {
+		return "", nil
+	}
+
+	driverLookup, err := sgClient.BuildDriverLookup(d.snapshotConfig().ChampionshipId)
+	if err != nil {
+		return "", fmt.Errorf("failed loading drivers for the %s attendance report: %w", session.Round, err)
+	}
+	report, err := d.buildAttendanceReport(session, driverLookup)
+	if err != nil {
+		return "", err
+	}
+	if err := d.state.Save(attendanceReportDocument(session.Season, session.Round), report); err != nil {
+		return "", err
+	}
+	if err := d.state.Delete(attendanceDocument); err != nil {
+		return "", err
+	}
+	return formatAttendanceReport(report), nil
+}
+
+func (d *DiscordClient) buildAttendanceReport(session *briefingSession, driverLookup models.DriverLookup) (*AttendanceReport, error) {
+	durations := map[snowflake.ID]time.Duration{}
+	for key, a := range session.Attendees {
+		id, err := snowflake.Parse(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attendee %q: %w", key, err)
+		}
+		if !a.JoinedAt.IsZero() {
+			a.Duration += session.overlap(a.JoinedAt, session.End)
+		}
+		if a.Duration > 0 {
+			durations[id] = a.Duration
+		}
+	}
+
+	report := &AttendanceReport{Season: session.Season, Round: session.Round, Start: session.Start, End: session.End}
+	matched := map[snowflake.ID]bool{}
+	for _, driver := range driverLookup {
+		entry := AttendanceEntry{CarNumber: driver.CarNumber, Name: driver.Name()}
+		id, err := d.getDriverId(driver)
+		if err != nil && !errors.Is(err, DiscordHandleNotFoundError{}) {
+			return nil, err
+		}
+		if err == nil {
+			entry.DiscordID = id
+		}
+		if duration, ok := durations[entry.DiscordID]; ok && entry.DiscordID != 0 {
+			entry.Duration = duration
+			matched[id] = true
+			report.Attended = append(report.Attended, entry)
+			continue
+		}
+		report.Absent = append(report.Absent, entry)
+	}
+	for id, duration := range durations {
+		if !matched[id] {
+			report.NotEntered = append(report.NotEntered, AttendanceEntry{DiscordID: id, Duration: duration})
+		}
+	}
+
+	for _, entries := range [][]AttendanceEntry{report.Attended, report.Absent} {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].CarNumber < entries[j].CarNumber })
+	}
+	sort.Slice(report.NotEntered, func(i, j int) bool { return report.NotEntered[i].DiscordID < report.NotEntered[j].DiscordID })
+	return report, nil
+}
+
+func formatAttendanceReport(report *AttendanceReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📋 **Briefing attendance for %s**: %d of %d drivers\n",
+		report.Round, len(report.Attended), len(report.Attended)+len(report.Absent))
+
+	fmt.Fprintf(&b, "\n**Attended**\n")
+	if len(report.Attended) == 0 {
+		fmt.Fprintf(&b, "- Nobody\n")
+	}
+	for _, entry := range report.Attended {
+		fmt.Fprintf(&b, "- #%d %s (<@%s>): %s\n", entry.CarNumber, entry.Name, entry.DiscordID, entry.Duration.Round(time.Minute))
+	}
+
+	fmt.Fprintf(&b, "\n**Absent**\n")
+	if len(report.Absent) == 0 {
+		fmt.Fprintf(&b, "- Nobody!\n")
+	}
+	for _, entry := range report.Absent {
+		if entry.DiscordID == 0 {
+			fmt.Fprintf(&b, "- #%d %s (no Discord account found, see `!unlinked`)\n", entry.CarNumber, entry.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "- #%d %s (<@%s>)\n", entry.CarNumber, entry.Name, entry.DiscordID)
+	}
+
+	if len(report.NotEntered) > 0 {
+		fmt.Fprintf(&b, "\n**Attended but not entered**\n")
+		for _, entry := range report.NotEntered {
+			fmt.Fprintf(&b, "- <@%s>: %s\n", entry.DiscordID, entry.Duration.Round(time.Minute))
+		}
+	}
+	return b.String()
+}
+
+// loadAttendanceReport returns the stored attendance report for round of
+// season, or nil when none was recorded.
+func (d *DiscordClient) loadAttendanceReport(season string, round config.Round) (*AttendanceReport, error) {
+	var report *AttendanceReport
+	if err := d.state.Load(attendanceReportDocument(season, round), &report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// watchAttendance seeds and closes tracked briefings until ctx is cancelled,
+// posting each finished report to the admins.
+func (d *DiscordClient) watchAttendance(ctx context.Context) {
+	ticker := time.NewTicker(attendancePollInterval)
+	defer ticker.Stop()
+	var watch attendanceWatch
+	for {
+		d.pollAttendance(time.Now(), d.newSimGridClient().WithContext(ctx), &watch)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// attendanceWatch remembers the failures watchAttendance last reported, so
+// one that persists (a missing stage channel, SimGrid being down) is
+// reported once rather than on every poll.
+type attendanceWatch struct {
+	seedErr  string
+	closeErr string
+}
+
+// pollAttendance seeds and closes tracked briefings once, reporting finished
+// reports and new failures to the admins.
+func (d *DiscordClient) pollAttendance(now time.Time, sgClient *simgrid.SimGridClient, watch *attendanceWatch) {
+	d.reportChangedError(&watch.seedErr, "📋 Failed recording briefing attendance", d.seedAttendance(now))
+	report, err := d.closeAttendance(now, sgClient)
+	d.reportChangedError(&watch.closeErr, "📋 Failed finishing briefing attendance", err)
+	if err == nil && report != "" {
+		d.reportToAdmins(report)
+	}
+}
+
+// reportChangedError reports err to the admins unless it is the failure last
+// reported in *last, and remembers it. A nil err clears *last, so the next
+// failure is reported again.
+func (d *DiscordClient) reportChangedError(last *string, prefix string, err error) {
+	if err == nil {
+		*last = ""
+		return
+	}
+	if err.Error() == *last {
+		return
+	}
+	*last = err.Error()
+	d.reportToAdmins(fmt.Sprintf("%s: %s", prefix, err))
+}
+
+func (d *DiscordClient) attendance(event *events.MessageCreate, args []string) {
+	msg, err := d.runAttendance(args)
+	if err != nil {
+		msg = err.Error()
+	}
+	sendBotResponse(event, msg, "")
+}
+
+// runAttendance shows the stored attendance report for a round of the
+// current season.
+func (d *DiscordClient) runAttendance(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: `!attendance <round number>`")
+	}
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid round number %q", args[0])
+	}
+	report, err := d.loadAttendanceReport(d.snapshotConfig().Season, config.Round{Number: number})
+	if err != nil {
+		return "", err
+	}
+	if report == nil {
+		return fmt.Sprintf("No briefing attendance was recorded for round %d.", number), nil
+	}
+	return formatAttendanceReport(report), nil
+}
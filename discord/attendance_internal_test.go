@@ -0,0 +1,199 @@
+package discord
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("briefing attendance", func() {
+	var (
+		client   *DiscordClient
+		stub     *stubRest
+		stateDir string
+		sgServer *httptest.Server
+		sgClient *simgrid.SimGridClient
+		rc       *config.RoundConfig
+		briefing time.Time
+		stage    snowflake.ID
+		role     snowflake.ID
+	)
+
+	voiceState := func(user uint64, channel *snowflake.ID) dgo.VoiceState {
+		return dgo.VoiceState{UserID: snowflakeID(user), ChannelID: channel}
+	}
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = os.MkdirTemp("", "rookies-bot-attendance")
+		Expect(err).NotTo(HaveOccurred())
+
+		stage = snowflakeID(500)
+		role = snowflakeID(777)
+		stub = &stubRest{
+			getRolesFn: func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+				return []dgo.Role{{Name: "Rookies", ID: role}}, nil
+			},
+			getMembersFn: func(guildID snowflake.ID, limit int, after snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Member, error) {
+				return pagedMembers(
+					dgo.Member{User: dgo.User{ID: snowflakeID(42), Username: "max.verstappen"}},
+					dgo.Member{User: dgo.User{ID: snowflakeID(43), Username: "lando.norris"}},
+				)(after)
+			},
+		}
+		conf := &config.Config{BotConfig: config.BotConfig{
+			Season:                   "2026 Fall",
+			StateDir:                 stateDir,
+			DiscordRoleName:          "Rookies",
+			DiscordBriefingChannelId: stage,
+		}}
+		client = NewTestDiscordClient(stub, snowflakeID(1), conf, nil)
+
+		sgServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(r.URL.Path, "entrylist") {
+				_, _ = w.Write([]byte(`{"entries":[
+					{"drivers":[{"firstName":"Max","lastName":"Verstappen","playerId":"S111"}],"raceNumber":1},
+					{"drivers":[{"firstName":"Lando","lastName":"Norris","playerId":"S222"}],"raceNumber":4},
+					{"drivers":[{"firstName":"Oscar","lastName":"Piastri","playerId":"S333"}],"raceNumber":81}]}`))
+			} else {
+				_, _ = w.Write([]byte(`[
+					{"steam64_id":"111","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"},
+					{"steam64_id":"222","username":"lando.norris","first_name":"Lando","last_name":"Norris"},
+					{"steam64_id":"333","username":"oscar.piastri","first_name":"Oscar","last_name":"Piastri"}]`))
+			}
+		}))
+		sgClient = simgrid.NewClient("test-token")
+		sgClient.BaseURL = sgServer.URL
+
+		rc = &config.RoundConfig{
+			PreviousRound: config.Round{Number: 3, Track: "Spa"},
+			NextRound:     config.Round{Number: 4, Track: "Monza"},
+		}
+		briefing = time.Date(2026, time.March, 9, 23, 30, 0, 0, time.UTC)
+		Expect(client.trackAttendance(rc, briefing)).To(BeEmpty())
+	})
+
+	AfterEach(func() {
+		sgServer.Close()
+		os.RemoveAll(stateDir)
+	})
+
+	session := func() *briefingSession {
+		var tracker attendanceTracker
+		Expect(client.state.Load(attendanceDocument, &tracker)).To(Succeed())
+		return tracker.Session
+	}
+
+	It("tracks the briefing until racing starts", func() {
+		s := session()
+		Expect(s.Round).To(Equal(rc.NextRound))
+		Expect(s.Start).To(BeTemporally("==", briefing))
+		Expect(s.End).To(BeTemporally("==", briefing.Add(config.DefaultRaceDelay)))
+		Expect(s.RoleID).To(Equal(role))
+	})
+
+	It("does nothing without a briefing channel", func() {
+		client.conf.DiscordBriefingChannelId = 0
+		Expect(client.state.Delete(attendanceDocument)).To(Succeed())
+		Expect(client.trackAttendance(rc, briefing)).To(BeEmpty())
+		Expect(session()).To(BeNil())
+	})
+
+	It("counts only time in the stage during the briefing", func() {
+		roles := []snowflake.ID{role}
+		// Joins early, counted from the start.
+		Expect(client.recordVoiceState(voiceState(42, &stage), roles, briefing.Add(-10*time.Minute))).To(Succeed())
+		// Steps out for five minutes.
+		Expect(client.recordVoiceState(voiceState(42, nil), roles, briefing.Add(10*time.Minute))).To(Succeed())
+		Expect(client.recordVoiceState(voiceState(42, &stage), roles, briefing.Add(15*time.Minute))).To(Succeed())
+
+		a := session().Attendees[snowflakeID(42).String()]
+		Expect(a.Duration).To(Equal(10 * time.Minute))
+		Expect(a.JoinedAt).To(BeTemporally("==", briefing.Add(15*time.Minute)))
+	})
+
+	It("ignores members without the season role, other channels, and joins outside the window", func() {
+		other := snowflakeID(501)
+		Expect(client.recordVoiceState(voiceState(43, &stage), nil, briefing)).To(Succeed())
+		Expect(client.recordVoiceState(voiceState(42, &other), []snowflake.ID{role}, briefing)).To(Succeed())
+		Expect(client.recordVoiceState(voiceState(42, &stage), []snowflake.ID{role}, briefing.Add(-time.Hour))).To(Succeed())
+		Expect(session().Attendees).To(BeEmpty())
+	})
+
+	It("reports attendance once racing starts and stores it for the round", func() {
+		roles := []snowflake.ID{role}
+		Expect(client.recordVoiceState(voiceState(42, &stage), roles, briefing)).To(Succeed())
+		Expect(client.recordVoiceState(voiceState(99, &stage), roles, briefing.Add(20*time.Minute))).To(Succeed())
+
+		report, err := client.closeAttendance(briefing.Add(10*time.Minute), sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(BeEmpty())
+
+		report, err = client.closeAttendance(briefing.Add(time.Hour), sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(ContainSubstring("Briefing attendance for Round 4 - Monza**: 1 of 3 drivers"))
+		Expect(report).To(ContainSubstring("- #1 Max Verstappen (<@42>): 30m0s"))
+		Expect(report).To(ContainSubstring("- #4 Lando Norris (<@43>)\n"))
+		Expect(report).To(ContainSubstring("- #81 Oscar Piastri (no Discord account found"))
+		Expect(report).To(ContainSubstring("**Attended but not entered**\n- <@99>: 10m0s"))
+		Expect(session()).To(BeNil())
+
+		stored, err := client.loadAttendanceReport("2026 Fall", rc.NextRound)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Attended).To(HaveLen(1))
+		Expect(stored.Attended[0].CarNumber).To(Equal(1))
+		Expect(stored.Attended[0].Duration).To(Equal(30 * time.Minute))
+		Expect(stored.Absent).To(HaveLen(2))
+
+		msg, err := client.runAttendance([]string{"4"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal(report))
+	})
+
+	It("reports a persistent failure to the admins once until it clears", func() {
+		var posted []string
+		client.conf.DiscordAdminChannelId = snowflakeID(900)
+		stub.createMessageFn = func(channelID snowflake.ID, messageCreate dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+			posted = append(posted, messageCreate.Content)
+			return &dgo.Message{}, nil
+		}
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer down.Close()
+		downClient := simgrid.NewClient("test-token")
+		downClient.BaseURL = down.URL
+
+		var watch attendanceWatch
+		ended := briefing.Add(time.Hour)
+		client.pollAttendance(ended, downClient, &watch)
+		client.pollAttendance(ended.Add(attendancePollInterval), downClient, &watch)
+		Expect(posted).To(HaveLen(1))
+		Expect(posted[0]).To(HavePrefix("📋 Failed finishing briefing attendance"))
+
+		client.pollAttendance(ended.Add(2*attendancePollInterval), sgClient, &watch)
+		Expect(posted).To(HaveLen(2))
+		Expect(posted[1]).To(ContainSubstring("Briefing attendance for Round 4 - Monza"))
+		Expect(watch.closeErr).To(BeEmpty())
+	})
+
+	It("says when no attendance was recorded for a round", func() {
+		msg, err := client.runAttendance([]string{"2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal("No briefing attendance was recorded for round 2."))
+
+		_, err = client.runAttendance(nil)
+		Expect(err).To(MatchError(ContainSubstring("usage")))
+	})
+})
@@ -0,0 +1,315 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/disgoorg/disgo/events"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/scheduler"
+	"gopkg.in/yaml.v3"
+)
+
+// currentRoundDocument is the state document holding the round config that
+// the next announce-penalties and race-setup act on. It is replaced whenever
+// an admin uploads a round config and after race setup generates the next
+// round's config.
+const currentRoundDocument = "current_round.yml"
+
+// Scheduled job names, matching the commands they run.
+const (
+	jobAnnouncePenalties = "announce-penalties"
+	jobRaceSetup         = "race-setup"
+	jobRoleSync          = "sync-roles"
+)
+
+// roundKey identifies a round for scheduled job bookkeeping.
+func roundKey(season string, round config.Round) string {
+	return fmt.Sprintf("%s/round-%d", season, round.Number)
+}
+
+// roundDocument names a per-round state document under dir, e.g.
+// "attendance/2026-fall/round-4.yml".
+func roundDocument(dir, season string, round config.Round) string {
+	slug := strings.ToLower(strings.ReplaceAll(season, " ", "-"))
+	return fmt.Sprintf("%s/%s/round-%d.yml", dir, slug, round.Number)
+}
+
+// storedRound is the current round document.
+type storedRound struct {
+	config.RoundConfig `yaml:",inline"`
+	// Generated is set when race setup stored the next round's config it
+	// generated, which has no stewarding penalties until an admin uploads
+	// them with `!set-round`.
+	Generated bool `yaml:"generated,omitempty"`
+}
+
+func (d *DiscordClient) loadStoredRound() (*storedRound, error) {
+	var sr *storedRound
+	if err := d.state.Load(currentRoundDocument, &sr); err != nil {
+		return nil, err
+	}
+	if sr == nil {
+		return nil, fmt.Errorf("no round config is stored yet. Attach one to `!set-round`")
+	}
+	return sr, nil
+}
+
+func (d *DiscordClient) loadCurrentRound() (*config.RoundConfig, error) {
+	sr, err := d.loadStoredRound()
+	if err != nil {
+		return nil, err
+	}
+	return &sr.RoundConfig, nil
+}
+
+// saveCurrentRound replaces the stored round config with rc, keeping whether
+// race setup generated it.
+func (d *DiscordClient) saveCurrentRound(rc *config.RoundConfig) error {
+	var sr storedRound
+	return d.state.Update(currentRoundDocument, &sr, func() error {
+		sr.RoundConfig = *rc
+		return nil
+	})
+}
+
+// storeRound replaces the stored round config with rc, noting whether race
+// setup generated it or an admin uploaded it. Absence penalties already given
+// for rc's previous round are merged in, so replacing the config keeps them.
+func (d *DiscordClient) storeRound(rc *config.RoundConfig, generated bool) error {
+	if err := d.mergeAbsencePenalties(d.snapshotConfig().Season, rc); err != nil {
+		return err
+	}
+	return d.state.Save(currentRoundDocument, &storedRound{RoundConfig: *rc, Generated: generated})
+}
+
+// newScheduler registers a job for each cron expression in conf.Jobs.
+func (d *DiscordClient) newScheduler(conf config.BotConfig) (*scheduler.Scheduler, error) {
+	loc, err := conf.Schedule.Location()
+	if err != nil {
+		return nil, err
+	}
+	s := scheduler.New(d.state, loc, func(r scheduler.Result) { d.reportToAdmins(r.String()) })
+
+	// Round jobs act on the stored round config, once per round.
+	storedRoundKey := func(round func(rc *config.RoundConfig) config.Round) func() (string, error) {
+		return func() (string, error) {
+			rc, err := d.loadCurrentRound()
+			if err != nil {
+				return "", err
+			}
+			return roundKey(d.snapshotConfig().Season, round(rc)), nil
+		}
+	}
+
+	jobs := []struct {
+		name string
+		expr string
+		key  func() (string, error)
+		run  func(ctx context.Context) (string, error)
+	}{
+		{jobAnnouncePenalties, conf.Jobs.AnnouncePenalties, storedRoundKey(func(rc *config.RoundConfig) config.Round {
+			return rc.PreviousRound
+		}), d.runScheduledAnnouncePenalties},
+		{jobRaceSetup, conf.Jobs.RaceSetup, storedRoundKey(func(rc *config.RoundConfig) config.Round {
+			return rc.NextRound
+		}), d.runScheduledRaceSetup},
+		{jobRoleSync, conf.Jobs.RoleSync, d.roleSyncKey, d.runScheduledRoleSync},
+	}
+	for _, job := range jobs {
+		if job.expr == "" {
+			continue
+		}
+		spec, err := scheduler.Parse(job.expr)
+		if err != nil {
+			return nil, err
+		}
+		s.Add(scheduler.Job{
+			Name: job.name,
+			Spec: spec,
+			Key:  job.key,
+			Run:  d.lockedJob(job.name, job.run),
+		})
+	}
+	return s, nil
+}
+
+// startBackground runs scheduled jobs, posts reminders and tracks briefing
+// attendance in the background until Close.
+func (d *DiscordClient) startBackground() {
+	ctx, cancel := context.WithCancel(d.ctx)
+	d.stopBackground = cancel
+	if d.scheduler != nil {
+		go d.scheduler.Run(ctx)
+	}
+	go d.watchReminders(ctx)
+	go d.watchAttendance(ctx)
+}
+
+// markRun records a completed command so the matching scheduled job skips
+// the round.
+func (d *DiscordClient) markRun(job string, round config.Round) error {
+	if d.scheduler == nil {
+		return nil
+	}
+	return d.scheduler.MarkRun(job, roundKey(d.snapshotConfig().Season, round))
+}
+
+// clearRun forgets that job ran for round, so the scheduled job runs it again.
+func (d *DiscordClient) clearRun(job string, round config.Round) error {
+	if d.scheduler == nil {
+		return nil
+	}
+	return d.scheduler.ClearRun(job, roundKey(d.snapshotConfig().Season, round))
+}
+
+// reportToAdmins posts msg to the admin channel, in as many messages as it
+// takes, or logs it when no admin channel is configured.
+func (d *DiscordClient) reportToAdmins(msg string) {
+	channel := d.snapshotConfig().DiscordAdminChannelId
+	if channel == 0 {
+		fmt.Println(msg)
+		return
+	}
+	for _, part := range splitMessage(msg) {
+		if _, err := d.rest.CreateMessage(channel, buildMessage(part)); err != nil {
+			fmt.Printf("Error reporting to admin channel: %s\n%s\n", err, part)
+		}
+	}
+}
+
+// runScheduledAnnouncePenalties announces the stored round's penalties. It
+// won't announce the config race setup generated, which has none yet; the job
+// fails without marking the round, so it runs once an admin uploads the
+// stewarding decisions.
+func (d *DiscordClient) runScheduledAnnouncePenalties(ctx context.Context) (string, error) {
+	sr, err := d.loadStoredRound()
+	if err != nil {
+		return "", err
+	}
+	if sr.Generated {
+		return "", fmt.Errorf("the stored round config is the one race setup generated for %s, so it has no penalties yet. Attach the stewarded config to `!set-round` and the next scheduled run will announce it", sr.PreviousRound)
+	}
+	sgClient := d.newSimGridClient().WithContext(ctx)
+	msg, _, err := d.runAnnouncePenalties(&sr.RoundConfig, sgClient)
+	return msg, err
+}
+
+func (d *DiscordClient) runScheduledRaceSetup(ctx context.Context) (string, error) {
+	rc, err := d.loadCurrentRound()
+	if err != nil {
+		return "", err
+	}
+	sgClient := d.newSimGridClient().WithContext(ctx)
+	gcClient, err := d.newGCloudClientContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	d.reportRosterBeforeRaceSetup(sgClient, rc.NextRound)
+	msg, attachment, err := d.runRaceSetup(rc, sgClient, gcClient)
+	if attachment != "" {
+		// The next round config is already stored as the current round;
+		// the file only exists to be attached to a command reply.
+		_ = os.Remove(attachment)
+	}
+	return msg, err
+}
+
+func (d *DiscordClient) setRound(event *events.MessageCreate) {
+	var msg string
+	defer func() { sendBotResponse(event, msg, "") }()
+	roundConfig, err := getRoundConfig(event)
+	if err != nil {
+		msg = fmt.Sprintf("Failed getting race config: %s", err)
+		return
+	}
+	msg, err = d.runSetRound(roundConfig)
+	if err != nil {
+		msg = err.Error()
+	}
+}
+
+// runSetRound stores rc as the current round for scheduled jobs.
+func (d *DiscordClient) runSetRound(rc *config.RoundConfig) (string, error) {
+	if err := d.storeRound(rc, false); err != nil {
+		return "", fmt.Errorf("failed saving round config: %w", err)
+	}
+	return fmt.Sprintf("Ok, scheduled jobs will use this config: penalties from %s, race setup for %s.", rc.PreviousRound, rc.NextRound), nil
+}
+
+// runJobs lists the scheduled jobs with their next run and the last round
+// each completed for.
+func (d *DiscordClient) runJobs() (string, error) {
+	if d.scheduler == nil {
+		return "No jobs are scheduled. Add cron expressions under `jobs:` in the bot config.", nil
+	}
+	jobs, err := d.scheduler.Jobs()
+	if err != nil {
+		return "", err
+	}
+	if len(jobs) == 0 {
+		return "No jobs are scheduled. Add cron expressions under `jobs:` in the bot config.", nil
+	}
+
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Scheduled jobs**\n")
+	for _, name := range names {
+		st := jobs[name]
+		fmt.Fprintf(&b, "- `%s` (`%s`)", name, st.Spec)
+		if !st.NextRun.IsZero() {
+			fmt.Fprintf(&b, ": next run <t:%d:F>", st.NextRun.Unix())
+		}
+		if st.LastKey != "" {
+			fmt.Fprintf(&b, ", last completed for %s", st.LastKey)
+		}
+		if st.LastError != "" {
+			fmt.Fprintf(&b, ", last error: %s", st.LastError)
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	if rc, err := d.loadCurrentRound(); err == nil {
+		data, err := yaml.Marshal(rc)
+		if err == nil {
+			fmt.Fprintf(&b, "\nStored round config:\n```yaml\n%s```\n", data)
+		}
+	}
+	return b.String(), nil
+}
+
+func (d *DiscordClient) jobs(event *events.MessageCreate) {
+	msg, err := d.runJobs()
+	if err != nil {
+		msg = err.Error()
+	}
+	sendBotResponse(event, msg, "")
+}
+
+// recordRound stores rc for scheduled jobs and marks job done for round,
+// returning a note for the admin when either fails. The command already
+// succeeded, so these failures don't fail it. Race setup stores the config
+// it generated, which the scheduled penalty announcement won't use.
+func (d *DiscordClient) recordRound(job string, round config.Round, rc *config.RoundConfig) string {
+	var notes []string
+	if rc != nil {
+		if err := d.storeRound(rc, job == jobRaceSetup); err != nil {
+			notes = append(notes, fmt.Sprintf("Could not store the round config for scheduled jobs: %s", err))
+		}
+	}
+	if err := d.markRun(job, round); err != nil {
+		notes = append(notes, fmt.Sprintf("Could not record this run for scheduled jobs: %s", err))
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return "\n\n" + strings.Join(notes, "\n")
+}
@@ -0,0 +1,149 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("scheduled jobs", func() {
+	var (
+		client   *DiscordClient
+		stub     *stubRest
+		conf     *config.Config
+		stateDir string
+		rc       *config.RoundConfig
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = os.MkdirTemp("", "rookies-bot-automation")
+		Expect(err).NotTo(HaveOccurred())
+
+		stub = &stubRest{}
+		conf = &config.Config{BotConfig: config.BotConfig{
+			Season:                "2026 Fall",
+			StateDir:              stateDir,
+			DiscordAdminChannelId: snowflakeID(900),
+			Jobs:                  config.Jobs{RaceSetup: "0 10 * * mon"},
+		}}
+		client = NewTestDiscordClient(stub, snowflakeID(1), conf, nil)
+		client.scheduler, err = client.newScheduler(conf.BotConfig)
+		Expect(err).NotTo(HaveOccurred())
+
+		rc = &config.RoundConfig{
+			PreviousRound: config.Round{Number: 3, Track: "Spa"},
+			NextRound:     config.Round{Number: 4, Track: "Monza"},
+			Penalties:     config.Penalty{PitStartsR1: []int{7}},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
+	})
+
+	It("stores the round config jobs act on", func() {
+		_, err := client.loadCurrentRound()
+		Expect(err).To(MatchError(ContainSubstring("!set-round")))
+
+		msg, err := client.runSetRound(rc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("penalties from Round 3 - Spa, race setup for Round 4 - Monza"))
+
+		stored, err := client.loadCurrentRound()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.NextRound).To(Equal(rc.NextRound))
+		Expect(stored.PreviousRound).To(Equal(rc.PreviousRound))
+		Expect(stored.Penalties.PitStartsR1).To(Equal([]int{7}))
+	})
+
+	It("only registers configured jobs", func() {
+		client.scheduler.RunDue(context.Background())
+		jobs, err := client.scheduler.Jobs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs).To(HaveKey(jobRaceSetup))
+		Expect(jobs).NotTo(HaveKey(jobAnnouncePenalties))
+		Expect(jobs[jobRaceSetup].NextRun.IsZero()).To(BeFalse())
+	})
+
+	It("records manual runs so the scheduled job skips the round", func() {
+		next := &config.RoundConfig{PreviousRound: rc.NextRound, NextRound: config.Round{Number: 5, Track: "Imola"}}
+		Expect(client.recordRound(jobRaceSetup, rc.NextRound, next)).To(BeEmpty())
+
+		stored, err := client.loadCurrentRound()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.NextRound).To(Equal(next.NextRound))
+
+		msg, err := client.runJobs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("`race-setup` (`0 10 * * mon`)"))
+		Expect(msg).To(ContainSubstring("last completed for 2026 Fall/round-4"))
+		Expect(msg).To(ContainSubstring("track: Imola"))
+	})
+
+	It("won't announce penalties from the config race setup generated", func() {
+		next := &config.RoundConfig{PreviousRound: rc.NextRound, NextRound: config.Round{Number: 5, Track: "Imola"}}
+		Expect(client.recordRound(jobRaceSetup, rc.NextRound, next)).To(BeEmpty())
+
+		_, err := client.runScheduledAnnouncePenalties(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("the stored round config is the one race setup generated for Round 4 - Monza, so it has no penalties yet")))
+
+		Expect(client.saveCurrentRound(next)).To(Succeed())
+		stored, err := client.loadStoredRound()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Generated).To(BeTrue())
+
+		_, err = client.runSetRound(next)
+		Expect(err).NotTo(HaveOccurred())
+		stored, err = client.loadStoredRound()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Generated).To(BeFalse())
+	})
+
+	It("connects to Google with the scheduled job's context", func() {
+		_, err := client.runSetRound(rc)
+		Expect(err).NotTo(HaveOccurred())
+
+		type jobKey struct{}
+		ctx := context.WithValue(context.Background(), jobKey{}, "race-setup")
+		client.connectGCloud = func(got context.Context) (*gcloud.Client, error) {
+			Expect(got.Value(jobKey{})).To(Equal("race-setup"))
+			return nil, errors.New("no credentials")
+		}
+		_, err = client.runScheduledRaceSetup(ctx)
+		Expect(err).To(MatchError("no credentials"))
+	})
+
+	It("notes when the round cannot be recorded", func() {
+		client.state = NewTestDiscordClient(stub, snowflakeID(1), nil, nil).state
+		note := client.recordRound(jobRaceSetup, rc.NextRound, rc)
+		Expect(note).To(ContainSubstring("Could not store the round config for scheduled jobs"))
+	})
+
+	It("reports to the admin channel", func() {
+		var channel snowflake.ID
+		var content string
+		stub.createMessageFn = func(channelID snowflake.ID, messageCreate dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+			channel, content = channelID, messageCreate.Content
+			return &dgo.Message{}, nil
+		}
+		client.reportToAdmins("⏰ Scheduled race-setup for 2026 Fall/round-4:\ndone")
+		Expect(channel).To(Equal(snowflakeID(900)))
+		Expect(content).To(ContainSubstring("done"))
+	})
+
+	It("explains when no jobs are configured", func() {
+		client.scheduler = nil
+		msg, err := client.runJobs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("No jobs are scheduled"))
+	})
+})
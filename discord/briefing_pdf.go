@@ -0,0 +1,70 @@
+package discord
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+)
+
+// briefingPDFFile is the state file keeping a copy of the round's briefing
+// PDF.
+func briefingPDFFile(season string, round config.Round) string {
+	return strings.TrimSuffix(roundDocument("briefings", season, round), ".yml") + ".pdf"
+}
+
+// exportBriefingPDF exports the round's briefing doc as a PDF to attach to
+// the race-day message, for drivers who can't open Google Docs, and keeps a
+// copy in the round's state. Failing to is noted for the admin rather than
+// stopping race setup.
+func (d *DiscordClient) exportBriefingPDF(gcClient *gcloud.Client, briefingURL string, round config.Round, journal *setupJournal) (*discord.File, string) {
+	file, pdf, err := briefingPDF(gcClient, briefingURL, round)
+	if err != nil {
+		return nil, fmt.Sprintf("\n\nCould not attach the briefing as a PDF: %s", err)
+	}
+	if d.state.Dir() == "" {
+		return file, ""
+	}
+	name := briefingPDFFile(d.snapshotConfig().Season, round)
+	if err := d.state.SaveFile(name, pdf); err != nil {
+		return file, fmt.Sprintf("\n\nCould not keep a copy of the briefing PDF: %s", err)
+	}
+	journal.record(setupOperation{Kind: opBriefingPDF, Path: name})
+	return file, ""
+}
+
+// refreshBriefingPDF re-exports the round's briefing doc once it has changed,
+// replaces the PDF attached to the race-day message with it, and overwrites
+// the stored copy.
+func (d *DiscordClient) refreshBriefingPDF(gcClient *gcloud.Client, briefingURL string, round config.Round, raceDay *messageRef) error {
+	file, pdf, err := briefingPDF(gcClient, briefingURL, round)
+	if err != nil {
+		return err
+	}
+	// Listing no attachments to keep drops the old PDF.
+	update := discord.MessageUpdate{Attachments: &[]discord.AttachmentUpdate{}, Files: []*discord.File{file}}
+	if _, err := d.rest.UpdateMessage(raceDay.ChannelID, raceDay.MessageID, update); err != nil {
+		return fmt.Errorf("failed replacing the race-day announcement's PDF: %w", err)
+	}
+	if d.state.Dir() == "" {
+		return nil
+	}
+	return d.state.SaveFile(briefingPDFFile(d.snapshotConfig().Season, round), pdf)
+}
+
+// briefingPDF exports the briefing doc as a PDF, returning it both as the
+// file to attach for round and as raw bytes to store.
+func briefingPDF(gcClient *gcloud.Client, briefingURL string, round config.Round) (*discord.File, []byte, error) {
+	pdf, err := gcClient.ExportBriefingPDF(briefingURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	file := &discord.File{
+		Name:   fmt.Sprintf("Drivers Briefing Round %d.pdf", round.Number),
+		Reader: bytes.NewReader(pdf),
+	}
+	return file, pdf, nil
+}
@@ -0,0 +1,107 @@
+package discord
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	"github.com/geofffranks/rookies-bot/gcloud/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("briefing PDFs", func() {
+	var (
+		league    *testLeague
+		client    *DiscordClient
+		gcClient  *gcloud.Client
+		fakeDrive *fakes.FakeDriveServicer
+		imola     config.Round
+	)
+
+	const briefingURL = "https://docs.google.com/document/d/briefing-id"
+
+	BeforeEach(func() {
+		fakeDrive = &fakes.FakeDriveServicer{}
+		fakeDrive.ExportFileReturns([]byte("%PDF-1.7"), nil)
+		gcClient = &gcloud.Client{Drive: fakeDrive}
+		league = newTestLeague("briefing-pdf", config.BotConfig{DiscordChannelId: snowflakeID(111)}, gcClient)
+		client = league.client
+		imola = config.Round{Number: 2, Track: "Imola"}
+	})
+
+	AfterEach(func() {
+		league.close()
+	})
+
+	storedPDF := func() string {
+		pdf, err := os.ReadFile(filepath.Join(league.stateDir, "briefings", "2026-fall", "round-2.pdf"))
+		Expect(err).NotTo(HaveOccurred())
+		return string(pdf)
+	}
+
+	It("exports the briefing doc for the race-day message and keeps a journaled copy", func() {
+		journal, err := client.startRaceSetupJournal(&config.RoundConfig{NextRound: imola}, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+
+		file, note := client.exportBriefingPDF(gcClient, briefingURL, imola, journal)
+		Expect(note).To(BeEmpty())
+		Expect(file.Name).To(Equal("Drivers Briefing Round 2.pdf"))
+		content, err := io.ReadAll(file.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("%PDF-1.7"))
+
+		_, id, mimeType := fakeDrive.ExportFileArgsForCall(0)
+		Expect(id).To(Equal("briefing-id"))
+		Expect(mimeType).To(Equal("application/pdf"))
+		Expect(storedPDF()).To(Equal("%PDF-1.7"))
+		Expect(journal.journal.Operations).To(Equal([]setupOperation{{Kind: opBriefingPDF, Path: "briefings/2026-fall/round-2.pdf", At: journal.journal.Operations[0].At}}))
+	})
+
+	It("notes an export failure instead of failing", func() {
+		fakeDrive.ExportFileReturns(nil, errors.New("export size limit exceeded"))
+		file, note := client.exportBriefingPDF(gcClient, briefingURL, imola, nil)
+		Expect(file).To(BeNil())
+		Expect(note).To(Equal("\n\nCould not attach the briefing as a PDF: failed exporting the Briefing Doc as PDF: export size limit exceeded"))
+	})
+
+	It("replaces the race-day message's PDF and the stored copy once the doc changes", func() {
+		_, note := client.exportBriefingPDF(gcClient, briefingURL, imola, nil)
+		Expect(note).To(BeEmpty())
+
+		var update dgo.MessageUpdate
+		league.stub.updateMessageFn = func(channelID snowflake.ID, messageID snowflake.ID, u dgo.MessageUpdate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+			Expect(channelID).To(Equal(snowflakeID(111)))
+			Expect(messageID).To(Equal(snowflakeID(700)))
+			update = u
+			return &dgo.Message{}, nil
+		}
+		fakeDrive.ExportFileReturns([]byte("%PDF-amended"), nil)
+
+		Expect(client.refreshBriefingPDF(gcClient, briefingURL, imola, &messageRef{ChannelID: snowflakeID(111), MessageID: snowflakeID(700)})).To(Succeed())
+		Expect(*update.Attachments).To(BeEmpty())
+		Expect(update.Files).To(HaveLen(1))
+		Expect(update.Files[0].Name).To(Equal("Drivers Briefing Round 2.pdf"))
+		Expect(storedPDF()).To(Equal("%PDF-amended"))
+	})
+
+	It("keeps the stored copy when the race-day message can't be updated", func() {
+		_, note := client.exportBriefingPDF(gcClient, briefingURL, imola, nil)
+		Expect(note).To(BeEmpty())
+		league.stub.updateMessageFn = func(channelID snowflake.ID, messageID snowflake.ID, u dgo.MessageUpdate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+			return nil, errors.New("unknown message")
+		}
+		fakeDrive.ExportFileReturns([]byte("%PDF-amended"), nil)
+
+		err := client.refreshBriefingPDF(gcClient, briefingURL, imola, &messageRef{ChannelID: snowflakeID(111), MessageID: snowflakeID(700)})
+		Expect(err).To(MatchError("failed replacing the race-day announcement's PDF: unknown message"))
+		Expect(storedPDF()).To(Equal("%PDF-1.7"))
+	})
+})
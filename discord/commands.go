@@ -0,0 +1,227 @@
+package discord
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+)
+
+// mutatingCommands change what the league has posted, copied or stored. Only
+// one of them runs at a time in a league; their dry runs are exempt.
+var mutatingCommands = map[string]bool{
+	"!announce-penalties":  true,
+	"!race-setup":          true,
+	"!rollback-race-setup": true,
+	"!new-season-apply":    true,
+	"!set-round":           true,
+	"!absence-penalties":   true,
+	"!amend-penalties":     true,
+	"!sync-roles":          true,
+}
+
+// leagueLock lets one mutating command or scheduled job run at a time in a
+// league, remembering which one holds it so others can be told.
+type leagueLock struct {
+	sem    chan struct{}
+	mu     sync.Mutex
+	holder alreadyRunningError
+}
+
+func newLeagueLock() *leagueLock {
+	return &leagueLock{sem: make(chan struct{}, 1)}
+}
+
+// alreadyRunningError is returned to a command that finds another one
+// holding the league lock.
+type alreadyRunningError struct {
+	// Command is what holds the lock, e.g. "`!race-setup`".
+	Command string
+	// By is the admin who ran it, or zero for a scheduled job.
+	By    snowflake.ID
+	Since time.Time
+}
+
+func (e alreadyRunningError) Error() string {
+	command := e.Command
+	if command == "" {
+		command = "Another command"
+	}
+	if e.By == 0 {
+		return fmt.Sprintf("%s is already running for this league (started %s ago).", command, time.Since(e.Since).Round(time.Second))
+	}
+	return fmt.Sprintf("%s is already running for this league (started by <@%s> %s ago).", command, e.By, time.Since(e.Since).Round(time.Second))
+}
+
+// tryAcquire takes the lock for command, run by the admin by, or returns an
+// alreadyRunningError without waiting when it's held. Call release once the
+// command finishes.
+func (l *leagueLock) tryAcquire(command string, by snowflake.ID) (release func(), err error) {
+	select {
+	case l.sem <- struct{}{}:
+		return l.hold(command, by), nil
+	default:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return nil, l.holder
+	}
+}
+
+// acquire waits for the lock, for at most as long as ctx lasts.
+func (l *leagueLock) acquire(ctx context.Context, command string) (release func(), err error) {
+	select {
+	case l.sem <- struct{}{}:
+		return l.hold(command, 0), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *leagueLock) hold(command string, by snowflake.ID) func() {
+	l.mu.Lock()
+	l.holder = alreadyRunningError{Command: command, By: by, Since: time.Now()}
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		l.holder = alreadyRunningError{}
+		l.mu.Unlock()
+		<-l.sem
+	}
+}
+
+// lockedJob runs a scheduled job under the league lock, waiting for any
+// command that's running to finish first. The run is tracked with the
+// commands so shutdown waits for it too.
+func (d *DiscordClient) lockedJob(name string, run func(ctx context.Context) (string, error)) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		release, err := d.running.acquire(ctx, fmt.Sprintf("The scheduled %s job", name))
+		if err != nil {
+			return "", err
+		}
+		defer release()
+		if !d.commands.start() {
+			return "", errShuttingDown
+		}
+		defer d.commands.finish()
+		return run(ctx)
+	}
+}
+
+// commandRunsDocument records the completed runs of commands that act on an
+// attached round config, keyed by idempotencyKey.
+const commandRunsDocument = "command-runs"
+
+// commandRun is a completed run of a command, with the reply it gave.
+type commandRun struct {
+	Command  string       `yaml:"command"`
+	Round    config.Round `yaml:"round"`
+	By       snowflake.ID `yaml:"by"`
+	At       time.Time    `yaml:"at"`
+	Response string       `yaml:"response"`
+}
+
+type commandRuns struct {
+	Runs map[string]commandRun `yaml:"runs"`
+}
+
+// attachmentHash identifies the content of an attached file.
+func attachmentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:8])
+}
+
+// idempotencyKey identifies running command for round of season with an
+// attachment hashing to hash. Running it again with the same file is a
+// repeat; a different file is a new run.
+func idempotencyKey(season, command string, round config.Round, hash string) string {
+	return runPrefix(season, command, round) + "sha256-" + hash
+}
+
+// runPrefix is the start of the idempotency keys for command's runs for
+// round.
+func runPrefix(season, command string, round config.Round) string {
+	return fmt.Sprintf("%s/%s/round-%d/", season, strings.TrimPrefix(command, "!"), round.Number)
+}
+
+// repeatedRun returns the reply for a command that already ran with the same
+// idempotency key, or "" when it hasn't. Without a state dir nothing is
+// recorded, so nothing is a repeat.
+func (d *DiscordClient) repeatedRun(key string) (string, error) {
+	var runs commandRuns
+	if err := d.state.Load(commandRunsDocument, &runs); err != nil {
+		return "", err
+	}
+	run, ok := runs.Runs[key]
+	if !ok {
+		return "", nil
+	}
+	by := "the bot"
+	if run.By != 0 {
+		by = fmt.Sprintf("<@%s>", run.By)
+	}
+	return fmt.Sprintf("`%s` already ran for %s with this file, by %s on %s, so nothing was done again. It replied:\n\n%s",
+		run.Command, run.Round, by, run.At.Format(time.RFC1123), run.Response), nil
+}
+
+// recordRun records a completed run under key, returning a note for the
+// admin response when it couldn't be recorded.
+func (d *DiscordClient) recordRun(key string, run commandRun) string {
+	if d.state.Dir() == "" {
+		return ""
+	}
+	var runs commandRuns
+	err := d.state.Update(commandRunsDocument, &runs, func() error {
+		if runs.Runs == nil {
+			runs.Runs = map[string]commandRun{}
+		}
+		runs.Runs[key] = run
+		return nil
+	})
+	if err != nil {
+		return fmt.Sprintf("\n\nCould not record this run, so repeating it won't be caught: %s", err)
+	}
+	return ""
+}
+
+// forgetRuns drops the recorded runs of command for round of season, so it
+// can run again with any file.
+func (d *DiscordClient) forgetRuns(season, command string, round config.Round) error {
+	if d.state.Dir() == "" {
+		return nil
+	}
+	prefix := runPrefix(season, command, round)
+	var runs commandRuns
+	return d.state.Update(commandRunsDocument, &runs, func() error {
+		for key := range runs.Runs {
+			if strings.HasPrefix(key, prefix) {
+				delete(runs.Runs, key)
+			}
+		}
+		return nil
+	})
+}
+
+// runOnce runs command for round unless a run with the same key already
+// completed, in which case it replies with what that run reported instead of
+// doing it all again. Successful runs are recorded under key.
+func (d *DiscordClient) runOnce(key, command string, round config.Round, by snowflake.ID, run func() (string, string, error)) (string, string, error) {
+	repeat, err := d.repeatedRun(key)
+	if err != nil {
+		return "", "", fmt.Errorf("could not check for an earlier %s run: %w", command, err)
+	}
+	if repeat != "" {
+		return repeat, "", nil
+	}
+	msg, attachment, err := run()
+	if err != nil {
+		return msg, attachment, err
+	}
+	msg += d.recordRun(key, commandRun{Command: command, Round: round, By: by, At: time.Now(), Response: msg})
+	return msg, attachment, nil
+}
@@ -0,0 +1,170 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/geofffranks/rookies-bot/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("running commands", func() {
+	Describe("the league lock", func() {
+		var lock *leagueLock
+
+		BeforeEach(func() {
+			lock = newLeagueLock()
+		})
+
+		It("tells a second command which one is already running", func() {
+			release, err := lock.tryAcquire("`!race-setup`", snowflakeID(42))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = lock.tryAcquire("`!announce-penalties`", snowflakeID(43))
+			Expect(err).To(MatchError(MatchRegexp(`^` + "`!race-setup`" + ` is already running for this league \(started by <@42> \d+s ago\)\.$`)))
+
+			release()
+			release, err = lock.tryAcquire("`!announce-penalties`", snowflakeID(43))
+			Expect(err).NotTo(HaveOccurred())
+			release()
+		})
+
+		It("makes scheduled jobs wait for the running command", func() {
+			release, err := lock.tryAcquire("`!race-setup`", snowflakeID(42))
+			Expect(err).NotTo(HaveOccurred())
+
+			acquired := make(chan error, 1)
+			go func() {
+				release, err := lock.acquire(context.Background(), "The scheduled race-setup job")
+				if err == nil {
+					release()
+				}
+				acquired <- err
+			}()
+			Consistently(acquired, 20*time.Millisecond).ShouldNot(Receive())
+			release()
+			Eventually(acquired).Should(Receive(BeNil()))
+		})
+
+		It("stops waiting when ctx is done", func() {
+			release, err := lock.tryAcquire("`!race-setup`", snowflakeID(42))
+			Expect(err).NotTo(HaveOccurred())
+			defer release()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = lock.acquire(ctx, "The scheduled race-setup job")
+			Expect(err).To(MatchError(context.Canceled))
+		})
+
+		It("is held by scheduled jobs while they run", func() {
+			client := NewTestDiscordClient(&stubRest{}, snowflakeID(1), &config.Config{}, nil)
+			job := client.lockedJob("race-setup", func(ctx context.Context) (string, error) {
+				_, err := client.running.tryAcquire("`!race-setup`", snowflakeID(42))
+				return "", err
+			})
+			_, err := job(context.Background())
+			Expect(err).To(MatchError(MatchRegexp(`^The scheduled race-setup job is already running for this league \(started \d+s ago\)\.$`)))
+
+			release, err := client.running.tryAcquire("`!race-setup`", snowflakeID(42))
+			Expect(err).NotTo(HaveOccurred())
+			release()
+		})
+	})
+
+	Describe("repeated runs", func() {
+		var (
+			client   *DiscordClient
+			stateDir string
+			round    config.Round
+			runs     int
+		)
+
+		run := func() (string, string, error) {
+			runs++
+			return "Briefing: https://docs.google.com/document/d/briefing-id", "", nil
+		}
+
+		BeforeEach(func() {
+			var err error
+			stateDir, err = os.MkdirTemp("", "rookies-bot-commands")
+			Expect(err).NotTo(HaveOccurred())
+			client = NewTestDiscordClient(&stubRest{}, snowflakeID(1), &config.Config{BotConfig: config.BotConfig{
+				Season:   "2026 Fall",
+				StateDir: stateDir,
+			}}, nil)
+			round = config.Round{Number: 2, Track: "Imola"}
+			runs = 0
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(stateDir)
+		})
+
+		It("keys runs by season, command, round and attachment", func() {
+			Expect(idempotencyKey("2026 Fall", "!race-setup", round, attachmentHash([]byte("penalties")))).
+				To(Equal("2026 Fall/race-setup/round-2/sha256-" + attachmentHash([]byte("penalties"))))
+			Expect(attachmentHash([]byte("penalties"))).To(HaveLen(16))
+			Expect(attachmentHash([]byte("penalties"))).NotTo(Equal(attachmentHash([]byte("other penalties"))))
+		})
+
+		It("reports the earlier run instead of running the same file again", func() {
+			key := idempotencyKey("2026 Fall", "!race-setup", round, "abc123")
+			msg, _, err := client.runOnce(key, "!race-setup", round, snowflakeID(42), run)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg).To(Equal("Briefing: https://docs.google.com/document/d/briefing-id"))
+
+			msg, _, err = client.runOnce(key, "!race-setup", round, snowflakeID(43), run)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runs).To(Equal(1))
+			Expect(msg).To(HavePrefix("`!race-setup` already ran for Round 2 - Imola with this file, by <@42> on "))
+			Expect(msg).To(HaveSuffix(", so nothing was done again. It replied:\n\nBriefing: https://docs.google.com/document/d/briefing-id"))
+		})
+
+		It("runs again with a different file", func() {
+			_, _, err := client.runOnce(idempotencyKey("2026 Fall", "!race-setup", round, "abc123"), "!race-setup", round, snowflakeID(42), run)
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = client.runOnce(idempotencyKey("2026 Fall", "!race-setup", round, "def456"), "!race-setup", round, snowflakeID(42), run)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runs).To(Equal(2))
+		})
+
+		It("does not record failed runs", func() {
+			key := idempotencyKey("2026 Fall", "!race-setup", round, "abc123")
+			_, _, err := client.runOnce(key, "!race-setup", round, snowflakeID(42), func() (string, string, error) {
+				return "", "", errors.New("SimGrid is down")
+			})
+			Expect(err).To(MatchError("SimGrid is down"))
+
+			_, _, err = client.runOnce(key, "!race-setup", round, snowflakeID(42), run)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runs).To(Equal(1))
+		})
+
+		It("forgets a round's runs so it can be set up again", func() {
+			key := idempotencyKey("2026 Fall", "!race-setup", round, "abc123")
+			other := idempotencyKey("2026 Fall", "!race-setup", config.Round{Number: 3}, "abc123")
+			for _, k := range []string{key, other} {
+				_, _, err := client.runOnce(k, "!race-setup", round, snowflakeID(42), run)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(client.forgetRuns("2026 Fall", "!race-setup", round)).To(Succeed())
+			Expect(client.repeatedRun(key)).To(BeEmpty())
+			Expect(client.repeatedRun(other)).NotTo(BeEmpty())
+		})
+
+		It("runs every time without a state dir", func() {
+			client.state = NewTestDiscordClient(&stubRest{}, snowflakeID(1), &config.Config{}, nil).state
+			key := idempotencyKey("2026 Fall", "!race-setup", round, "abc123")
+			for range 2 {
+				_, _, err := client.runOnce(key, "!race-setup", round, snowflakeID(42), run)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(runs).To(Equal(2))
+		})
+	})
+})
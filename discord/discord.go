@@ -7,15 +7,19 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/geofffranks/rookies-bot/config"
 	"github.com/geofffranks/rookies-bot/gcloud"
 	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/scheduler"
 	"github.com/geofffranks/rookies-bot/simgrid"
+	"github.com/geofffranks/rookies-bot/state"
 	"gopkg.in/yaml.v3"
 
 	"github.com/disgoorg/disgo"
@@ -56,10 +60,37 @@ type DiscordClient struct {
 	applicationID snowflake.ID
 	conf          *config.Config
 	guild         snowflake.ID
-	memberList    map[string]snowflake.ID
+	members       *guildMembers
 	gcloud        *gcloud.Client
-	configPath    string
-	mu            sync.RWMutex
+	// connectGCloud connects to Google APIs for a command or job; tests
+	// swap it for one returning a fake.
+	connectGCloud  func(context.Context) (*gcloud.Client, error)
+	configPath     string
+	messages       *messages
+	state          *state.Store
+	scheduler      *scheduler.Scheduler
+	stopBackground context.CancelFunc
+	mu             sync.RWMutex
+
+	// ctx is the root context for command handlers' SimGrid and Google
+	// calls, cancelled once shutdown stops waiting on them. Leagues share it.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// commands tracks the command handlers in flight, so shutdown can wait
+	// for them.
+	commands *commandTracker
+	// running lets one mutating command or scheduled job run at a time in
+	// the league.
+	running *leagueLock
+
+	// drivers holds driver identity links and DM preferences. They belong
+	// to the driver rather than to a league, so every league shares the
+	// top-level store while state holds the league's own round state.
+	drivers *state.Store
+	// leagues are the clients for each configured league, sharing this
+	// client's Discord and Google connections. Empty for a bot without
+	// leagues, which handles everything itself.
+	leagues []*DiscordClient
 }
 
 // snapshotConfig returns a copy of the live bot config. Handlers read config
@@ -95,11 +126,17 @@ func generateNextRoundConfig(sgc *simgrid.SimGridClient, gc *gcloud.Client, conf
 
 	conf.NextRound.PenaltyTrackerLink = nextRoundTracker
 
+	return buildNextRoundConfig(conf.NextRound, nextRound, penalties), nil
+}
+
+// buildNextRoundConfig is the config for the round after round, carrying
+// over the penalties drivers didn't serve.
+func buildNextRoundConfig(round config.Round, nextRound *config.Round, penalties *models.Penalties) *config.RoundConfig {
 	return &config.RoundConfig{
-		PreviousRound:        conf.NextRound,
+		PreviousRound:        round,
 		NextRound:            *nextRound,
 		CarriedOverPenalties: penalties.Consolidate(),
-	}, nil
+	}
 }
 
 func writeNextRoundConfig(conf *config.RoundConfig, season string) (string, error) {
@@ -119,27 +156,35 @@ func writeNextRoundConfig(conf *config.RoundConfig, season string) (string, erro
 }
 
 func getRoundConfig(event *events.MessageCreate) (*config.RoundConfig, error) {
+	roundConfig, _, err := getRoundConfigWithHash(event)
+	return roundConfig, err
+}
+
+// getRoundConfigWithHash is getRoundConfig, also returning the attachment's
+// hash for idempotencyKey.
+func getRoundConfigWithHash(event *events.MessageCreate) (*config.RoundConfig, string, error) {
 	attachments := event.Message.Attachments
 
 	if len(attachments) == 0 {
-		return nil, fmt.Errorf("no race penalty YAML file was attached to this request")
+		return nil, "", fmt.Errorf("no race penalty YAML file was attached to this request")
 	}
 
 	if len(attachments) > 1 {
-		return nil, fmt.Errorf("too many attachments were included on the request, please only submit one race penalty YAML file")
+		return nil, "", fmt.Errorf("too many attachments were included on the request, please only submit one race penalty YAML file")
 	}
 	fileContent, err := downloadAttachment(attachments[0].URL)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error downloading the attached file: %s", err)
+		return nil, "", fmt.Errorf("unexpected error downloading the attached file: %s", err)
 	}
 
 	roundConfig, err := config.LoadRoundConfig(fileContent)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse race penalty YAML file: %s", err)
+		return nil, "", fmt.Errorf("unable to parse race penalty YAML file: %s", err)
 	}
 
-	return roundConfig, nil
+	return roundConfig, attachmentHash(fileContent), nil
 }
+
 func buildPenaltyList(driverLookup models.DriverLookup, conf *config.RoundConfig) (*models.Penalties, error) {
 	penalties := models.Penalties{}
 
@@ -201,51 +246,230 @@ func isAllowedUser(userId snowflake.ID) bool {
 	}
 	return false
 }
+
+// runHelp renders the help overview, or the commands for the topic in args.
+func (d *DiscordClient) runHelp(args []string) (string, error) {
+	var topic string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		topic = strings.ToLower(args[0])
+		if !slices.Contains(helpTopics, topic) {
+			return "", fmt.Errorf("unknown help topic %q, try one of: %s", args[0], strings.Join(helpTopics, ", "))
+		}
+	}
+	return d.messages.render(templateHelp, HelpData{Topic: topic})
+}
+
+// inAdminChannel reports whether a message came in a DM or one of a league's
+// admin channels, the only places admin commands are answered: their replies
+// can ping drivers, list DQs and show the bot's config.
+func (d *DiscordClient) inAdminChannel(guildID *snowflake.ID, channelID snowflake.ID) bool {
+	if guildID == nil {
+		return true
+	}
+	for _, league := range d.leagueClients() {
+		if slices.Contains(adminChannels(league.snapshotConfig()), channelID) {
+			return true
+		}
+	}
+	return false
+}
+
+// adminChannels returns the channels a league answers admin commands in: its
+// admin channel, or its announcement and briefing channels when it has none.
+func adminChannels(conf config.BotConfig) []snowflake.ID {
+	if conf.DiscordAdminChannelId != 0 {
+		return []snowflake.ID{conf.DiscordAdminChannelId}
+	}
+	var channels []snowflake.ID
+	for _, channel := range []snowflake.ID{conf.DiscordChannelId, conf.DiscordBriefingChannelId} {
+		if channel != 0 {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// adminChannelHint tells an admin who sent command elsewhere where it is
+// answered.
+func (d *DiscordClient) adminChannelHint(command string) string {
+	var mentions []string
+	for _, league := range d.leagueClients() {
+		for _, channel := range adminChannels(league.snapshotConfig()) {
+			mentions = append(mentions, fmt.Sprintf("<#%s>", channel))
+		}
+	}
+	if len(mentions) == 0 {
+		return fmt.Sprintf("`%s` is only answered in DMs.", command)
+	}
+	return fmt.Sprintf("`%s` is only answered in DMs and %s.", command, strings.Join(mentions, ", "))
+}
+
 func (d *DiscordClient) onMessageCreate(event *events.MessageCreate) {
-	if !isAllowedUser(event.Message.Author.ID) {
+	if event.Message.Author.Bot {
 		return
 	}
+	fields := strings.Fields(event.Message.Content)
+	if len(fields) == 0 {
+		return
+	}
+	command, args := fields[0], fields[1:]
 
-	switch event.Message.Content {
+	// Commands open to every driver.
+	switch command {
+	case "!link", "!penalty-dms", "!excuse":
 	case "!help":
-		sendBotResponse(event, helpMessage(), "")
+		if !isAllowedUser(event.Message.Author.ID) {
+			return
+		}
+		if !d.inAdminChannel(event.GuildID, event.ChannelID) {
+			sendBotResponse(event, d.adminChannelHint(command), "")
+			return
+		}
+		msg, err := d.runHelp(args)
+		if err != nil {
+			msg = err.Error()
+		}
+		sendBotResponse(event, msg, "")
+		return
+	default:
+		if !strings.HasPrefix(command, "!") || !isAllowedUser(event.Message.Author.ID) {
+			return
+		}
+		if !d.inAdminChannel(event.GuildID, event.ChannelID) {
+			sendBotResponse(event, d.adminChannelHint(command), "")
+			return
+		}
+	}
+
+	if !d.commands.start() {
+		sendBotResponse(event, shutdownMessage, "")
+		return
+	}
+	defer d.commands.finish()
+
+	league, args, err := d.resolveLeague(event.ChannelID, args)
+	if err != nil {
+		sendBotResponse(event, err.Error(), "")
+		return
+	}
+	league.handleCommand(event, command, args)
+}
+
+// handleCommand runs command for this client's league. Callers have already
+// checked the author may run it.
+func (d *DiscordClient) handleCommand(event *events.MessageCreate, command string, args []string) {
+	if mutatingCommands[command] && !hasDryRunFlag(args) {
+		release, err := d.running.tryAcquire(fmt.Sprintf("`%s`", command), event.Message.Author.ID)
+		if err != nil {
+			sendBotResponse(event, err.Error()+" Try again once it finishes.", "")
+			return
+		}
+		defer release()
+	}
+	switch command {
+	case "!link":
+		d.link(event, args)
+	case "!penalty-dms":
+		d.penaltyDMs(event, args)
+	case "!excuse":
+		d.excuse(event, args)
 	case "!announce-penalties":
-		d.announcePenalties(event)
+		d.announcePenalties(event, args)
 	case "!race-setup":
-		d.raceSetup(event)
+		d.raceSetup(event, args)
+	case "!rollback-race-setup":
+		d.rollbackRaceSetup(event, args)
 	case "!new-season":
 		d.newSeason(event, false)
 	case "!new-season-apply":
 		d.newSeason(event, true)
+	case "!link-driver":
+		d.linkDriver(event, args)
+	case "!unlinked":
+		d.unlinked(event)
+	case "!status":
+		d.status(event)
+	case "!validate-templates":
+		d.validateTemplates(event)
+	case "!roster":
+		d.roster(event)
+	case "!sync-roles":
+		d.syncRoles(event, args)
+	case "!set-round":
+		d.setRound(event)
+	case "!jobs":
+		d.jobs(event)
+	case "!reminders":
+		d.reminders(event, args)
+	case "!attendance":
+		d.attendance(event, args)
+	case "!absence-penalties":
+		d.absencePenalties(event, args)
+	case "!amend-penalties":
+		d.amendPenalties(event, args)
+	}
+}
+
+func (d *DiscordClient) link(event *events.MessageCreate, args []string) {
+	var msg, attachment string
+	defer func() { sendBotResponse(event, msg, attachment) }()
+	sgClient := d.newSimGridClient()
+	var err error
+	msg, attachment, err = d.runLink(event.Message.Author.ID, args, sgClient)
+	if err != nil {
+		msg = err.Error()
+	}
+}
+
+func (d *DiscordClient) penaltyDMs(event *events.MessageCreate, args []string) {
+	var msg, attachment string
+	defer func() { sendBotResponse(event, msg, attachment) }()
+	var err error
+	msg, attachment, err = d.runPenaltyDMs(event.Message.Author.ID, args)
+	if err != nil {
+		msg = err.Error()
+	}
+}
+
+func (d *DiscordClient) linkDriver(event *events.MessageCreate, args []string) {
+	var msg, attachment string
+	defer func() { sendBotResponse(event, msg, attachment) }()
+	sgClient := d.newSimGridClient()
+	var err error
+	msg, attachment, err = d.runLinkDriver(event.Message.Author.ID, args, sgClient)
+	if err != nil {
+		msg = err.Error()
 	}
 }
 
-// helpMessage returns the admin-facing command reference posted in response
-// to the !help command.
-func helpMessage() string {
-	return "**Rookies Bot — Commands**\n\n" +
-		"`!help`\n" +
-		"  Show this message.\n\n" +
-		"`!announce-penalties`\n" +
-		"  Attach a round penalty YAML; posts the formatted penalty breakdown (quali bans / pit starts, R1 & R2).\n\n" +
-		"`!race-setup`\n" +
-		"  Attach a round penalty YAML; generates the next round config and race-day setup.\n\n" +
-		"`!new-season`\n" +
-		"  Preview the next-season reconfiguration (championship, schedule, config values). Makes no changes.\n\n" +
-		"`!new-season-apply`\n" +
-		"  Apply the next-season reconfiguration: create Drive folders, update the bot config live, and post the round-0 config.\n"
+func (d *DiscordClient) unlinked(event *events.MessageCreate) {
+	var msg, attachment string
+	defer func() { sendBotResponse(event, msg, attachment) }()
+	sgClient := d.newSimGridClient()
+	var err error
+	msg, attachment, err = d.runUnlinked(sgClient)
+	if err != nil {
+		msg = err.Error()
+	}
 }
 
 func sendBotResponse(event *events.MessageCreate, msg, attachment string) {
-	if msg != "" {
-		dm := discord.MessageCreate{
-			Content: msg,
+	if msg == "" {
+		fmt.Printf("No response message content provided\n")
+		return
+	}
+	// Long replies go out as several messages: the first replies to the
+	// command and the last carries the attachment.
+	parts := splitMessage(msg)
+	for i, part := range parts {
+		dm := discord.MessageCreate{Content: part}
+		if i == 0 {
 			// Reply to the original message by using MessageReference
-			MessageReference: &discord.MessageReference{
+			dm.MessageReference = &discord.MessageReference{
 				MessageID: &event.Message.ID,
-			},
+			}
 		}
-		if attachment != "" {
+		if attachment != "" && i == len(parts)-1 {
 			file, err := os.Open(attachment) // #nosec G304 -- path written by this process from config, not user input
 			if err != nil {
 				fmt.Printf("Error attaching file %s: %s\n", attachment, err)
@@ -268,12 +492,13 @@ func sendBotResponse(event *events.MessageCreate, msg, attachment string) {
 		if err != nil {
 			fmt.Println("Error sending message:", err)
 		}
-	} else {
-		fmt.Printf("No response message content provided\n")
 	}
 }
 func (d *DiscordClient) runAnnouncePenalties(roundConfig *config.RoundConfig, sgClient *simgrid.SimGridClient) (string, string, error) {
 	conf := d.snapshotConfig()
+	if err := d.mergeAbsencePenalties(conf.Season, roundConfig); err != nil {
+		return "", "", err
+	}
 	driverLookup, err := sgClient.BuildDriverLookup(conf.ChampionshipId)
 	if err != nil {
 		return "", "", fmt.Errorf("failed building driver list: %w", err)
@@ -299,19 +524,46 @@ func (d *DiscordClient) runAnnouncePenalties(roundConfig *config.RoundConfig, sg
 		return "", "", fmt.Errorf("failed to pin penalty announcement: %w", err)
 	}
 
-	return fmt.Sprintf("Ok, I have announced penalties from %s", roundConfig.PreviousRound), "", nil
+	msgText := fmt.Sprintf("Ok, I have announced penalties from %s", roundConfig.PreviousRound)
+	msgText += d.recordRound(jobAnnouncePenalties, roundConfig.PreviousRound, roundConfig)
+	msgText += d.recordAnnouncement(roundConfig, func(posted *postedAnnouncement) {
+		posted.Penalties = &messageRef{ChannelID: conf.DiscordChannelId, MessageID: sentMsg.ID}
+	})
+
+	// Questions about the penalties go in the penalized round's thread.
+	notice := buildMessage(fmt.Sprintf("Penalties from %s are announced in <#%s>. Ask about them here.", roundConfig.PreviousRound, conf.DiscordChannelId))
+	if _, err := d.postToRoundThread(conf.Season, roundConfig.PreviousRound, threadDiscussion, notice); err != nil {
+		msgText = fmt.Sprintf("%s\n\nCould not post to the %s discussion thread: %s", msgText, roundConfig.PreviousRound, err)
+	}
+
+	// The announcement is already public, so DM failures are reported rather
+	// than failing the command.
+	sent, failed, err := d.sendPenaltyDMs(penaltyList, roundConfig)
+	if err != nil {
+		msgText = fmt.Sprintf("%s\n\nFailed sending penalty DMs: %s", msgText, err)
+	} else {
+		msgText = fmt.Sprintf("%s\n\n%s", msgText, penaltyDMReport(sent, failed))
+	}
+	return d.reportUnresolvedDrivers(msgText, penaltyList, roundConfig.PreviousRound), "", nil
 }
 
-func (d *DiscordClient) announcePenalties(event *events.MessageCreate) {
+func (d *DiscordClient) announcePenalties(event *events.MessageCreate, args []string) {
+	if hasDryRunFlag(args) {
+		d.announcePenaltiesDryRun(event)
+		return
+	}
 	var msg, attachment string
 	defer func() { sendBotResponse(event, msg, attachment) }()
-	roundConfig, err := getRoundConfig(event)
+	roundConfig, hash, err := getRoundConfigWithHash(event)
 	if err != nil {
 		msg = fmt.Sprintf("Failed getting race config: %s", err)
 		return
 	}
-	sgClient := simgrid.NewClient(d.snapshotConfig().SimGridApiToken)
-	msg, attachment, err = d.runAnnouncePenalties(roundConfig, sgClient)
+	sgClient := d.newSimGridClient()
+	key := idempotencyKey(d.snapshotConfig().Season, "!announce-penalties", roundConfig.PreviousRound, hash)
+	msg, attachment, err = d.runOnce(key, "!announce-penalties", roundConfig.PreviousRound, event.Message.Author.ID, func() (string, string, error) {
+		return d.runAnnouncePenalties(roundConfig, sgClient)
+	})
 	if err != nil {
 		msg = err.Error()
 	}
@@ -319,6 +571,9 @@ func (d *DiscordClient) announcePenalties(event *events.MessageCreate) {
 
 func (d *DiscordClient) runRaceSetup(roundConfig *config.RoundConfig, sgClient *simgrid.SimGridClient, gcClient *gcloud.Client) (string, string, error) {
 	conf := d.snapshotConfig()
+	if err := d.mergeAbsencePenalties(conf.Season, roundConfig); err != nil {
+		return "", "", err
+	}
 	driverLookup, err := sgClient.BuildDriverLookup(conf.ChampionshipId)
 	if err != nil {
 		return "", "", err
@@ -329,13 +584,29 @@ func (d *DiscordClient) runRaceSetup(roundConfig *config.RoundConfig, sgClient *
 		return "", "", err
 	}
 
+	// The briefing doc, the race-day timestamp and the scheduled event all
+	// use this one time.
+	briefingTime, err := d.briefingTime(roundConfig.NextRound)
+	if err != nil {
+		return "", "", err
+	}
+
+	journal, err := d.startRaceSetupJournal(roundConfig, time.Now())
+	if err != nil {
+		return "", "", err
+	}
+
 	briefingUrl, err := gcClient.GenerateBriefing(&config.Config{
 		RoundConfig: *roundConfig,
 		BotConfig:   conf,
-	}, penalties)
+	}, penalties, briefingTime)
+	if briefingUrl != "" {
+		journal.record(setupOperation{Kind: opBriefingDoc, FileID: gcloud.BriefingDocID(briefingUrl)})
+	}
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate briefing doc: %w", err)
+		return "", "", journal.fail(fmt.Errorf("failed to generate briefing doc: %w", err))
 	}
+	briefingPDF, pdfNote := d.exportBriefingPDF(gcClient, briefingUrl, roundConfig.NextRound, journal)
 
 	var attachment string
 	var nextRoundConfig *config.RoundConfig
@@ -346,65 +617,137 @@ func (d *DiscordClient) runRaceSetup(roundConfig *config.RoundConfig, sgClient *
 		}
 		nextRoundConfig, err = generateNextRoundConfig(sgClient, gcClient, bigConfig, penalties)
 		if err != nil {
-			return "", "", fmt.Errorf("failed to generate config for next round: %w", err)
+			return "", "", journal.fail(fmt.Errorf("failed to generate config for next round: %w", err))
 		}
+		journal.record(setupOperation{Kind: opPenaltyTracker, FileID: gcloud.TrackerID(nextRoundConfig.PreviousRound.PenaltyTrackerLink)})
 		attachment, err = writeNextRoundConfig(nextRoundConfig, conf.Season)
 		if err != nil {
-			return "", "", err
+			return "", "", journal.fail(err)
 		}
+		journal.record(setupOperation{Kind: opNextRoundFile, Path: attachment})
 	}
 
-	msg, err := d.BuildBriefingMessage(penalties, briefingUrl, roundConfig)
+	msg, err := d.BuildBriefingMessage(penalties, briefingUrl, roundConfig, briefingTime)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate briefingmessage: %w", err)
+		return "", "", journal.fail(fmt.Errorf("failed to generate briefingmessage: %w", err))
+	}
+	if briefingPDF != nil {
+		msg.Files = append(msg.Files, briefingPDF)
 	}
 
 	sentMsg, err := d.SendMessage(msg)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to send briefing announcement: %w", err)
+		return "", "", journal.fail(fmt.Errorf("failed to send briefing announcement: %w", err))
 	}
+	journal.record(setupOperation{Kind: opMessage, ChannelID: conf.DiscordChannelId, MessageID: sentMsg.ID})
 
-	err = d.Repin(sentMsg)
+	unpinned, err := d.repin(sentMsg)
+	for _, id := range unpinned {
+		journal.record(setupOperation{Kind: opUnpin, ChannelID: conf.DiscordChannelId, MessageID: id})
+	}
 	if err != nil {
-		return "", "", fmt.Errorf("failed to pin briefing announcement: %w", err)
+		return "", "", journal.fail(fmt.Errorf("failed to pin briefing announcement: %w", err))
 	}
 
-	err = d.CreateBriefingEvent(roundConfig)
+	event, err := d.createBriefingEvent(roundConfig, briefingTime)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to create briefing event: %w", err)
+		return "", "", journal.fail(fmt.Errorf("failed to create briefing event: %w", err))
 	}
+	journal.record(setupOperation{Kind: opScheduledEvent, EventID: event.ID})
 
 	msgText := fmt.Sprintf("Race setup for %s complete!\n", roundConfig.NextRound)
 	if nextRoundConfig != nil {
 		msgText = fmt.Sprintf("%s\n[Penalty Tracker](%s)\n", msgText, nextRoundConfig.PreviousRound.PenaltyTrackerLink)
 	}
-
-	if len(penalties.UniqueDriverNumbers()) > 0 {
-		msgText = fmt.Sprintf("%s\nDQ List:\n```", msgText)
-		for _, carNum := range penalties.UniqueDriverNumbers() {
-			msgText = fmt.Sprintf("%s\n/dq %d\n", msgText, carNum)
+	msgText += pdfNote
+	msgText += d.createRoundThreads(roundConfig, sentMsg, journal)
+	msgText += d.recordAnnouncement(roundConfig, func(posted *postedAnnouncement) {
+		posted.RaceDay = &messageRef{ChannelID: conf.DiscordChannelId, MessageID: sentMsg.ID}
+		posted.BriefingDocURL = briefingUrl
+		posted.BriefingTime = briefingTime
+	})
+	msgText += d.scheduleReminders(roundConfig, briefingTime, briefingUrl, penalties, time.Now())
+	msgText += d.trackAttendance(roundConfig, briefingTime)
+	if nextRoundConfig != nil {
+		var previous *storedRound
+		if err := d.state.Load(currentRoundDocument, &previous); err == nil {
+			journal.record(setupOperation{Kind: opStoredRound, Previous: previous})
 		}
-		msgText = fmt.Sprintf("%s\n```", msgText)
 	}
+	msgText += d.recordRound(jobRaceSetup, roundConfig.NextRound, nextRoundConfig)
+	msgText += journal.complete()
+
+	msgText += dqList(penalties)
+
+	return d.reportUnresolvedDrivers(msgText, penalties, roundConfig.NextRound), attachment, nil
+}
+
+// dqList lists the /dq commands for every penalized car, if there are any.
+func dqList(penalties *models.Penalties) string {
+	if len(penalties.UniqueDriverNumbers()) == 0 {
+		return ""
+	}
+	list := "\nDQ List:\n```"
+	for _, carNum := range penalties.UniqueDriverNumbers() {
+		list = fmt.Sprintf("%s\n/dq %d\n", list, carNum)
+	}
+	return list + "\n```"
+}
+
+// withUnresolvedDrivers appends the report of penalized drivers who could not
+// be mentioned to an admin response. The announcement already went out, so a
+// failure here is noted rather than returned.
+func (d *DiscordClient) withUnresolvedDrivers(msg string, penalties *models.Penalties) string {
+	report, err := d.unresolvedDriversReport(penalties.UniqueDrivers())
+	if err != nil {
+		return fmt.Sprintf("%s\n\nCould not check for unlinked drivers: %s", msg, err)
+	}
+	if report == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s\n\n%s", msg, report)
+}
 
-	return msgText, attachment, nil
+// reportUnresolvedDrivers is withUnresolvedDrivers for a command that posted
+// round's announcements: the report also goes to the round's stewards thread.
+func (d *DiscordClient) reportUnresolvedDrivers(msg string, penalties *models.Penalties, round config.Round) string {
+	report, err := d.unresolvedDriversReport(penalties.UniqueDrivers())
+	if err != nil {
+		return fmt.Sprintf("%s\n\nCould not check for unlinked drivers: %s", msg, err)
+	}
+	if report == "" {
+		return msg
+	}
+	msg = fmt.Sprintf("%s\n\n%s", msg, report)
+	if err := d.postToStewards(round, report); err != nil {
+		msg = fmt.Sprintf("%s\n\nCould not post to the %s stewards thread: %s", msg, round, err)
+	}
+	return msg
 }
 
-func (d *DiscordClient) raceSetup(event *events.MessageCreate) {
+func (d *DiscordClient) raceSetup(event *events.MessageCreate, args []string) {
+	if hasDryRunFlag(args) {
+		d.raceSetupDryRun(event)
+		return
+	}
 	var msg, attachment string
 	defer func() { sendBotResponse(event, msg, attachment) }()
-	roundConfig, err := getRoundConfig(event)
+	roundConfig, hash, err := getRoundConfigWithHash(event)
 	if err != nil {
 		msg = err.Error()
 		return
 	}
-	sgClient := simgrid.NewClient(d.snapshotConfig().SimGridApiToken)
-	gcClient, err := gcloud.NewClient(context.Background())
+	sgClient := d.newSimGridClient()
+	gcClient, err := d.newGCloudClient()
 	if err != nil {
 		msg = err.Error()
 		return
 	}
-	msg, attachment, err = d.runRaceSetup(roundConfig, sgClient, gcClient)
+	key := idempotencyKey(d.snapshotConfig().Season, "!race-setup", roundConfig.NextRound, hash)
+	msg, attachment, err = d.runOnce(key, "!race-setup", roundConfig.NextRound, event.Message.Author.ID, func() (string, string, error) {
+		d.reportRosterBeforeRaceSetup(sgClient, roundConfig.NextRound)
+		return d.runRaceSetup(roundConfig, sgClient, gcClient)
+	})
 	if err != nil {
 		msg = err.Error()
 	}
@@ -414,7 +757,7 @@ func (d *DiscordClient) newSeason(event *events.MessageCreate, apply bool) {
 	var msg, attachment string
 	defer func() { sendBotResponse(event, msg, attachment) }()
 
-	sgClient := simgrid.NewClient(d.snapshotConfig().SimGridApiToken)
+	sgClient := d.newSimGridClient()
 	var err error
 	msg, attachment, err = d.runNewSeason(apply, sgClient)
 	if err != nil {
@@ -462,19 +805,18 @@ func (d *DiscordClient) runNewSeason(apply bool, sgClient *simgrid.SimGridClient
 	champID := strconv.Itoa(champ.ID)
 
 	// apply: create the season's Drive folders (idempotent find-or-create)
-	ctx := context.Background()
-	briefingID, err := d.gcloud.EnsureSeasonFolder(ctx, conf.BriefingFolderID, season)
+	briefingID, err := d.gcloud.EnsureSeasonFolder(d.ctx, conf.BriefingFolderID, season)
 	if err != nil {
 		return "", "", fmt.Errorf("failed setting up briefing folder: %w", err)
 	}
-	trackerID, err := d.gcloud.EnsureSeasonFolder(ctx, conf.TrackerFolderID, season)
+	trackerID, err := d.gcloud.EnsureSeasonFolder(d.ctx, conf.TrackerFolderID, season)
 	if err != nil {
 		return "", "", fmt.Errorf("failed setting up tracker folder: %w", err)
 	}
 
 	// Generate the round-0 config before committing any config change, so that a
 	// failure here leaves the existing config (file and in-memory) untouched.
-	attachment, err := writeRoundZeroConfig(season, round1)
+	attachment, err := writeRoundZeroConfig(season, config.Round{Number: 1, Track: round1, Date: champ.Races[0].StartsAt})
 	if err != nil {
 		return "", "", fmt.Errorf("failed generating round-0 config: %w", err)
 	}
@@ -495,7 +837,13 @@ func (d *DiscordClient) runNewSeason(apply bool, sgClient *simgrid.SimGridClient
 		"briefing_folder_id": briefingID,
 		"tracker_folder_id":  trackerID,
 	}
-	if err := config.UpdateBotConfigFile(d.configPath, updates); err != nil {
+	updateConfigFile := config.UpdateBotConfigFile
+	if conf.League != "" {
+		updateConfigFile = func(path string, updates map[string]string) error {
+			return config.UpdateLeagueConfigFile(path, conf.League, updates)
+		}
+	}
+	if err := updateConfigFile(d.configPath, updates); err != nil {
 		return "", "", fmt.Errorf("failed updating config file: %w", err)
 	}
 
@@ -508,16 +856,29 @@ func (d *DiscordClient) runNewSeason(apply bool, sgClient *simgrid.SimGridClient
 	d.conf.TrackerFolderID = trackerID
 	d.mu.Unlock()
 
+	msg, err := d.messages.render(templateNewSeasonApplied, NewSeasonData{
+		Season:           season,
+		Championship:     champ,
+		RoleName:         role,
+		Round1Track:      round1,
+		BriefingFolderID: briefingID,
+		TrackerFolderID:  trackerID,
+	})
+	if err != nil {
+		// The new season is already live at this point; still report it.
+		msg = fmt.Sprintf("New season %s applied, but %s", season, err)
+	}
+
 	committed = true
-	return buildNewSeasonApplied(champ, season, role, round1, briefingID, trackerID), attachment, nil
+	return msg, attachment, nil
 }
 
 // writeRoundZeroConfig writes a round-0 config (next round = round 1 at the
 // season opener, no penalties, no previous round) to the working directory and
 // returns the file name.
-func writeRoundZeroConfig(season, round1Track string) (string, error) {
+func writeRoundZeroConfig(season string, round1 config.Round) (string, error) {
 	rc := &config.RoundConfig{
-		NextRound: config.Round{Number: 1, Track: round1Track},
+		NextRound: round1,
 	}
 	data, err := yaml.Marshal(rc)
 	if err != nil {
@@ -531,23 +892,6 @@ func writeRoundZeroConfig(season, round1Track string) (string, error) {
 	return fileName, nil
 }
 
-// buildNewSeasonApplied renders the confirmation posted after a successful
-// apply. It omits secrets and explains the next step.
-func buildNewSeasonApplied(champ *simgrid.Championship, season, role, round1, briefingID, trackerID string) string {
-	var b strings.Builder
-	fmt.Fprintf(&b, "✅ **New Season Applied: %s**\n\n", season)
-	fmt.Fprintf(&b, "Championship: %s (#%d)\n", champ.Name, champ.ID)
-	fmt.Fprintf(&b, "Updated config:\n")
-	fmt.Fprintf(&b, "  season             %s\n", season)
-	fmt.Fprintf(&b, "  championship_id    %d\n", champ.ID)
-	fmt.Fprintf(&b, "  discord_role_name  %s\n", role)
-	fmt.Fprintf(&b, "  briefing_folder_id %s\n", briefingID)
-	fmt.Fprintf(&b, "  tracker_folder_id  %s\n", trackerID)
-	fmt.Fprintf(&b, "\nThe bot is now using the new season — no restart needed.\n")
-	fmt.Fprintf(&b, "Attached round-0 config announces Round 1 — %s. Run `!race-setup` with it to announce week 1.", round1)
-	return b.String()
-}
-
 // buildNewSeasonPreview renders the read-only proposal. It deliberately omits
 // secrets and shows only the season-level values that will change.
 func buildNewSeasonPreview(champ *simgrid.Championship, season, role, round1 string) string {
@@ -571,106 +915,172 @@ func buildNewSeasonPreview(champ *simgrid.Championship, season, role, round1 str
 
 func NewDiscordClient(conf *config.Config, gc *gcloud.Client, configPath string) (*DiscordClient, error) {
 	client, err := disgo.New(conf.DiscordToken, bot.WithGatewayConfigOpts(
-		gateway.WithIntents(gateway.IntentMessageContent, gateway.IntentDirectMessages),
+		gateway.WithIntents(gateway.IntentMessageContent, gateway.IntentDirectMessages,
+			gateway.IntentGuilds, gateway.IntentGuildMessages, gateway.IntentGuildMembers, gateway.IntentGuildVoiceStates),
 	))
 	if err != nil {
 		return nil, err
 	}
 
+	msgs, errs := loadMessages(conf.TemplatesDir)
+	for _, err := range errs {
+		fmt.Printf("Template warning: %s\n", err)
+	}
+
 	dc := &DiscordClient{
 		conf:          conf,
 		botClient:     client,
 		rest:          client.Rest,
 		applicationID: client.ApplicationID,
 		gcloud:        gc,
+		connectGCloud: gcloud.NewClient,
 		configPath:    configPath,
+		messages:      msgs,
+		members:       newGuildMembers(),
+		state:         state.NewStore(conf.StateDir),
+		commands:      &commandTracker{},
+		running:       newLeagueLock(),
+	}
+	dc.drivers = dc.state
+	dc.ctx, dc.cancel = context.WithCancel(context.Background())
+
+	if len(conf.Leagues) > 0 {
+		for _, leagueConf := range conf.LeagueConfigs() {
+			league, err := dc.newLeagueClient(leagueConf)
+			if err != nil {
+				return nil, err
+			}
+			dc.leagues = append(dc.leagues, league)
+		}
+	} else if conf.Jobs != (config.Jobs{}) {
+		dc.scheduler, err = dc.newScheduler(conf.BotConfig)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	client.AddEventListeners(bot.NewListenerFunc(dc.onMessageCreate))
+	client.AddEventListeners(
+		bot.NewListenerFunc(dc.onMessageCreate),
+		bot.NewListenerFunc(dc.onGuildMemberJoin),
+		bot.NewListenerFunc(dc.onGuildMemberUpdate),
+		bot.NewListenerFunc(dc.onGuildMemberLeave),
+		bot.NewListenerFunc(dc.onGuildVoiceStateUpdate),
+	)
 	return dc, nil
 }
 
 func (d *DiscordClient) OpenGateway(ctx context.Context) error {
-	return d.botClient.OpenGateway(ctx)
+	if err := d.botClient.OpenGateway(ctx); err != nil {
+		return err
+	}
+	for _, league := range d.leagueClients() {
+		league.startBackground()
+	}
+	return nil
 }
+
+// Close stops accepting commands, waits for those in flight (see shutdown),
+// then disconnects from Discord.
 func (d *DiscordClient) Close(ctx context.Context) {
+	d.shutdown(ctx)
 	d.botClient.Close(ctx)
 }
 
 func (d *DiscordClient) BuildPenaltyMessage(penalties *models.Penalties, config *config.RoundConfig) (discord.MessageCreate, error) {
-	message := fmt.Sprintf(`
-🚓 **Penalties from Round %d** 🚓 
-
-Stewarding is in from Round %d. The following penalties are to be served next week at %s:
-`, config.PreviousRound.Number, config.PreviousRound.Number, config.NextRound.Track)
+	if d.useEmbeds() {
+		return d.buildPenaltyEmbedMessage(penalties, config)
+	}
 
-	penaltyMessage, err := d.generatePenaltyMessage(penalties, config)
+	sections, err := d.buildPenaltySections(penalties)
 	if err != nil {
 		return discord.MessageCreate{}, err
 	}
-	message += penaltyMessage
 
+	message, err := d.messages.render(templatePenalties, d.announcementData(config, sections))
+	if err != nil {
+		return discord.MessageCreate{}, err
+	}
 	return buildMessage(message), nil
-
 }
 
-func (d *DiscordClient) BuildBriefingMessage(penalties *models.Penalties, briefingUrl string, config *config.RoundConfig) (discord.MessageCreate, error) {
+func (d *DiscordClient) BuildBriefingMessage(penalties *models.Penalties, briefingUrl string, config *config.RoundConfig, briefingTime time.Time) (discord.MessageCreate, error) {
 	role, err := d.lookupRole(d.snapshotConfig().DiscordRoleName)
 	if err != nil {
 		return discord.MessageCreate{}, err
 	}
 
-	briefingTime, err := d.briefingTime()
+	if d.useEmbeds() {
+		return d.buildBriefingEmbedMessage(penalties, briefingUrl, config, role, briefingTime)
+	}
+
+	sections, err := d.buildPenaltySections(penalties)
 	if err != nil {
 		return discord.MessageCreate{}, err
 	}
 
-	message := fmt.Sprintf(`
-🏎 **It's Race Day!!** 🏎
-
-<@&%s> **Mandatory** drivers' briefing is at <t:%d>. Here's the [briefing doc](%s) for Round %d.
+	data := d.announcementData(config, sections)
+	data.RoleMention = fmt.Sprintf("<@&%s>", role.ID)
+	data.BriefingTime = briefingTime
+	data.BriefingTimestamp = fmt.Sprintf("<t:%d>", briefingTime.Unix())
+	data.BriefingDocURL = briefingUrl
 
-**Penalties to be Served This Week**
-`, role.ID, briefingTime.Unix(), briefingUrl, config.NextRound.Number)
-
-	penaltyMessage, err := d.generatePenaltyMessage(penalties, config)
+	message, err := d.messages.render(templateRaceDay, data)
 	if err != nil {
 		return discord.MessageCreate{}, err
 	}
-	message += penaltyMessage
-
 	return buildMessage(message), nil
 }
 
+// announcementData fills in the template fields shared by every announcement.
+func (d *DiscordClient) announcementData(roundConfig *config.RoundConfig, sections []penaltySection) AnnouncementData {
+	return AnnouncementData{
+		Round:         roundConfig.NextRound,
+		PreviousRound: roundConfig.PreviousRound,
+		Season:        d.snapshotConfig().Season,
+		TrackerURL:    roundConfig.PreviousRound.PenaltyTrackerLink,
+		Penalties:     sections,
+	}
+}
+
 func (d *DiscordClient) SendMessage(message discord.MessageCreate) (*discord.Message, error) {
 	return d.rest.CreateMessage(d.conf.DiscordChannelId, message)
 }
 
 func (d *DiscordClient) Repin(message *discord.Message) error {
+	_, err := d.repin(message)
+	return err
+}
+
+// repin unpins the bot's pinned messages and pins message, returning the
+// messages it unpinned, even when it fails partway.
+func (d *DiscordClient) repin(message *discord.Message) ([]snowflake.ID, error) {
 	pins, err := d.rest.GetChannelPins(d.conf.DiscordChannelId, time.Time{}, 0)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	var unpinned []snowflake.ID
 	for _, pin := range pins.Items {
 		if pin.Message.Author.ID == d.applicationID {
 			err := d.rest.UnpinMessage(d.conf.DiscordChannelId, pin.Message.ID)
 			if err != nil {
-				return err
+				return unpinned, err
 			}
+			unpinned = append(unpinned, pin.Message.ID)
 		}
 	}
 
-	return d.rest.PinMessage(d.conf.DiscordChannelId, message.ID)
+	return unpinned, d.rest.PinMessage(d.conf.DiscordChannelId, message.ID)
+}
+
+func (d *DiscordClient) CreateBriefingEvent(config *config.RoundConfig, briefingTime time.Time) error {
+	_, err := d.createBriefingEvent(config, briefingTime)
+	return err
 }
 
-func (d *DiscordClient) CreateBriefingEvent(config *config.RoundConfig) error {
+func (d *DiscordClient) createBriefingEvent(config *config.RoundConfig, briefingTime time.Time) (*discord.GuildScheduledEvent, error) {
 	guildId, err := d.getGuild()
 	if err != nil {
-		return err
-	}
-	briefingTime, err := d.briefingTime()
-	if err != nil {
-		return err
+		return nil, err
 	}
 	event := discord.GuildScheduledEventCreate{
 		Name:               fmt.Sprintf("Rookies Briefing Round %d - %s", config.NextRound.Number, config.NextRound.Track),
@@ -679,39 +1089,171 @@ func (d *DiscordClient) CreateBriefingEvent(config *config.RoundConfig) error {
 		PrivacyLevel:       discord.ScheduledEventPrivacyLevelGuildOnly,
 		EntityType:         discord.ScheduledEventEntityTypeStageInstance,
 	}
-	_, err = d.rest.CreateGuildScheduledEvent(guildId, event)
-	return err
+	return d.rest.CreateGuildScheduledEvent(guildId, event)
 }
 
 func buildMessage(message string) discord.MessageCreate {
 	return discord.NewMessageCreate().WithContent(message).WithSuppressEmbeds(true)
 }
 
-func (d *DiscordClient) briefingTime() (time.Time, error) {
-	location, err := time.LoadLocation("America/New_York")
+// maxMessageLength is the most characters Discord accepts in a message.
+const maxMessageLength = 2000
+
+// splitMessage breaks text into messages Discord accepts, splitting between
+// lines where it can and mid-line only for a line too long on its own.
+func splitMessage(text string) []string {
+	var parts []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for utf8.RuneCountInString(line) > maxMessageLength {
+			if current.Len() > 0 {
+				parts = append(parts, current.String())
+				current.Reset()
+			}
+			runes := []rune(line)
+			parts = append(parts, string(runes[:maxMessageLength]))
+			line = string(runes[maxMessageLength:])
+		}
+		if utf8.RuneCountInString(current.String())+utf8.RuneCountInString(line) > maxMessageLength {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 || len(parts) == 0 {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
+
+// Embed accent colors for the two announcement types.
+const (
+	penaltyEmbedColor = 0xC62828
+	raceDayEmbedColor = 0x2E7D32
+)
+
+// useEmbeds reports whether announcements should be rendered as rich embeds
+// rather than plain text.
+func (d *DiscordClient) useEmbeds() bool {
+	return d.snapshotConfig().MessageFormat == config.MessageFormatEmbed
+}
+
+func (d *DiscordClient) buildPenaltyEmbedMessage(penalties *models.Penalties, roundConfig *config.RoundConfig) (discord.MessageCreate, error) {
+	sections, err := d.buildPenaltySections(penalties)
+	if err != nil {
+		return discord.MessageCreate{}, err
+	}
+
+	embed := discord.NewEmbed().
+		WithTitlef("🚓 Penalties from %s", roundConfig.PreviousRound).
+		WithDescriptionf("Stewarding is in from Round %d. The following penalties are to be served next week at %s.",
+			roundConfig.PreviousRound.Number, roundConfig.NextRound.Track).
+		WithColor(penaltyEmbedColor).
+		AddFields(penaltyEmbedFields(sections)...)
+
+	var buttons []discord.InteractiveComponent
+	if roundConfig.PreviousRound.PenaltyTrackerLink != "" {
+		buttons = append(buttons, discord.NewLinkButton("Penalty Explanations", roundConfig.PreviousRound.PenaltyTrackerLink))
+	}
+
+	// Mentions inside an embed never notify anyone, so the penalized drivers
+	// are also mentioned in the message content.
+	return buildEmbedMessage(sectionMentions(sections), embed, buttons), nil
+}
+
+func (d *DiscordClient) buildBriefingEmbedMessage(penalties *models.Penalties, briefingUrl string, roundConfig *config.RoundConfig, role discord.Role, briefingTime time.Time) (discord.MessageCreate, error) {
+	sections, err := d.buildPenaltySections(penalties)
 	if err != nil {
-		return time.Time{}, err
+		return discord.MessageCreate{}, err
 	}
 
-	now := time.Now().In(location)
+	embed := discord.NewEmbed().
+		WithTitlef("🏎 It's Race Day! %s", roundConfig.NextRound).
+		WithDescription("**Mandatory** drivers' briefing tonight. Penalties to be served this week are listed below.").
+		WithColor(raceDayEmbedColor).
+		AddField("Drivers' Briefing", fmt.Sprintf("%s (%s)",
+			discord.TimestampStyleLongDateTime.FormatTime(briefingTime),
+			discord.TimestampStyleRelative.FormatTime(briefingTime)), false).
+		AddFields(penaltyEmbedFields(sections)...)
+
+	var buttons []discord.InteractiveComponent
+	if briefingUrl != "" {
+		buttons = append(buttons, discord.NewLinkButton("Briefing Doc", briefingUrl))
+	}
+	if roundConfig.PreviousRound.PenaltyTrackerLink != "" {
+		buttons = append(buttons, discord.NewLinkButton("Penalty Tracker", roundConfig.PreviousRound.PenaltyTrackerLink))
+	}
+
+	content := fmt.Sprintf("<@&%s>", role.ID)
+	if mentions := sectionMentions(sections); mentions != "" {
+		content += " " + mentions
+	}
+	return buildEmbedMessage(content, embed, buttons), nil
+}
+
+// penaltyEmbedFields renders one embed field per penalty category.
+func penaltyEmbedFields(sections []penaltySection) []discord.EmbedField {
+	fields := make([]discord.EmbedField, 0, len(sections))
+	for _, section := range sections {
+		value := "None!"
+		if len(section.Lines) > 0 {
+			value = "- " + strings.Join(section.Lines, "\n- ")
+		}
+		inline := true
+		fields = append(fields, discord.EmbedField{Name: section.Title, Value: value, Inline: &inline})
+	}
+	return fields
+}
+
+// sectionMentions returns a space-separated mention for every resolved driver
+// across all sections, each driver listed once.
+func sectionMentions(sections []penaltySection) string {
+	seen := map[snowflake.ID]struct{}{}
+	var mentions []string
+	for _, section := range sections {
+		for _, id := range section.Mentions {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+		}
+	}
+	return strings.Join(mentions, " ")
+}
+
+func buildEmbedMessage(content string, embed discord.Embed, buttons []discord.InteractiveComponent) discord.MessageCreate {
+	message := discord.NewMessageCreate().WithContent(content).WithEmbeds(embed)
+	if len(buttons) > 0 {
+		message = message.WithComponents(discord.NewActionRow(buttons...))
+	}
+	return message
+}
 
-	dayOffset := (time.Monday + 7 - now.Weekday()) % 7
-	targetDate := now.AddDate(0, 0, int(dayOffset))
-	return time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 19, 30, 00, 00, location), nil
+// briefingTime returns when the drivers' briefing for round starts, per the
+// configured schedule.
+func (d *DiscordClient) briefingTime(round config.Round) (time.Time, error) {
+	return d.snapshotConfig().Schedule.BriefingTimeFor(round, time.Now())
 }
 
 func (d *DiscordClient) getGuild() (snowflake.ID, error) {
-	if d.guild != 0 {
-		return d.guild, nil
+	d.mu.RLock()
+	guild := d.guild
+	d.mu.RUnlock()
+	if guild != 0 {
+		return guild, nil
 	}
 
-	channel, err := d.rest.GetChannel(d.conf.DiscordChannelId)
+	channel, err := d.rest.GetChannel(d.snapshotConfig().DiscordChannelId)
 	if err != nil {
 		return 0, err
 	}
 	if channel.Type() == discord.ChannelTypeGuildText {
-		d.guild = channel.(discord.GuildChannel).GuildID()
-		return d.guild, nil
+		guild = channel.(discord.GuildChannel).GuildID()
+		d.mu.Lock()
+		d.guild = guild
+		d.mu.Unlock()
+		return guild, nil
 	}
 	return 0, fmt.Errorf("provided DiscordChannelId was not a guild channel: %d", channel.Type())
 
@@ -737,179 +1279,125 @@ func (d *DiscordClient) lookupRole(roleName string) (discord.Role, error) {
 	return discord.Role{}, fmt.Errorf("role %s not found", roleName)
 }
 
-func (d *DiscordClient) getDriverId(handle string) (snowflake.ID, error) {
-	if d.memberList == nil {
-		d.memberList = map[string]snowflake.ID{}
-
-		guildId, err := d.getGuild()
+// getDriverId resolves a driver to a Discord user, preferring an identity
+// link for their SimGrid player ID and falling back to matching their SimGrid
+// username against guild member names.
+func (d *DiscordClient) getDriverId(driver models.Driver) (snowflake.ID, error) {
+	if driver.SteamID != "" {
+		link, ok, err := d.lookupIdentity(driver.SteamID)
 		if err != nil {
 			return 0, err
 		}
-
-		var lastUser snowflake.ID
-		for {
-			members, err := d.rest.GetMembers(guildId, 1000, lastUser)
-			if err != nil {
-				return 0, err
-			}
-			if len(members) == 0 {
-				break
-			}
-			for _, member := range members {
-				normalizedUsername := strings.Replace(strings.ToLower(member.User.Username), ".", "", -1)
-				d.memberList[normalizedUsername] = member.User.ID
-			}
-			lastUser = members[len(members)-1].User.ID
+		if ok {
+			return link.DiscordID, nil
 		}
 	}
 
-	driver, ok := d.memberList[handle]
+	members, err := d.loadMembers()
+	if err != nil {
+		return 0, err
+	}
+
+	driverId, ok := members.lookup(driver.DiscordHandle)
 	if !ok {
-		return 0, DiscordHandleNotFoundError{Handle: handle}
+		return 0, DiscordHandleNotFoundError{Handle: driver.DiscordHandle}
 	}
-	return driver, nil
+	return driverId, nil
 }
 
-func (d *DiscordClient) generatePenaltyMessage(penalties *models.Penalties, config *config.RoundConfig) (string, error) {
-	message := `
-**Quali Bans R1**
-`
-	if len(penalties.QualiBansR1CarriedOver)+len(penalties.QualiBansR1) == 0 {
-		message += "- None!\n"
-	} else {
-		for _, driver := range penalties.QualiBansR1CarriedOver {
-			driverId, err := d.getDriverId(driver.DiscordHandle)
-			if err != nil {
-				if errors.Is(err, DiscordHandleNotFoundError{}) {
-					message += fmt.Sprintf("- #%d %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName)
-					continue
-				} else {
-					return "", err
-				}
-			}
-			message += fmt.Sprintf("- <@%s> (carried over)\n", driverId)
-		}
-		for _, driver := range penalties.QualiBansR1 {
-			driverId, err := d.getDriverId(driver.DiscordHandle)
-			if err != nil {
-				if errors.Is(err, DiscordHandleNotFoundError{}) {
-					message += fmt.Sprintf("- #%d %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName)
-					continue
-				} else {
-					return "", err
-				}
-			}
-			message += fmt.Sprintf("- <@%s>\n", driverId)
-		}
+// loadMembers returns the member cache for the league's guild, seeding it
+// from the guild's member list if it hasn't been already.
+func (d *DiscordClient) loadMembers() (*memberCache, error) {
+	guildId, err := d.getGuild()
+	if err != nil {
+		return nil, err
 	}
-
-	message += `
-**Pit Starts R1**
-`
-	if len(penalties.PitStartsR1CarriedOver)+len(penalties.PitStartsR1) == 0 {
-		message += "- None!\n"
-	} else {
-		for _, driver := range penalties.PitStartsR1CarriedOver {
-			driverId, err := d.getDriverId(driver.DiscordHandle)
-			if err != nil {
-				if errors.Is(err, DiscordHandleNotFoundError{}) {
-					message += fmt.Sprintf("- #%d %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName)
-					continue
-				} else {
-					return "", err
-				}
-			}
-			message += fmt.Sprintf("- <@%s> (carried over)\n", driverId)
-		}
-		for _, driver := range penalties.PitStartsR1 {
-			driverId, err := d.getDriverId(driver.DiscordHandle)
-			if err != nil {
-				if errors.Is(err, DiscordHandleNotFoundError{}) {
-					message += fmt.Sprintf("- #%d %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName)
-					continue
-				} else {
-					return "", err
-				}
-			}
-			message += fmt.Sprintf("- <@%s>\n", driverId)
-		}
+	members := d.members.forGuild(guildId)
+	err = members.load(guildId, func(after snowflake.ID) ([]discord.Member, error) {
+		return d.rest.GetMembers(guildId, 1000, after)
+	})
+	if err != nil {
+		return nil, err
 	}
-	message += `
-**Quali Bans R2**
-`
-	if len(penalties.QualiBansR2CarriedOver)+len(penalties.QualiBansR2) == 0 {
-		message += "- None!\n"
-	} else {
-		for _, driver := range penalties.QualiBansR2CarriedOver {
-			driverId, err := d.getDriverId(driver.DiscordHandle)
-			if err != nil {
-				if errors.Is(err, DiscordHandleNotFoundError{}) {
-					message += fmt.Sprintf("- #%d %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName)
-					continue
-				} else {
-					return "", err
-				}
-			}
-			message += fmt.Sprintf("- <@%s> (carried over)\n", driverId)
-		}
-		for _, driver := range penalties.QualiBansR2 {
-			driverId, err := d.getDriverId(driver.DiscordHandle)
-			if err != nil {
-				if errors.Is(err, DiscordHandleNotFoundError{}) {
-					message += fmt.Sprintf("- #%d %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName)
-					continue
-				} else {
-					return "", err
-				}
-			}
-			message += fmt.Sprintf("- <@%s>\n", driverId)
-		}
+	return members, nil
+}
+
+// penaltySection is one penalty category rendered for Discord: one line per
+// driver serving it, plus the IDs of drivers who could be mentioned.
+type penaltySection struct {
+	Title    string
+	Lines    []string
+	Mentions []snowflake.ID
+}
+
+// penaltyCategory is one kind of penalty, split into carried-over and newly
+// issued drivers.
+type penaltyCategory struct {
+	title       string
+	carriedOver []models.Driver
+	current     []models.Driver
+}
+
+// penaltyCategories lists the penalty categories in announcement order.
+func penaltyCategories(penalties *models.Penalties) []penaltyCategory {
+	return []penaltyCategory{
+		{"Quali Bans R1", penalties.QualiBansR1CarriedOver, penalties.QualiBansR1},
+		{"Pit Starts R1", penalties.PitStartsR1CarriedOver, penalties.PitStartsR1},
+		{"Quali Bans R2", penalties.QualiBansR2CarriedOver, penalties.QualiBansR2},
+		{"Pit Starts R2", penalties.PitStartsR2CarriedOver, penalties.PitStartsR2},
 	}
+}
 
-	message += `
-**Pit Starts R2**
-`
-	if len(penalties.PitStartsR2CarriedOver)+len(penalties.PitStartsR2) == 0 {
-		message += "- None!\n"
-	} else {
-		for _, driver := range penalties.PitStartsR2CarriedOver {
-			driverId, err := d.getDriverId(driver.DiscordHandle)
+// buildPenaltySections resolves every penalized driver to a Discord mention,
+// falling back to car number and name when the driver is not in the guild.
+func (d *DiscordClient) buildPenaltySections(penalties *models.Penalties) ([]penaltySection, error) {
+	categories := penaltyCategories(penalties)
+
+	sections := make([]penaltySection, 0, len(categories))
+	for _, category := range categories {
+		section := penaltySection{Title: category.title}
+		for i, driver := range append(append([]models.Driver{}, category.carriedOver...), category.current...) {
+			driverId, err := d.getDriverId(driver)
 			if err != nil {
 				if errors.Is(err, DiscordHandleNotFoundError{}) {
-					message += fmt.Sprintf("- #%d %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName)
+					section.Lines = append(section.Lines, fmt.Sprintf("#%d %s %s", driver.CarNumber, driver.FirstName, driver.LastName))
 					continue
-				} else {
-					return "", err
 				}
+				return nil, err
 			}
-			message += fmt.Sprintf("- <@%s> (carried over)\n", driverId)
-		}
-		for _, driver := range penalties.PitStartsR2 {
-			driverId, err := d.getDriverId(driver.DiscordHandle)
-			if err != nil {
-				if errors.Is(err, DiscordHandleNotFoundError{}) {
-					message += fmt.Sprintf("- #%d %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName)
-					continue
-				} else {
-					return "", err
-				}
+			line := fmt.Sprintf("<@%s>", driverId)
+			if i < len(category.carriedOver) {
+				line += " (carried over)"
 			}
-			message += fmt.Sprintf("- <@%s>\n", driverId)
+			section.Lines = append(section.Lines, line)
+			section.Mentions = append(section.Mentions, driverId)
 		}
+		sections = append(sections, section)
 	}
-	message += fmt.Sprintf(`
-[Explanations of penalties can be found here.](%s)
-`, config.PreviousRound.PenaltyTrackerLink)
-
-	return message, nil
+	return sections, nil
 }
 
 // NewTestDiscordClient creates a DiscordClient with injected dependencies for testing.
 func NewTestDiscordClient(rest BotRestClient, applicationID snowflake.ID, conf *config.Config, gc *gcloud.Client) *DiscordClient {
+	stateDir := ""
+	if conf != nil {
+		stateDir = conf.StateDir
+	}
+	store := state.NewStore(stateDir)
+	ctx, cancel := context.WithCancel(context.Background())
 	return &DiscordClient{
 		rest:          rest,
 		applicationID: applicationID,
 		conf:          conf,
 		gcloud:        gc,
+		connectGCloud: func(context.Context) (*gcloud.Client, error) { return gc, nil },
+		messages:      defaultMessages(),
+		members:       newGuildMembers(),
+		state:         store,
+		drivers:       store,
+		ctx:           ctx,
+		cancel:        cancel,
+		commands:      &commandTracker{},
+		running:       newLeagueLock(),
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	dgo "github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
@@ -82,29 +83,91 @@ var _ = Describe("isAllowedUser", func() {
 	})
 })
 
-var _ = Describe("helpMessage", func() {
+var _ = Describe("help template", func() {
+	var (
+		client *DiscordClient
+		help   string
+	)
+
+	BeforeEach(func() {
+		client = NewTestDiscordClient(&stubRest{}, snowflakeID(1), &config.Config{}, nil)
+		overview, err := client.runHelp(nil)
+		Expect(err).NotTo(HaveOccurred())
+		help = overview
+		for _, topic := range helpTopics {
+			msg, err := client.runHelp([]string{topic})
+			Expect(err).NotTo(HaveOccurred())
+			help += msg
+		}
+	})
+
+	It("fits the overview and every topic in one Discord message", func() {
+		for _, topic := range append([]string{""}, helpTopics...) {
+			msg, err := defaultMessages().render(templateHelp, HelpData{Topic: topic})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(utf8.RuneCountInString(msg)).To(BeNumerically("<=", maxMessageLength), "!help %s", topic)
+		}
+	})
+
+	It("lists the topics in the overview and rejects unknown ones", func() {
+		overview, err := client.runHelp([]string{"--league", "rookies"})
+		Expect(err).NotTo(HaveOccurred())
+		for _, topic := range helpTopics {
+			Expect(overview).To(ContainSubstring("`%s`", topic))
+		}
+
+		_, err = client.runHelp([]string{"penalties"})
+		Expect(err).To(MatchError(ContainSubstring("unknown help topic \"penalties\"")))
+	})
+
 	It("lists the !help command", func() {
-		Expect(helpMessage()).To(ContainSubstring("!help"))
+		Expect(help).To(ContainSubstring("!help"))
 	})
 
 	It("lists the !announce-penalties command", func() {
-		Expect(helpMessage()).To(ContainSubstring("!announce-penalties"))
+		Expect(help).To(ContainSubstring("!announce-penalties"))
 	})
 
 	It("lists the !race-setup command", func() {
-		Expect(helpMessage()).To(ContainSubstring("!race-setup"))
+		Expect(help).To(ContainSubstring("!race-setup"))
 	})
 
 	It("notes that the penalty commands require a YAML attachment", func() {
-		Expect(helpMessage()).To(ContainSubstring("YAML"))
+		Expect(help).To(ContainSubstring("YAML"))
 	})
 
 	It("lists the !new-season command", func() {
-		Expect(helpMessage()).To(ContainSubstring("!new-season"))
+		Expect(help).To(ContainSubstring("!new-season"))
 	})
 
 	It("lists the !new-season-apply command", func() {
-		Expect(helpMessage()).To(ContainSubstring("!new-season-apply"))
+		Expect(help).To(ContainSubstring("!new-season-apply"))
+	})
+})
+
+var _ = Describe("splitMessage", func() {
+	It("keeps a short message whole", func() {
+		Expect(splitMessage("hello\nworld")).To(Equal([]string{"hello\nworld"}))
+	})
+
+	It("splits a long message between lines without losing any", func() {
+		line := strings.Repeat("x", 99) + "\n"
+		msg := strings.Repeat(line, 50)
+		parts := splitMessage(msg)
+		Expect(parts).To(HaveLen(3))
+		for _, part := range parts {
+			Expect(utf8.RuneCountInString(part)).To(BeNumerically("<=", maxMessageLength))
+			Expect(part).To(HaveSuffix("\n"))
+		}
+		Expect(strings.Join(parts, "")).To(Equal(msg))
+	})
+
+	It("cuts a single line longer than the limit", func() {
+		msg := strings.Repeat("é", maxMessageLength+10)
+		parts := splitMessage(msg)
+		Expect(parts).To(HaveLen(2))
+		Expect(utf8.RuneCountInString(parts[0])).To(Equal(maxMessageLength))
+		Expect(strings.Join(parts, "")).To(Equal(msg))
 	})
 })
 
@@ -316,6 +379,18 @@ type stubRest struct {
 	getRolesFn                  func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error)
 	getMembersFn                func(guildID snowflake.ID, limit int, after snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Member, error)
 	createGuildScheduledEventFn func(guildID snowflake.ID, e dgo.GuildScheduledEventCreate, opts ...rest.RequestOpt) (*dgo.GuildScheduledEvent, error)
+	createDMChannelFn           func(userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.DMChannel, error)
+	createThreadFromMessageFn   func(channelID snowflake.ID, messageID snowflake.ID, t dgo.ThreadCreateFromMessage, opts ...rest.RequestOpt) (*dgo.GuildThread, error)
+	createThreadFn              func(channelID snowflake.ID, t dgo.ThreadCreate, opts ...rest.RequestOpt) (*dgo.GuildThread, error)
+	addThreadMemberFn           func(threadID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) error
+	updateMessageFn             func(channelID snowflake.ID, messageID snowflake.ID, u dgo.MessageUpdate, opts ...rest.RequestOpt) (*dgo.Message, error)
+	updateChannelFn             func(channelID snowflake.ID, u dgo.ChannelUpdate, opts ...rest.RequestOpt) (dgo.Channel, error)
+	deleteMessageFn             func(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error
+	deleteChannelFn             func(channelID snowflake.ID, opts ...rest.RequestOpt) error
+	deleteGuildScheduledEventFn func(guildID snowflake.ID, eventID snowflake.ID, opts ...rest.RequestOpt) error
+	getMemberFn                 func(guildID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.Member, error)
+	addMemberRoleFn             func(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) error
+	removeMemberRoleFn          func(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) error
 }
 
 func (s *stubRest) CreateMessage(channelID snowflake.ID, messageCreate dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
@@ -367,6 +442,149 @@ func (s *stubRest) CreateGuildScheduledEvent(guildID snowflake.ID, e dgo.GuildSc
 	}
 	return &dgo.GuildScheduledEvent{}, nil
 }
+func (s *stubRest) CreateDMChannel(userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.DMChannel, error) {
+	if s.createDMChannelFn != nil {
+		return s.createDMChannelFn(userID, opts...)
+	}
+	return &dgo.DMChannel{}, nil
+}
+func (s *stubRest) CreateThreadFromMessage(channelID snowflake.ID, messageID snowflake.ID, t dgo.ThreadCreateFromMessage, opts ...rest.RequestOpt) (*dgo.GuildThread, error) {
+	if s.createThreadFromMessageFn != nil {
+		return s.createThreadFromMessageFn(channelID, messageID, t, opts...)
+	}
+	return &dgo.GuildThread{}, nil
+}
+func (s *stubRest) CreateThread(channelID snowflake.ID, t dgo.ThreadCreate, opts ...rest.RequestOpt) (*dgo.GuildThread, error) {
+	if s.createThreadFn != nil {
+		return s.createThreadFn(channelID, t, opts...)
+	}
+	return &dgo.GuildThread{}, nil
+}
+func (s *stubRest) AddThreadMember(threadID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) error {
+	if s.addThreadMemberFn != nil {
+		return s.addThreadMemberFn(threadID, userID, opts...)
+	}
+	return nil
+}
+func (s *stubRest) UpdateMessage(channelID snowflake.ID, messageID snowflake.ID, u dgo.MessageUpdate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+	if s.updateMessageFn != nil {
+		return s.updateMessageFn(channelID, messageID, u, opts...)
+	}
+	return &dgo.Message{ID: messageID}, nil
+}
+func (s *stubRest) UpdateChannel(channelID snowflake.ID, u dgo.ChannelUpdate, opts ...rest.RequestOpt) (dgo.Channel, error) {
+	if s.updateChannelFn != nil {
+		return s.updateChannelFn(channelID, u, opts...)
+	}
+	return dgo.GuildThread{}, nil
+}
+func (s *stubRest) DeleteMessage(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error {
+	if s.deleteMessageFn != nil {
+		return s.deleteMessageFn(channelID, messageID, opts...)
+	}
+	return nil
+}
+func (s *stubRest) DeleteChannel(channelID snowflake.ID, opts ...rest.RequestOpt) error {
+	if s.deleteChannelFn != nil {
+		return s.deleteChannelFn(channelID, opts...)
+	}
+	return nil
+}
+func (s *stubRest) DeleteGuildScheduledEvent(guildID snowflake.ID, eventID snowflake.ID, opts ...rest.RequestOpt) error {
+	if s.deleteGuildScheduledEventFn != nil {
+		return s.deleteGuildScheduledEventFn(guildID, eventID, opts...)
+	}
+	return nil
+}
+func (s *stubRest) GetMember(guildID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.Member, error) {
+	if s.getMemberFn != nil {
+		return s.getMemberFn(guildID, userID, opts...)
+	}
+	return &dgo.Member{}, nil
+}
+func (s *stubRest) AddMemberRole(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) error {
+	if s.addMemberRoleFn != nil {
+		return s.addMemberRoleFn(guildID, userID, roleID, opts...)
+	}
+	return nil
+}
+func (s *stubRest) RemoveMemberRole(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) error {
+	if s.removeMemberRoleFn != nil {
+		return s.removeMemberRoleFn(guildID, userID, roleID, opts...)
+	}
+	return nil
+}
+
+// testLeague is the fixture shared by tests that drive a whole league: a
+// state dir, a stubRest serving the guild's members, a client for the
+// "2026 Fall" season, and a SimGrid server for the championship.
+type testLeague struct {
+	stateDir string
+	stub     *stubRest
+	client   *DiscordClient
+	sgServer *httptest.Server
+	sgClient *simgrid.SimGridClient
+
+	// members are the guild's members: Max (42) and Lando (43) by default.
+	members []dgo.Member
+	// entries, users and championship are what SimGrid serves: Max as #1
+	// and Lando as #4 at Monza, Imola and Spa by default.
+	entries      string
+	users        string
+	championship string
+}
+
+// newTestLeague sets up a testLeague named name, filling in conf's season
+// and state dir. Tests tweak its fields and stubs before using them.
+func newTestLeague(name string, conf config.BotConfig, gcClient *gcloud.Client) *testLeague {
+	stateDir, err := os.MkdirTemp("", "rookies-bot-"+name)
+	Expect(err).NotTo(HaveOccurred())
+
+	league := &testLeague{
+		stateDir: stateDir,
+		members: []dgo.Member{
+			{User: dgo.User{ID: snowflakeID(42), Username: "max.verstappen"}},
+			{User: dgo.User{ID: snowflakeID(43), Username: "lando.norris"}},
+		},
+		entries: `{"entries":[
+			{"drivers":[{"firstName":"Max","lastName":"Verstappen","playerId":"S111"}],"raceNumber":1},
+			{"drivers":[{"firstName":"Lando","lastName":"Norris","playerId":"S222"}],"raceNumber":4}]}`,
+		users: `[
+			{"steam64_id":"111","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"},
+			{"steam64_id":"222","username":"lando.norris","first_name":"Lando","last_name":"Norris"}]`,
+		championship: `{"name":"Rookies Fall","races":[{"track":{"name":"Monza"}},{"track":{"name":"Imola"}},{"track":{"name":"Spa"}}]}`,
+	}
+	league.stub = &stubRest{
+		getMembersFn: func(guildID snowflake.ID, limit int, after snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Member, error) {
+			return pagedMembers(league.members...)(after)
+		},
+	}
+
+	conf.Season = "2026 Fall"
+	conf.StateDir = stateDir
+	league.client = NewTestDiscordClient(league.stub, snowflakeID(1), &config.Config{BotConfig: conf}, gcClient)
+
+	league.sgServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "entrylist"):
+			_, _ = w.Write([]byte(league.entries))
+		case strings.Contains(r.URL.Path, "participating_users"):
+			_, _ = w.Write([]byte(league.users))
+		default:
+			_, _ = w.Write([]byte(league.championship))
+		}
+	}))
+	league.sgClient = simgrid.NewClient("test-token")
+	league.sgClient.BaseURL = league.sgServer.URL
+	return league
+}
+
+// close stops the SimGrid server and removes the state dir.
+func (l *testLeague) close() {
+	l.sgServer.Close()
+	_ = os.RemoveAll(l.stateDir)
+}
 
 var _ = Describe("runAnnouncePenalties", func() {
 	var (
@@ -470,15 +688,16 @@ var _ = Describe("runAnnouncePenalties", func() {
 	})
 })
 
-// makeStreamDoc creates a minimal *docs.Document with a Stream H3 heading at body index 1.
-// This is needed by generateUpdates inside GenerateBriefing.
-func makeStreamDoc() *docs.Document {
+// makeBriefingDoc creates a minimal briefing template *docs.Document with the
+// penalties marker at body index 7, as GenerateBriefing needs.
+func makeBriefingDoc() *docs.Document {
 	return &docs.Document{
 		DocumentId: "test-doc",
 		Body: &docs.Body{
 			Content: []*docs.StructuralElement{
 				{
-					StartIndex: 0,
+					StartIndex: 1,
+					EndIndex:   7,
 					Paragraph: &docs.Paragraph{
 						Elements: []*docs.ParagraphElement{
 							{
@@ -490,7 +709,21 @@ func makeStreamDoc() *docs.Document {
 					},
 				},
 				{
-					StartIndex: 1,
+					StartIndex: 7,
+					EndIndex:   19,
+					Paragraph: &docs.Paragraph{
+						Elements: []*docs.ParagraphElement{
+							{
+								TextRun: &docs.TextRun{
+									Content: "[penalties]\n",
+								},
+							},
+						},
+					},
+				},
+				{
+					StartIndex: 19,
+					EndIndex:   26,
 					Paragraph: &docs.Paragraph{
 						ParagraphStyle: &docs.ParagraphStyle{
 							NamedStyleType: "HEADING_3",
@@ -498,7 +731,7 @@ func makeStreamDoc() *docs.Document {
 						Elements: []*docs.ParagraphElement{
 							{
 								TextRun: &docs.TextRun{
-									Content: "Stream",
+									Content: "Stream\n",
 								},
 							},
 						},
@@ -532,7 +765,7 @@ var _ = Describe("runRaceSetup", func() {
 		// Default fake drive returns a file with an ID
 		fakeDrive.CopyFileReturns(&drive.File{Id: "test-doc-id"}, nil)
 		// Default fake docs returns a document with Stream heading
-		fakeDocs.GetDocumentReturns(makeStreamDoc(), nil)
+		fakeDocs.GetDocumentReturns(makeBriefingDoc(), nil)
 
 		client = NewTestDiscordClient(stub, snowflakeID(1), &config.Config{
 			BotConfig: config.BotConfig{
@@ -614,7 +847,7 @@ var _ = Describe("runRaceSetup", func() {
 		stub.getRolesFn = func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
 			return nil, fmt.Errorf("roles error")
 		}
-		fakeDocs.GetDocumentReturns(makeStreamDoc(), nil)
+		fakeDocs.GetDocumentReturns(makeBriefingDoc(), nil)
 		_, _, err := client.runRaceSetup(roundConfig, sgClient, gcClient)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed to generate briefingmessage"))
@@ -624,7 +857,7 @@ var _ = Describe("runRaceSetup", func() {
 		stub.createMessageFn = func(channelID snowflake.ID, messageCreate dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
 			return nil, fmt.Errorf("send failed")
 		}
-		fakeDocs.GetDocumentReturns(makeStreamDoc(), nil)
+		fakeDocs.GetDocumentReturns(makeBriefingDoc(), nil)
 		_, _, err := client.runRaceSetup(roundConfig, sgClient, gcClient)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed to send briefing announcement"))
@@ -634,7 +867,7 @@ var _ = Describe("runRaceSetup", func() {
 		stub.pinMessageFn = func(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error {
 			return fmt.Errorf("pin failed")
 		}
-		fakeDocs.GetDocumentReturns(makeStreamDoc(), nil)
+		fakeDocs.GetDocumentReturns(makeBriefingDoc(), nil)
 		_, _, err := client.runRaceSetup(roundConfig, sgClient, gcClient)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed to pin briefing announcement"))
@@ -644,14 +877,14 @@ var _ = Describe("runRaceSetup", func() {
 		stub.createGuildScheduledEventFn = func(guildID snowflake.ID, e dgo.GuildScheduledEventCreate, opts ...rest.RequestOpt) (*dgo.GuildScheduledEvent, error) {
 			return nil, fmt.Errorf("event creation failed")
 		}
-		fakeDocs.GetDocumentReturns(makeStreamDoc(), nil)
+		fakeDocs.GetDocumentReturns(makeBriefingDoc(), nil)
 		_, _, err := client.runRaceSetup(roundConfig, sgClient, gcClient)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed to create briefing event"))
 	})
 
 	It("happy path with NextRound.Track == '' (no file written, msg contains round name, empty attachment)", func() {
-		fakeDocs.GetDocumentReturns(makeStreamDoc(), nil)
+		fakeDocs.GetDocumentReturns(makeBriefingDoc(), nil)
 		msg, attachment, err := client.runRaceSetup(roundConfig, sgClient, gcClient)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(msg).To(ContainSubstring("Round"))
@@ -661,7 +894,7 @@ var _ = Describe("runRaceSetup", func() {
 	It("happy path with NextRound.Track != '' (attachment non-empty, msg contains penalty tracker link)", func() {
 		roundConfig.NextRound.Track = "Silverstone"
 		roundConfig.NextRound.Number = 3
-		fakeDocs.GetDocumentReturns(makeStreamDoc(), nil)
+		fakeDocs.GetDocumentReturns(makeBriefingDoc(), nil)
 
 		sgServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -30,6 +30,9 @@ func newGuildTextChannel(id, guildID snowflake.ID) dgo.GuildTextChannel {
 	return ch
 }
 
+// briefingTime is Monday 9 March 2026, 7:30PM Eastern.
+var briefingTime = time.Date(2026, time.March, 9, 23, 30, 0, 0, time.UTC)
+
 func newTestClient(restClient discord.BotRestClient, conf *config.Config) *discord.DiscordClient {
 	return discord.NewTestDiscordClient(restClient, snowflake.ID(12345), conf, nil)
 }
@@ -552,37 +555,33 @@ var _ = Describe("CreateBriefingEvent", func() {
 		fakeRest.CreateGuildScheduledEventReturns(&dgo.GuildScheduledEvent{}, nil)
 	})
 
-	It("schedules the event at 7:30 PM Eastern on the next Monday", func() {
-		err := dc.CreateBriefingEvent(&conf.RoundConfig)
+	It("schedules the event at the briefing time", func() {
+		err := dc.CreateBriefingEvent(&conf.RoundConfig, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(fakeRest.CreateGuildScheduledEventCallCount()).To(Equal(1))
 		_, eventCreate, _ := fakeRest.CreateGuildScheduledEventArgsForCall(0)
-		loc, _ := time.LoadLocation("America/New_York")
-		scheduledTime := eventCreate.ScheduledStartTime.In(loc)
-		Expect(scheduledTime.Weekday()).To(Equal(time.Monday))
-		Expect(scheduledTime.Hour()).To(Equal(19))
-		Expect(scheduledTime.Minute()).To(Equal(30))
+		Expect(eventCreate.ScheduledStartTime).To(BeTemporally("==", briefingTime))
 	})
 
 	It("returns error when GetChannel fails", func() {
 		fakeRest.GetChannelReturns(nil, &errorMsg{msg: "channel not found"})
-		err := dc.CreateBriefingEvent(&conf.RoundConfig)
+		err := dc.CreateBriefingEvent(&conf.RoundConfig, briefingTime)
 		Expect(err).To(MatchError("channel not found"))
 	})
 
 	It("returns error when CreateGuildScheduledEvent fails", func() {
 		fakeRest.CreateGuildScheduledEventReturns(nil, &errorMsg{msg: "event creation failed"})
-		err := dc.CreateBriefingEvent(&conf.RoundConfig)
+		err := dc.CreateBriefingEvent(&conf.RoundConfig, briefingTime)
 		Expect(err).To(MatchError("event creation failed"))
 	})
 
 	It("does not call GetChannel a second time when guild is already cached", func() {
 		fakeRest.CreateGuildScheduledEventReturns(&dgo.GuildScheduledEvent{}, nil)
 
-		err := dc.CreateBriefingEvent(&conf.RoundConfig)
+		err := dc.CreateBriefingEvent(&conf.RoundConfig, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
-		err = dc.CreateBriefingEvent(&conf.RoundConfig)
+		err = dc.CreateBriefingEvent(&conf.RoundConfig, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(fakeRest.GetChannelCallCount()).To(Equal(1))
@@ -591,7 +590,7 @@ var _ = Describe("CreateBriefingEvent", func() {
 	It("includes the round number and track in the event name", func() {
 		fakeRest.CreateGuildScheduledEventReturns(&dgo.GuildScheduledEvent{}, nil)
 
-		err := dc.CreateBriefingEvent(&conf.RoundConfig)
+		err := dc.CreateBriefingEvent(&conf.RoundConfig, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(fakeRest.CreateGuildScheduledEventCallCount()).To(Equal(1))
@@ -606,7 +605,7 @@ var _ = Describe("CreateBriefingEvent", func() {
 			Fail("failed to create DM channel: " + err.Error())
 		}
 		fakeRest.GetChannelReturns(dmChannel, nil)
-		err := dc.CreateBriefingEvent(&conf.RoundConfig)
+		err := dc.CreateBriefingEvent(&conf.RoundConfig, briefingTime)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("not a guild channel"))
 	})
@@ -682,43 +681,43 @@ var _ = Describe("BuildBriefingMessage", func() {
 
 	It("includes a role mention in the message", func() {
 		penalties := &models.Penalties{}
-		msg, err := dc.BuildBriefingMessage(penalties, "https://docs.google.com/briefing", &conf.RoundConfig)
+		msg, err := dc.BuildBriefingMessage(penalties, "https://docs.google.com/briefing", &conf.RoundConfig, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(msg.Content).To(ContainSubstring("<@&500>"))
 	})
 
 	It("includes the briefing doc URL", func() {
 		penalties := &models.Penalties{}
-		msg, err := dc.BuildBriefingMessage(penalties, "https://docs.google.com/briefing", &conf.RoundConfig)
+		msg, err := dc.BuildBriefingMessage(penalties, "https://docs.google.com/briefing", &conf.RoundConfig, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(msg.Content).To(ContainSubstring("https://docs.google.com/briefing"))
 	})
 
 	It("includes the next round number", func() {
 		penalties := &models.Penalties{}
-		msg, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig)
+		msg, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(msg.Content).To(ContainSubstring("Round 5"))
 	})
 
 	It("includes the penalty tracker link", func() {
 		penalties := &models.Penalties{}
-		msg, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig)
+		msg, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(msg.Content).To(ContainSubstring("https://example.com/tracker"))
 	})
 
 	It("includes a briefing timestamp marker", func() {
 		penalties := &models.Penalties{}
-		msg, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig)
+		msg, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(msg.Content).To(ContainSubstring("<t:"))
+		Expect(msg.Content).To(ContainSubstring(fmt.Sprintf("<t:%d>", briefingTime.Unix())))
 	})
 
 	It("returns error when the role is not found", func() {
 		fakeRest.GetRolesReturns([]dgo.Role{{Name: "OtherRole", ID: snowflake.ID(501)}}, nil)
 		penalties := &models.Penalties{}
-		_, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig)
+		_, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig, briefingTime)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("Rookies"))
 	})
@@ -726,18 +725,136 @@ var _ = Describe("BuildBriefingMessage", func() {
 	It("returns error when GetRoles fails", func() {
 		fakeRest.GetRolesReturns(nil, &errorMsg{msg: "roles API error"})
 		penalties := &models.Penalties{}
-		_, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig)
+		_, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig, briefingTime)
 		Expect(err).To(MatchError("roles API error"))
 	})
 
 	It("returns error when GetChannel fails", func() {
 		fakeRest.GetChannelReturns(nil, &errorMsg{msg: "channel error"})
 		penalties := &models.Penalties{}
-		_, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig)
+		_, err := dc.BuildBriefingMessage(penalties, "https://example.com/doc", &conf.RoundConfig, briefingTime)
 		Expect(err).To(MatchError("channel error"))
 	})
 })
 
+var _ = Describe("embed message format", func() {
+	var (
+		fakeRest *fakes.FakeBotRestClient
+		conf     *config.Config
+		dc       *discord.DiscordClient
+	)
+
+	BeforeEach(func() {
+		fakeRest = new(fakes.FakeBotRestClient)
+		conf = &config.Config{
+			BotConfig: config.BotConfig{
+				DiscordChannelId:         snowflake.ID(111),
+				DiscordBriefingChannelId: snowflake.ID(222),
+				DiscordRoleName:          "Rookies",
+				MessageFormat:            config.MessageFormatEmbed,
+			},
+			RoundConfig: config.RoundConfig{
+				NextRound:     config.Round{Number: 5, Track: "Monza"},
+				PreviousRound: config.Round{Number: 4, Track: "Spa", PenaltyTrackerLink: "https://example.com/tracker"},
+			},
+		}
+		dc = newTestClient(fakeRest, conf)
+
+		fakeRest.GetChannelStub = func(channelID snowflake.ID, opts ...rest.RequestOpt) (dgo.Channel, error) {
+			return newGuildTextChannel(channelID, snowflake.ID(777)), nil
+		}
+		fakeRest.GetRolesReturns([]dgo.Role{{Name: "Rookies", ID: snowflake.ID(500)}}, nil)
+		fakeRest.GetMembersReturnsOnCall(0, []dgo.Member{
+			{User: dgo.User{ID: snowflake.ID(1001), Username: "maxv"}},
+		}, nil)
+		fakeRest.GetMembersReturns([]dgo.Member{}, nil)
+	})
+
+	Describe("BuildPenaltyMessage", func() {
+		It("renders one embed field per penalty category with the round in the title", func() {
+			penalties := &models.Penalties{
+				PitStartsR1: []models.Driver{{FirstName: "Max", LastName: "V", CarNumber: 42, DiscordHandle: "maxv"}},
+			}
+			msg, err := dc.BuildPenaltyMessage(penalties, &conf.RoundConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Embeds).To(HaveLen(1))
+			embed := msg.Embeds[0]
+			Expect(embed.Title).To(ContainSubstring("Round 4 - Spa"))
+			Expect(embed.Description).To(ContainSubstring("Monza"))
+			Expect(embed.Fields).To(HaveLen(4))
+			Expect(embed.Fields[0].Name).To(Equal("Quali Bans R1"))
+			Expect(embed.Fields[0].Value).To(Equal("None!"))
+			Expect(embed.Fields[1].Name).To(Equal("Pit Starts R1"))
+			Expect(embed.Fields[1].Value).To(ContainSubstring("<@1001>"))
+		})
+
+		It("mentions penalized drivers in the content so they are notified", func() {
+			penalties := &models.Penalties{
+				QualiBansR1: []models.Driver{{FirstName: "Max", LastName: "V", CarNumber: 42, DiscordHandle: "maxv"}},
+				PitStartsR2: []models.Driver{{FirstName: "Max", LastName: "V", CarNumber: 42, DiscordHandle: "maxv"}},
+			}
+			msg, err := dc.BuildPenaltyMessage(penalties, &conf.RoundConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Content).To(Equal("<@1001>"))
+		})
+
+		It("links the penalty tracker as a button", func() {
+			msg, err := dc.BuildPenaltyMessage(&models.Penalties{}, &conf.RoundConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Components).To(HaveLen(1))
+			row := msg.Components[0].(dgo.ActionRowComponent)
+			Expect(row.Components).To(HaveLen(1))
+			Expect(row.Components[0].(dgo.ButtonComponent).URL).To(Equal("https://example.com/tracker"))
+		})
+
+		It("does not suppress embeds", func() {
+			msg, err := dc.BuildPenaltyMessage(&models.Penalties{}, &conf.RoundConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Flags.Has(dgo.MessageFlagSuppressEmbeds)).To(BeFalse())
+		})
+	})
+
+	Describe("BuildBriefingMessage", func() {
+		It("renders the race-day embed with round, track and briefing timestamp", func() {
+			msg, err := dc.BuildBriefingMessage(&models.Penalties{}, "https://docs.google.com/briefing", &conf.RoundConfig, briefingTime)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Content).To(Equal("<@&500>"))
+			Expect(msg.Embeds).To(HaveLen(1))
+			embed := msg.Embeds[0]
+			Expect(embed.Title).To(ContainSubstring("Round 5 - Monza"))
+			Expect(embed.Fields[0].Name).To(Equal("Drivers' Briefing"))
+			Expect(embed.Fields[0].Value).To(MatchRegexp(`<t:\d+:F>`))
+			Expect(embed.Fields).To(HaveLen(5))
+		})
+
+		It("mentions penalized drivers after the role so they are notified", func() {
+			penalties := &models.Penalties{
+				QualiBansR1: []models.Driver{{FirstName: "Max", LastName: "V", CarNumber: 42, DiscordHandle: "maxv"}},
+			}
+			msg, err := dc.BuildBriefingMessage(penalties, "https://docs.google.com/briefing", &conf.RoundConfig, briefingTime)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Content).To(Equal("<@&500> <@1001>"))
+		})
+
+		It("links the briefing doc and tracker as buttons", func() {
+			msg, err := dc.BuildBriefingMessage(&models.Penalties{}, "https://docs.google.com/briefing", &conf.RoundConfig, briefingTime)
+			Expect(err).NotTo(HaveOccurred())
+			row := msg.Components[0].(dgo.ActionRowComponent)
+			Expect(row.Components).To(HaveLen(2))
+			Expect(row.Components[0].(dgo.ButtonComponent).URL).To(Equal("https://docs.google.com/briefing"))
+			Expect(row.Components[1].(dgo.ButtonComponent).URL).To(Equal("https://example.com/tracker"))
+		})
+
+		It("omits the tracker button when no tracker link is set", func() {
+			conf.PreviousRound.PenaltyTrackerLink = ""
+			msg, err := dc.BuildBriefingMessage(&models.Penalties{}, "https://docs.google.com/briefing", &conf.RoundConfig, briefingTime)
+			Expect(err).NotTo(HaveOccurred())
+			row := msg.Components[0].(dgo.ActionRowComponent)
+			Expect(row.Components).To(HaveLen(1))
+		})
+	})
+})
+
 type errorMsg struct {
 	msg string
 }
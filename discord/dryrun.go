@@ -0,0 +1,217 @@
+package discord
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	"github.com/geofffranks/rookies-bot/simgrid"
+)
+
+// dryRunFlag makes `!announce-penalties` and `!race-setup` preview what they
+// would post instead of acting.
+const dryRunFlag = "--dry-run"
+
+// dryRunBriefingURL stands in for the briefing doc a dry run doesn't copy.
+const dryRunBriefingURL = "https://docs.google.com/document/d/dry-run"
+
+// hasDryRunFlag reports whether args ask for a dry run.
+func hasDryRunFlag(args []string) bool {
+	return slices.Contains(args, dryRunFlag)
+}
+
+// dryRun is what a command would have done. It is sent only to the admin who
+// asked for it.
+type dryRun struct {
+	// summary says what was previewed and what was left alone.
+	summary string
+	// message is the announcement exactly as it would be posted.
+	message discord.MessageCreate
+	// details follow the announcement, e.g. the DQ list.
+	details string
+	// briefing is the briefing doc text, sent with the details as a text
+	// file since it easily runs past Discord's 2000 character limit.
+	briefing     string
+	briefingName string
+	// attachment is a temp file sent with the details, then removed.
+	attachment string
+}
+
+func (d *DiscordClient) announcePenaltiesDryRun(event *events.MessageCreate) {
+	roundConfig, err := getRoundConfig(event)
+	if err != nil {
+		sendBotResponse(event, fmt.Sprintf("Failed getting race config: %s", err), "")
+		return
+	}
+	sgClient := d.newSimGridClient()
+	preview, err := d.runAnnouncePenaltiesDryRun(roundConfig, sgClient)
+	d.respondDryRun(event, preview, err)
+}
+
+func (d *DiscordClient) raceSetupDryRun(event *events.MessageCreate) {
+	roundConfig, err := getRoundConfig(event)
+	if err != nil {
+		sendBotResponse(event, err.Error(), "")
+		return
+	}
+	sgClient := d.newSimGridClient()
+	preview, err := d.runRaceSetupDryRun(roundConfig, sgClient)
+	d.respondDryRun(event, preview, err)
+}
+
+// runAnnouncePenaltiesDryRun resolves the drivers and renders the penalty
+// announcement without posting, pinning or DMing anything.
+func (d *DiscordClient) runAnnouncePenaltiesDryRun(roundConfig *config.RoundConfig, sgClient *simgrid.SimGridClient) (*dryRun, error) {
+	conf := d.snapshotConfig()
+	if err := d.mergeAbsencePenalties(conf.Season, roundConfig); err != nil {
+		return nil, err
+	}
+	driverLookup, err := sgClient.BuildDriverLookup(conf.ChampionshipId)
+	if err != nil {
+		return nil, fmt.Errorf("failed building driver list: %w", err)
+	}
+
+	penaltyList, err := buildPenaltyList(driverLookup, roundConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating penalty summary: %w", err)
+	}
+
+	msg, err := d.BuildPenaltyMessage(penaltyList, roundConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate penalty message: %w", err)
+	}
+
+	summary := fmt.Sprintf("🧪 Dry run of the penalty announcement for %s. Nothing was posted or pinned, and no penalty DMs were sent. The announcement follows, with mentions disabled.",
+		roundConfig.PreviousRound)
+	return &dryRun{
+		summary: d.withUnresolvedDrivers(summary, penaltyList),
+		message: msg,
+	}, nil
+}
+
+// runRaceSetupDryRun resolves the drivers and renders the race-day
+// announcement, the briefing doc text, the DQ list and the next round config
+// without copying, posting, pinning or scheduling anything.
+func (d *DiscordClient) runRaceSetupDryRun(roundConfig *config.RoundConfig, sgClient *simgrid.SimGridClient) (*dryRun, error) {
+	conf := d.snapshotConfig()
+	if err := d.mergeAbsencePenalties(conf.Season, roundConfig); err != nil {
+		return nil, err
+	}
+	driverLookup, err := sgClient.BuildDriverLookup(conf.ChampionshipId)
+	if err != nil {
+		return nil, err
+	}
+
+	penalties, err := buildPenaltyList(driverLookup, roundConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	briefingTime, err := d.briefingTime(roundConfig.NextRound)
+	if err != nil {
+		return nil, err
+	}
+
+	briefing, err := gcloud.PreviewBriefing(&config.Config{
+		RoundConfig: *roundConfig,
+		BotConfig:   conf,
+	}, penalties, briefingTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate briefing doc: %w", err)
+	}
+
+	msg, err := d.BuildBriefingMessage(penalties, dryRunBriefingURL, roundConfig, briefingTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate briefingmessage: %w", err)
+	}
+
+	details := "**Briefing doc** text attached." + dqList(penalties)
+
+	var attachment string
+	if roundConfig.NextRound.Track != "" {
+		nextRound, err := sgClient.GetNextRound(conf.ChampionshipId, roundConfig.NextRound)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting details for next round: %w", err)
+		}
+		attachment, err = writeNextRoundConfig(buildNextRoundConfig(roundConfig.NextRound, nextRound, penalties), conf.Season)
+		if err != nil {
+			return nil, err
+		}
+		details += "\nThe next round config is attached. Race setup fills in the penalty tracker link when it copies the tracker."
+	}
+
+	summary := fmt.Sprintf("🧪 Dry run of race setup for %s. No Drive files were copied, nothing was posted or pinned, and no events or reminders were scheduled. The race-day announcement follows, with mentions disabled and a placeholder briefing doc link.",
+		roundConfig.NextRound)
+	return &dryRun{
+		summary:      d.withUnresolvedDrivers(summary, penalties),
+		message:      msg,
+		details:      details,
+		briefing:     briefing,
+		briefingName: fmt.Sprintf("Drivers Briefing Round %d.txt", roundConfig.NextRound.Number),
+		attachment:   attachment,
+	}, nil
+}
+
+// sendDryRun DMs preview to userID: the summary, the announcement as it would
+// be posted but without pinging anyone, then the details.
+func (d *DiscordClient) sendDryRun(userID snowflake.ID, preview *dryRun) error {
+	if preview.attachment != "" {
+		defer func() {
+			if err := os.Remove(preview.attachment); err != nil {
+				fmt.Printf("Error removing temp file %s: %s\n", preview.attachment, err)
+			}
+		}()
+	}
+
+	channel, err := d.rest.CreateDMChannel(userID)
+	if err != nil {
+		return err
+	}
+	if _, err := d.rest.CreateMessage(channel.ID(), buildMessage(preview.summary)); err != nil {
+		return err
+	}
+
+	announcement := preview.message
+	announcement.AllowedMentions = &discord.AllowedMentions{}
+	if _, err := d.rest.CreateMessage(channel.ID(), announcement); err != nil {
+		return err
+	}
+
+	if preview.details == "" {
+		return nil
+	}
+	details := buildMessage(preview.details)
+	if preview.briefing != "" {
+		details.Files = append(details.Files, &discord.File{Name: preview.briefingName, Reader: strings.NewReader(preview.briefing)})
+	}
+	if preview.attachment != "" {
+		file, err := os.Open(preview.attachment) // #nosec G304 -- path written by this process from config, not user input
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		details.Files = append(details.Files, &discord.File{Name: preview.attachment, Reader: file})
+	}
+	_, err = d.rest.CreateMessage(channel.ID(), details)
+	return err
+}
+
+// respondDryRun sends a dry run to the admin who asked for it, replying in
+// the channel only to say where it went or why it failed.
+func (d *DiscordClient) respondDryRun(event *events.MessageCreate, preview *dryRun, err error) {
+	if err != nil {
+		sendBotResponse(event, err.Error(), "")
+		return
+	}
+	if err := d.sendDryRun(event.Message.Author.ID, preview); err != nil {
+		sendBotResponse(event, fmt.Sprintf("Failed sending you the dry run: %s", err), "")
+		return
+	}
+	sendBotResponse(event, "Sent you the dry run in DMs.", "")
+}
@@ -0,0 +1,132 @@
+package discord
+
+import (
+	"io"
+	"strings"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("dry runs", func() {
+	var (
+		league      *testLeague
+		client      *DiscordClient
+		sgClient    *simgrid.SimGridClient
+		roundConfig *config.RoundConfig
+		posted      map[snowflake.ID][]dgo.MessageCreate
+		pinned      int
+	)
+
+	BeforeEach(func() {
+		league = newTestLeague("dryrun", config.BotConfig{
+			DiscordChannelId: snowflakeID(111),
+			DiscordRoleName:  "Rookies",
+		}, nil)
+		client, sgClient = league.client, league.sgClient
+
+		posted = map[snowflake.ID][]dgo.MessageCreate{}
+		pinned = 0
+		league.stub.getRolesFn = func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+			return []dgo.Role{{Name: "Rookies", ID: snowflakeID(777)}}, nil
+		}
+		league.stub.createDMChannelFn = func(userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.DMChannel, error) {
+			return dmChannel(userID + 1000), nil
+		}
+		league.stub.createMessageFn = func(channelID snowflake.ID, m dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+			posted[channelID] = append(posted[channelID], m)
+			return &dgo.Message{}, nil
+		}
+		league.stub.pinMessageFn = func(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error {
+			pinned++
+			return nil
+		}
+
+		roundConfig = &config.RoundConfig{
+			PreviousRound: config.Round{Number: 1, Track: "Monza"},
+			NextRound:     config.Round{Number: 2, Track: "Imola"},
+			Penalties:     config.Penalty{QualiBansR1: []int{1}},
+		}
+	})
+
+	AfterEach(func() {
+		league.close()
+	})
+
+	It("recognizes the flag", func() {
+		Expect(hasDryRunFlag([]string{"--dry-run"})).To(BeTrue())
+		Expect(hasDryRunFlag([]string{"--dry-runs"})).To(BeFalse())
+		Expect(hasDryRunFlag(nil)).To(BeFalse())
+	})
+
+	It("DMs the penalty announcement to the admin without posting anything", func() {
+		preview, err := client.runAnnouncePenaltiesDryRun(roundConfig, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(posted).To(BeEmpty())
+
+		Expect(client.sendDryRun(snowflakeID(9), preview)).To(Succeed())
+		Expect(posted).To(HaveLen(1))
+		dms := posted[snowflakeID(1009)]
+		Expect(dms).To(HaveLen(2))
+		Expect(dms[0].Content).To(HavePrefix("🧪 Dry run of the penalty announcement for Round 1 - Monza."))
+		Expect(dms[1].Content).To(ContainSubstring("<@42>"))
+		Expect(*dms[1].AllowedMentions).To(Equal(dgo.AllowedMentions{}))
+		Expect(pinned).To(BeZero())
+	})
+
+	It("DMs the race-day announcement, briefing doc text, DQ list and next round config", func() {
+		preview, err := client.runRaceSetupDryRun(roundConfig, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(posted).To(BeEmpty())
+		Expect(preview.attachment).To(BeAnExistingFile())
+
+		Expect(client.sendDryRun(snowflakeID(9), preview)).To(Succeed())
+		dms := posted[snowflakeID(1009)]
+		Expect(dms).To(HaveLen(3))
+		Expect(dms[0].Content).To(HavePrefix("🧪 Dry run of race setup for Round 2 - Imola. No Drive files were copied"))
+		Expect(dms[1].Content).To(ContainSubstring("<@&777>"))
+		Expect(dms[1].Content).To(ContainSubstring(dryRunBriefingURL))
+		Expect(*dms[1].AllowedMentions).To(Equal(dgo.AllowedMentions{}))
+
+		Expect(dms[2].Content).To(HavePrefix("**Briefing doc** text attached."))
+		Expect(dms[2].Content).To(ContainSubstring("/dq 1"))
+		Expect(dms[2].Files).To(HaveLen(2))
+		Expect(dms[2].Files[0].Name).To(Equal("Drivers Briefing Round 2.txt"))
+		briefing, err := io.ReadAll(dms[2].Files[0].Reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(briefing)).To(ContainSubstring("Drivers Serving Penalties Tonight\nRace 1 Quali Bans\n  • #001 - Max Verstappen\n"))
+		Expect(string(briefing)).To(ContainSubstring("[Track Name] → Imola"))
+		Expect(dms[2].Files[1].Name).To(Equal("2026-fall-round-3-spa.yml"))
+		Expect(preview.attachment).NotTo(BeAnExistingFile())
+		Expect(pinned).To(BeZero())
+	})
+
+	It("keeps each message within Discord's limit however long the briefing is", func() {
+		preview := &dryRun{
+			summary:      "🧪 Dry run",
+			details:      "**Briefing doc** text attached.",
+			briefing:     strings.Repeat("  • #001 - Max Verstappen\n", 200),
+			briefingName: "Drivers Briefing Round 2.txt",
+		}
+		Expect(client.sendDryRun(snowflakeID(9), preview)).To(Succeed())
+		dms := posted[snowflakeID(1009)]
+		Expect(dms).To(HaveLen(3))
+		for _, dm := range dms {
+			Expect(len(dm.Content)).To(BeNumerically("<=", 2000))
+		}
+		Expect(dms[2].Files).To(HaveLen(1))
+	})
+
+	It("skips the next round config when there is no next track", func() {
+		roundConfig.NextRound.Track = ""
+		preview, err := client.runRaceSetupDryRun(roundConfig, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preview.attachment).To(BeEmpty())
+		Expect(preview.details).NotTo(ContainSubstring("next round config"))
+	})
+})
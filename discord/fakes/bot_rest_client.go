@@ -12,6 +12,47 @@ import (
 )
 
 type FakeBotRestClient struct {
+	AddMemberRoleStub        func(snowflake.ID, snowflake.ID, snowflake.ID, ...rest.RequestOpt) error
+	addMemberRoleMutex       sync.RWMutex
+	addMemberRoleArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 snowflake.ID
+		arg4 []rest.RequestOpt
+	}
+	addMemberRoleReturns struct {
+		result1 error
+	}
+	addMemberRoleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AddThreadMemberStub        func(snowflake.ID, snowflake.ID, ...rest.RequestOpt) error
+	addThreadMemberMutex       sync.RWMutex
+	addThreadMemberArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 []rest.RequestOpt
+	}
+	addThreadMemberReturns struct {
+		result1 error
+	}
+	addThreadMemberReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateDMChannelStub        func(snowflake.ID, ...rest.RequestOpt) (*discorda.DMChannel, error)
+	createDMChannelMutex       sync.RWMutex
+	createDMChannelArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 []rest.RequestOpt
+	}
+	createDMChannelReturns struct {
+		result1 *discorda.DMChannel
+		result2 error
+	}
+	createDMChannelReturnsOnCall map[int]struct {
+		result1 *discorda.DMChannel
+		result2 error
+	}
 	CreateGuildScheduledEventStub        func(snowflake.ID, discorda.GuildScheduledEventCreate, ...rest.RequestOpt) (*discorda.GuildScheduledEvent, error)
 	createGuildScheduledEventMutex       sync.RWMutex
 	createGuildScheduledEventArgsForCall []struct {
@@ -42,6 +83,75 @@ type FakeBotRestClient struct {
 		result1 *discorda.Message
 		result2 error
 	}
+	CreateThreadStub        func(snowflake.ID, discorda.ThreadCreate, ...rest.RequestOpt) (*discorda.GuildThread, error)
+	createThreadMutex       sync.RWMutex
+	createThreadArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 discorda.ThreadCreate
+		arg3 []rest.RequestOpt
+	}
+	createThreadReturns struct {
+		result1 *discorda.GuildThread
+		result2 error
+	}
+	createThreadReturnsOnCall map[int]struct {
+		result1 *discorda.GuildThread
+		result2 error
+	}
+	CreateThreadFromMessageStub        func(snowflake.ID, snowflake.ID, discorda.ThreadCreateFromMessage, ...rest.RequestOpt) (*discorda.GuildThread, error)
+	createThreadFromMessageMutex       sync.RWMutex
+	createThreadFromMessageArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 discorda.ThreadCreateFromMessage
+		arg4 []rest.RequestOpt
+	}
+	createThreadFromMessageReturns struct {
+		result1 *discorda.GuildThread
+		result2 error
+	}
+	createThreadFromMessageReturnsOnCall map[int]struct {
+		result1 *discorda.GuildThread
+		result2 error
+	}
+	DeleteChannelStub        func(snowflake.ID, ...rest.RequestOpt) error
+	deleteChannelMutex       sync.RWMutex
+	deleteChannelArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 []rest.RequestOpt
+	}
+	deleteChannelReturns struct {
+		result1 error
+	}
+	deleteChannelReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteGuildScheduledEventStub        func(snowflake.ID, snowflake.ID, ...rest.RequestOpt) error
+	deleteGuildScheduledEventMutex       sync.RWMutex
+	deleteGuildScheduledEventArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 []rest.RequestOpt
+	}
+	deleteGuildScheduledEventReturns struct {
+		result1 error
+	}
+	deleteGuildScheduledEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteMessageStub        func(snowflake.ID, snowflake.ID, ...rest.RequestOpt) error
+	deleteMessageMutex       sync.RWMutex
+	deleteMessageArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 []rest.RequestOpt
+	}
+	deleteMessageReturns struct {
+		result1 error
+	}
+	deleteMessageReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetChannelStub        func(snowflake.ID, ...rest.RequestOpt) (discorda.Channel, error)
 	getChannelMutex       sync.RWMutex
 	getChannelArgsForCall []struct {
@@ -56,22 +166,6 @@ type FakeBotRestClient struct {
 		result1 discorda.Channel
 		result2 error
 	}
-	GetMembersStub        func(snowflake.ID, int, snowflake.ID, ...rest.RequestOpt) ([]discorda.Member, error)
-	getMembersMutex       sync.RWMutex
-	getMembersArgsForCall []struct {
-		arg1 snowflake.ID
-		arg2 int
-		arg3 snowflake.ID
-		arg4 []rest.RequestOpt
-	}
-	getMembersReturns struct {
-		result1 []discorda.Member
-		result2 error
-	}
-	getMembersReturnsOnCall map[int]struct {
-		result1 []discorda.Member
-		result2 error
-	}
 	GetChannelPinsStub        func(snowflake.ID, time.Time, int, ...rest.RequestOpt) (*discorda.ChannelPins, error)
 	getChannelPinsMutex       sync.RWMutex
 	getChannelPinsArgsForCall []struct {
@@ -88,6 +182,37 @@ type FakeBotRestClient struct {
 		result1 *discorda.ChannelPins
 		result2 error
 	}
+	GetMemberStub        func(snowflake.ID, snowflake.ID, ...rest.RequestOpt) (*discorda.Member, error)
+	getMemberMutex       sync.RWMutex
+	getMemberArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 []rest.RequestOpt
+	}
+	getMemberReturns struct {
+		result1 *discorda.Member
+		result2 error
+	}
+	getMemberReturnsOnCall map[int]struct {
+		result1 *discorda.Member
+		result2 error
+	}
+	GetMembersStub        func(snowflake.ID, int, snowflake.ID, ...rest.RequestOpt) ([]discorda.Member, error)
+	getMembersMutex       sync.RWMutex
+	getMembersArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 int
+		arg3 snowflake.ID
+		arg4 []rest.RequestOpt
+	}
+	getMembersReturns struct {
+		result1 []discorda.Member
+		result2 error
+	}
+	getMembersReturnsOnCall map[int]struct {
+		result1 []discorda.Member
+		result2 error
+	}
 	GetRolesStub        func(snowflake.ID, ...rest.RequestOpt) ([]discorda.Role, error)
 	getRolesMutex       sync.RWMutex
 	getRolesArgsForCall []struct {
@@ -115,6 +240,20 @@ type FakeBotRestClient struct {
 	pinMessageReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RemoveMemberRoleStub        func(snowflake.ID, snowflake.ID, snowflake.ID, ...rest.RequestOpt) error
+	removeMemberRoleMutex       sync.RWMutex
+	removeMemberRoleArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 snowflake.ID
+		arg4 []rest.RequestOpt
+	}
+	removeMemberRoleReturns struct {
+		result1 error
+	}
+	removeMemberRoleReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UnpinMessageStub        func(snowflake.ID, snowflake.ID, ...rest.RequestOpt) error
 	unpinMessageMutex       sync.RWMutex
 	unpinMessageArgsForCall []struct {
@@ -128,10 +267,233 @@ type FakeBotRestClient struct {
 	unpinMessageReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateChannelStub        func(snowflake.ID, discorda.ChannelUpdate, ...rest.RequestOpt) (discorda.Channel, error)
+	updateChannelMutex       sync.RWMutex
+	updateChannelArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 discorda.ChannelUpdate
+		arg3 []rest.RequestOpt
+	}
+	updateChannelReturns struct {
+		result1 discorda.Channel
+		result2 error
+	}
+	updateChannelReturnsOnCall map[int]struct {
+		result1 discorda.Channel
+		result2 error
+	}
+	UpdateMessageStub        func(snowflake.ID, snowflake.ID, discorda.MessageUpdate, ...rest.RequestOpt) (*discorda.Message, error)
+	updateMessageMutex       sync.RWMutex
+	updateMessageArgsForCall []struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 discorda.MessageUpdate
+		arg4 []rest.RequestOpt
+	}
+	updateMessageReturns struct {
+		result1 *discorda.Message
+		result2 error
+	}
+	updateMessageReturnsOnCall map[int]struct {
+		result1 *discorda.Message
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeBotRestClient) AddMemberRole(arg1 snowflake.ID, arg2 snowflake.ID, arg3 snowflake.ID, arg4 ...rest.RequestOpt) error {
+	fake.addMemberRoleMutex.Lock()
+	ret, specificReturn := fake.addMemberRoleReturnsOnCall[len(fake.addMemberRoleArgsForCall)]
+	fake.addMemberRoleArgsForCall = append(fake.addMemberRoleArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 snowflake.ID
+		arg4 []rest.RequestOpt
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.AddMemberRoleStub
+	fakeReturns := fake.addMemberRoleReturns
+	fake.recordInvocation("AddMemberRole", []interface{}{arg1, arg2, arg3, arg4})
+	fake.addMemberRoleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBotRestClient) AddMemberRoleCallCount() int {
+	fake.addMemberRoleMutex.RLock()
+	defer fake.addMemberRoleMutex.RUnlock()
+	return len(fake.addMemberRoleArgsForCall)
+}
+
+func (fake *FakeBotRestClient) AddMemberRoleCalls(stub func(snowflake.ID, snowflake.ID, snowflake.ID, ...rest.RequestOpt) error) {
+	fake.addMemberRoleMutex.Lock()
+	defer fake.addMemberRoleMutex.Unlock()
+	fake.AddMemberRoleStub = stub
+}
+
+func (fake *FakeBotRestClient) AddMemberRoleArgsForCall(i int) (snowflake.ID, snowflake.ID, snowflake.ID, []rest.RequestOpt) {
+	fake.addMemberRoleMutex.RLock()
+	defer fake.addMemberRoleMutex.RUnlock()
+	argsForCall := fake.addMemberRoleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBotRestClient) AddMemberRoleReturns(result1 error) {
+	fake.addMemberRoleMutex.Lock()
+	defer fake.addMemberRoleMutex.Unlock()
+	fake.AddMemberRoleStub = nil
+	fake.addMemberRoleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) AddMemberRoleReturnsOnCall(i int, result1 error) {
+	fake.addMemberRoleMutex.Lock()
+	defer fake.addMemberRoleMutex.Unlock()
+	fake.AddMemberRoleStub = nil
+	if fake.addMemberRoleReturnsOnCall == nil {
+		fake.addMemberRoleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addMemberRoleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) AddThreadMember(arg1 snowflake.ID, arg2 snowflake.ID, arg3 ...rest.RequestOpt) error {
+	fake.addThreadMemberMutex.Lock()
+	ret, specificReturn := fake.addThreadMemberReturnsOnCall[len(fake.addThreadMemberArgsForCall)]
+	fake.addThreadMemberArgsForCall = append(fake.addThreadMemberArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 []rest.RequestOpt
+	}{arg1, arg2, arg3})
+	stub := fake.AddThreadMemberStub
+	fakeReturns := fake.addThreadMemberReturns
+	fake.recordInvocation("AddThreadMember", []interface{}{arg1, arg2, arg3})
+	fake.addThreadMemberMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBotRestClient) AddThreadMemberCallCount() int {
+	fake.addThreadMemberMutex.RLock()
+	defer fake.addThreadMemberMutex.RUnlock()
+	return len(fake.addThreadMemberArgsForCall)
+}
+
+func (fake *FakeBotRestClient) AddThreadMemberCalls(stub func(snowflake.ID, snowflake.ID, ...rest.RequestOpt) error) {
+	fake.addThreadMemberMutex.Lock()
+	defer fake.addThreadMemberMutex.Unlock()
+	fake.AddThreadMemberStub = stub
+}
+
+func (fake *FakeBotRestClient) AddThreadMemberArgsForCall(i int) (snowflake.ID, snowflake.ID, []rest.RequestOpt) {
+	fake.addThreadMemberMutex.RLock()
+	defer fake.addThreadMemberMutex.RUnlock()
+	argsForCall := fake.addThreadMemberArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeBotRestClient) AddThreadMemberReturns(result1 error) {
+	fake.addThreadMemberMutex.Lock()
+	defer fake.addThreadMemberMutex.Unlock()
+	fake.AddThreadMemberStub = nil
+	fake.addThreadMemberReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) AddThreadMemberReturnsOnCall(i int, result1 error) {
+	fake.addThreadMemberMutex.Lock()
+	defer fake.addThreadMemberMutex.Unlock()
+	fake.AddThreadMemberStub = nil
+	if fake.addThreadMemberReturnsOnCall == nil {
+		fake.addThreadMemberReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addThreadMemberReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) CreateDMChannel(arg1 snowflake.ID, arg2 ...rest.RequestOpt) (*discorda.DMChannel, error) {
+	fake.createDMChannelMutex.Lock()
+	ret, specificReturn := fake.createDMChannelReturnsOnCall[len(fake.createDMChannelArgsForCall)]
+	fake.createDMChannelArgsForCall = append(fake.createDMChannelArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 []rest.RequestOpt
+	}{arg1, arg2})
+	stub := fake.CreateDMChannelStub
+	fakeReturns := fake.createDMChannelReturns
+	fake.recordInvocation("CreateDMChannel", []interface{}{arg1, arg2})
+	fake.createDMChannelMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBotRestClient) CreateDMChannelCallCount() int {
+	fake.createDMChannelMutex.RLock()
+	defer fake.createDMChannelMutex.RUnlock()
+	return len(fake.createDMChannelArgsForCall)
+}
+
+func (fake *FakeBotRestClient) CreateDMChannelCalls(stub func(snowflake.ID, ...rest.RequestOpt) (*discorda.DMChannel, error)) {
+	fake.createDMChannelMutex.Lock()
+	defer fake.createDMChannelMutex.Unlock()
+	fake.CreateDMChannelStub = stub
+}
+
+func (fake *FakeBotRestClient) CreateDMChannelArgsForCall(i int) (snowflake.ID, []rest.RequestOpt) {
+	fake.createDMChannelMutex.RLock()
+	defer fake.createDMChannelMutex.RUnlock()
+	argsForCall := fake.createDMChannelArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBotRestClient) CreateDMChannelReturns(result1 *discorda.DMChannel, result2 error) {
+	fake.createDMChannelMutex.Lock()
+	defer fake.createDMChannelMutex.Unlock()
+	fake.CreateDMChannelStub = nil
+	fake.createDMChannelReturns = struct {
+		result1 *discorda.DMChannel
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) CreateDMChannelReturnsOnCall(i int, result1 *discorda.DMChannel, result2 error) {
+	fake.createDMChannelMutex.Lock()
+	defer fake.createDMChannelMutex.Unlock()
+	fake.CreateDMChannelStub = nil
+	if fake.createDMChannelReturnsOnCall == nil {
+		fake.createDMChannelReturnsOnCall = make(map[int]struct {
+			result1 *discorda.DMChannel
+			result2 error
+		})
+	}
+	fake.createDMChannelReturnsOnCall[i] = struct {
+		result1 *discorda.DMChannel
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBotRestClient) CreateGuildScheduledEvent(arg1 snowflake.ID, arg2 discorda.GuildScheduledEventCreate, arg3 ...rest.RequestOpt) (*discorda.GuildScheduledEvent, error) {
 	fake.createGuildScheduledEventMutex.Lock()
 	ret, specificReturn := fake.createGuildScheduledEventReturnsOnCall[len(fake.createGuildScheduledEventArgsForCall)]
@@ -238,45 +600,499 @@ func (fake *FakeBotRestClient) CreateMessageArgsForCall(i int) (snowflake.ID, di
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeBotRestClient) CreateMessageReturns(result1 *discorda.Message, result2 error) {
-	fake.createMessageMutex.Lock()
-	defer fake.createMessageMutex.Unlock()
-	fake.CreateMessageStub = nil
-	fake.createMessageReturns = struct {
-		result1 *discorda.Message
+func (fake *FakeBotRestClient) CreateMessageReturns(result1 *discorda.Message, result2 error) {
+	fake.createMessageMutex.Lock()
+	defer fake.createMessageMutex.Unlock()
+	fake.CreateMessageStub = nil
+	fake.createMessageReturns = struct {
+		result1 *discorda.Message
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) CreateMessageReturnsOnCall(i int, result1 *discorda.Message, result2 error) {
+	fake.createMessageMutex.Lock()
+	defer fake.createMessageMutex.Unlock()
+	fake.CreateMessageStub = nil
+	if fake.createMessageReturnsOnCall == nil {
+		fake.createMessageReturnsOnCall = make(map[int]struct {
+			result1 *discorda.Message
+			result2 error
+		})
+	}
+	fake.createMessageReturnsOnCall[i] = struct {
+		result1 *discorda.Message
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) CreateThread(arg1 snowflake.ID, arg2 discorda.ThreadCreate, arg3 ...rest.RequestOpt) (*discorda.GuildThread, error) {
+	fake.createThreadMutex.Lock()
+	ret, specificReturn := fake.createThreadReturnsOnCall[len(fake.createThreadArgsForCall)]
+	fake.createThreadArgsForCall = append(fake.createThreadArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 discorda.ThreadCreate
+		arg3 []rest.RequestOpt
+	}{arg1, arg2, arg3})
+	stub := fake.CreateThreadStub
+	fakeReturns := fake.createThreadReturns
+	fake.recordInvocation("CreateThread", []interface{}{arg1, arg2, arg3})
+	fake.createThreadMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBotRestClient) CreateThreadCallCount() int {
+	fake.createThreadMutex.RLock()
+	defer fake.createThreadMutex.RUnlock()
+	return len(fake.createThreadArgsForCall)
+}
+
+func (fake *FakeBotRestClient) CreateThreadCalls(stub func(snowflake.ID, discorda.ThreadCreate, ...rest.RequestOpt) (*discorda.GuildThread, error)) {
+	fake.createThreadMutex.Lock()
+	defer fake.createThreadMutex.Unlock()
+	fake.CreateThreadStub = stub
+}
+
+func (fake *FakeBotRestClient) CreateThreadArgsForCall(i int) (snowflake.ID, discorda.ThreadCreate, []rest.RequestOpt) {
+	fake.createThreadMutex.RLock()
+	defer fake.createThreadMutex.RUnlock()
+	argsForCall := fake.createThreadArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeBotRestClient) CreateThreadReturns(result1 *discorda.GuildThread, result2 error) {
+	fake.createThreadMutex.Lock()
+	defer fake.createThreadMutex.Unlock()
+	fake.CreateThreadStub = nil
+	fake.createThreadReturns = struct {
+		result1 *discorda.GuildThread
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) CreateThreadReturnsOnCall(i int, result1 *discorda.GuildThread, result2 error) {
+	fake.createThreadMutex.Lock()
+	defer fake.createThreadMutex.Unlock()
+	fake.CreateThreadStub = nil
+	if fake.createThreadReturnsOnCall == nil {
+		fake.createThreadReturnsOnCall = make(map[int]struct {
+			result1 *discorda.GuildThread
+			result2 error
+		})
+	}
+	fake.createThreadReturnsOnCall[i] = struct {
+		result1 *discorda.GuildThread
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) CreateThreadFromMessage(arg1 snowflake.ID, arg2 snowflake.ID, arg3 discorda.ThreadCreateFromMessage, arg4 ...rest.RequestOpt) (*discorda.GuildThread, error) {
+	fake.createThreadFromMessageMutex.Lock()
+	ret, specificReturn := fake.createThreadFromMessageReturnsOnCall[len(fake.createThreadFromMessageArgsForCall)]
+	fake.createThreadFromMessageArgsForCall = append(fake.createThreadFromMessageArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 discorda.ThreadCreateFromMessage
+		arg4 []rest.RequestOpt
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.CreateThreadFromMessageStub
+	fakeReturns := fake.createThreadFromMessageReturns
+	fake.recordInvocation("CreateThreadFromMessage", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createThreadFromMessageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBotRestClient) CreateThreadFromMessageCallCount() int {
+	fake.createThreadFromMessageMutex.RLock()
+	defer fake.createThreadFromMessageMutex.RUnlock()
+	return len(fake.createThreadFromMessageArgsForCall)
+}
+
+func (fake *FakeBotRestClient) CreateThreadFromMessageCalls(stub func(snowflake.ID, snowflake.ID, discorda.ThreadCreateFromMessage, ...rest.RequestOpt) (*discorda.GuildThread, error)) {
+	fake.createThreadFromMessageMutex.Lock()
+	defer fake.createThreadFromMessageMutex.Unlock()
+	fake.CreateThreadFromMessageStub = stub
+}
+
+func (fake *FakeBotRestClient) CreateThreadFromMessageArgsForCall(i int) (snowflake.ID, snowflake.ID, discorda.ThreadCreateFromMessage, []rest.RequestOpt) {
+	fake.createThreadFromMessageMutex.RLock()
+	defer fake.createThreadFromMessageMutex.RUnlock()
+	argsForCall := fake.createThreadFromMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBotRestClient) CreateThreadFromMessageReturns(result1 *discorda.GuildThread, result2 error) {
+	fake.createThreadFromMessageMutex.Lock()
+	defer fake.createThreadFromMessageMutex.Unlock()
+	fake.CreateThreadFromMessageStub = nil
+	fake.createThreadFromMessageReturns = struct {
+		result1 *discorda.GuildThread
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) CreateThreadFromMessageReturnsOnCall(i int, result1 *discorda.GuildThread, result2 error) {
+	fake.createThreadFromMessageMutex.Lock()
+	defer fake.createThreadFromMessageMutex.Unlock()
+	fake.CreateThreadFromMessageStub = nil
+	if fake.createThreadFromMessageReturnsOnCall == nil {
+		fake.createThreadFromMessageReturnsOnCall = make(map[int]struct {
+			result1 *discorda.GuildThread
+			result2 error
+		})
+	}
+	fake.createThreadFromMessageReturnsOnCall[i] = struct {
+		result1 *discorda.GuildThread
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) DeleteChannel(arg1 snowflake.ID, arg2 ...rest.RequestOpt) error {
+	fake.deleteChannelMutex.Lock()
+	ret, specificReturn := fake.deleteChannelReturnsOnCall[len(fake.deleteChannelArgsForCall)]
+	fake.deleteChannelArgsForCall = append(fake.deleteChannelArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 []rest.RequestOpt
+	}{arg1, arg2})
+	stub := fake.DeleteChannelStub
+	fakeReturns := fake.deleteChannelReturns
+	fake.recordInvocation("DeleteChannel", []interface{}{arg1, arg2})
+	fake.deleteChannelMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBotRestClient) DeleteChannelCallCount() int {
+	fake.deleteChannelMutex.RLock()
+	defer fake.deleteChannelMutex.RUnlock()
+	return len(fake.deleteChannelArgsForCall)
+}
+
+func (fake *FakeBotRestClient) DeleteChannelCalls(stub func(snowflake.ID, ...rest.RequestOpt) error) {
+	fake.deleteChannelMutex.Lock()
+	defer fake.deleteChannelMutex.Unlock()
+	fake.DeleteChannelStub = stub
+}
+
+func (fake *FakeBotRestClient) DeleteChannelArgsForCall(i int) (snowflake.ID, []rest.RequestOpt) {
+	fake.deleteChannelMutex.RLock()
+	defer fake.deleteChannelMutex.RUnlock()
+	argsForCall := fake.deleteChannelArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBotRestClient) DeleteChannelReturns(result1 error) {
+	fake.deleteChannelMutex.Lock()
+	defer fake.deleteChannelMutex.Unlock()
+	fake.DeleteChannelStub = nil
+	fake.deleteChannelReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) DeleteChannelReturnsOnCall(i int, result1 error) {
+	fake.deleteChannelMutex.Lock()
+	defer fake.deleteChannelMutex.Unlock()
+	fake.DeleteChannelStub = nil
+	if fake.deleteChannelReturnsOnCall == nil {
+		fake.deleteChannelReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteChannelReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) DeleteGuildScheduledEvent(arg1 snowflake.ID, arg2 snowflake.ID, arg3 ...rest.RequestOpt) error {
+	fake.deleteGuildScheduledEventMutex.Lock()
+	ret, specificReturn := fake.deleteGuildScheduledEventReturnsOnCall[len(fake.deleteGuildScheduledEventArgsForCall)]
+	fake.deleteGuildScheduledEventArgsForCall = append(fake.deleteGuildScheduledEventArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 []rest.RequestOpt
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteGuildScheduledEventStub
+	fakeReturns := fake.deleteGuildScheduledEventReturns
+	fake.recordInvocation("DeleteGuildScheduledEvent", []interface{}{arg1, arg2, arg3})
+	fake.deleteGuildScheduledEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBotRestClient) DeleteGuildScheduledEventCallCount() int {
+	fake.deleteGuildScheduledEventMutex.RLock()
+	defer fake.deleteGuildScheduledEventMutex.RUnlock()
+	return len(fake.deleteGuildScheduledEventArgsForCall)
+}
+
+func (fake *FakeBotRestClient) DeleteGuildScheduledEventCalls(stub func(snowflake.ID, snowflake.ID, ...rest.RequestOpt) error) {
+	fake.deleteGuildScheduledEventMutex.Lock()
+	defer fake.deleteGuildScheduledEventMutex.Unlock()
+	fake.DeleteGuildScheduledEventStub = stub
+}
+
+func (fake *FakeBotRestClient) DeleteGuildScheduledEventArgsForCall(i int) (snowflake.ID, snowflake.ID, []rest.RequestOpt) {
+	fake.deleteGuildScheduledEventMutex.RLock()
+	defer fake.deleteGuildScheduledEventMutex.RUnlock()
+	argsForCall := fake.deleteGuildScheduledEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeBotRestClient) DeleteGuildScheduledEventReturns(result1 error) {
+	fake.deleteGuildScheduledEventMutex.Lock()
+	defer fake.deleteGuildScheduledEventMutex.Unlock()
+	fake.DeleteGuildScheduledEventStub = nil
+	fake.deleteGuildScheduledEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) DeleteGuildScheduledEventReturnsOnCall(i int, result1 error) {
+	fake.deleteGuildScheduledEventMutex.Lock()
+	defer fake.deleteGuildScheduledEventMutex.Unlock()
+	fake.DeleteGuildScheduledEventStub = nil
+	if fake.deleteGuildScheduledEventReturnsOnCall == nil {
+		fake.deleteGuildScheduledEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteGuildScheduledEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) DeleteMessage(arg1 snowflake.ID, arg2 snowflake.ID, arg3 ...rest.RequestOpt) error {
+	fake.deleteMessageMutex.Lock()
+	ret, specificReturn := fake.deleteMessageReturnsOnCall[len(fake.deleteMessageArgsForCall)]
+	fake.deleteMessageArgsForCall = append(fake.deleteMessageArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 []rest.RequestOpt
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteMessageStub
+	fakeReturns := fake.deleteMessageReturns
+	fake.recordInvocation("DeleteMessage", []interface{}{arg1, arg2, arg3})
+	fake.deleteMessageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBotRestClient) DeleteMessageCallCount() int {
+	fake.deleteMessageMutex.RLock()
+	defer fake.deleteMessageMutex.RUnlock()
+	return len(fake.deleteMessageArgsForCall)
+}
+
+func (fake *FakeBotRestClient) DeleteMessageCalls(stub func(snowflake.ID, snowflake.ID, ...rest.RequestOpt) error) {
+	fake.deleteMessageMutex.Lock()
+	defer fake.deleteMessageMutex.Unlock()
+	fake.DeleteMessageStub = stub
+}
+
+func (fake *FakeBotRestClient) DeleteMessageArgsForCall(i int) (snowflake.ID, snowflake.ID, []rest.RequestOpt) {
+	fake.deleteMessageMutex.RLock()
+	defer fake.deleteMessageMutex.RUnlock()
+	argsForCall := fake.deleteMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeBotRestClient) DeleteMessageReturns(result1 error) {
+	fake.deleteMessageMutex.Lock()
+	defer fake.deleteMessageMutex.Unlock()
+	fake.DeleteMessageStub = nil
+	fake.deleteMessageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) DeleteMessageReturnsOnCall(i int, result1 error) {
+	fake.deleteMessageMutex.Lock()
+	defer fake.deleteMessageMutex.Unlock()
+	fake.DeleteMessageStub = nil
+	if fake.deleteMessageReturnsOnCall == nil {
+		fake.deleteMessageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteMessageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) GetChannel(arg1 snowflake.ID, arg2 ...rest.RequestOpt) (discorda.Channel, error) {
+	fake.getChannelMutex.Lock()
+	ret, specificReturn := fake.getChannelReturnsOnCall[len(fake.getChannelArgsForCall)]
+	fake.getChannelArgsForCall = append(fake.getChannelArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 []rest.RequestOpt
+	}{arg1, arg2})
+	stub := fake.GetChannelStub
+	fakeReturns := fake.getChannelReturns
+	fake.recordInvocation("GetChannel", []interface{}{arg1, arg2})
+	fake.getChannelMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBotRestClient) GetChannelCallCount() int {
+	fake.getChannelMutex.RLock()
+	defer fake.getChannelMutex.RUnlock()
+	return len(fake.getChannelArgsForCall)
+}
+
+func (fake *FakeBotRestClient) GetChannelCalls(stub func(snowflake.ID, ...rest.RequestOpt) (discorda.Channel, error)) {
+	fake.getChannelMutex.Lock()
+	defer fake.getChannelMutex.Unlock()
+	fake.GetChannelStub = stub
+}
+
+func (fake *FakeBotRestClient) GetChannelArgsForCall(i int) (snowflake.ID, []rest.RequestOpt) {
+	fake.getChannelMutex.RLock()
+	defer fake.getChannelMutex.RUnlock()
+	argsForCall := fake.getChannelArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBotRestClient) GetChannelReturns(result1 discorda.Channel, result2 error) {
+	fake.getChannelMutex.Lock()
+	defer fake.getChannelMutex.Unlock()
+	fake.GetChannelStub = nil
+	fake.getChannelReturns = struct {
+		result1 discorda.Channel
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) GetChannelReturnsOnCall(i int, result1 discorda.Channel, result2 error) {
+	fake.getChannelMutex.Lock()
+	defer fake.getChannelMutex.Unlock()
+	fake.GetChannelStub = nil
+	if fake.getChannelReturnsOnCall == nil {
+		fake.getChannelReturnsOnCall = make(map[int]struct {
+			result1 discorda.Channel
+			result2 error
+		})
+	}
+	fake.getChannelReturnsOnCall[i] = struct {
+		result1 discorda.Channel
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) GetChannelPins(arg1 snowflake.ID, arg2 time.Time, arg3 int, arg4 ...rest.RequestOpt) (*discorda.ChannelPins, error) {
+	fake.getChannelPinsMutex.Lock()
+	ret, specificReturn := fake.getChannelPinsReturnsOnCall[len(fake.getChannelPinsArgsForCall)]
+	fake.getChannelPinsArgsForCall = append(fake.getChannelPinsArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 time.Time
+		arg3 int
+		arg4 []rest.RequestOpt
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GetChannelPinsStub
+	fakeReturns := fake.getChannelPinsReturns
+	fake.recordInvocation("GetChannelPins", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getChannelPinsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBotRestClient) GetChannelPinsCallCount() int {
+	fake.getChannelPinsMutex.RLock()
+	defer fake.getChannelPinsMutex.RUnlock()
+	return len(fake.getChannelPinsArgsForCall)
+}
+
+func (fake *FakeBotRestClient) GetChannelPinsCalls(stub func(snowflake.ID, time.Time, int, ...rest.RequestOpt) (*discorda.ChannelPins, error)) {
+	fake.getChannelPinsMutex.Lock()
+	defer fake.getChannelPinsMutex.Unlock()
+	fake.GetChannelPinsStub = stub
+}
+
+func (fake *FakeBotRestClient) GetChannelPinsArgsForCall(i int) (snowflake.ID, time.Time, int, []rest.RequestOpt) {
+	fake.getChannelPinsMutex.RLock()
+	defer fake.getChannelPinsMutex.RUnlock()
+	argsForCall := fake.getChannelPinsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBotRestClient) GetChannelPinsReturns(result1 *discorda.ChannelPins, result2 error) {
+	fake.getChannelPinsMutex.Lock()
+	defer fake.getChannelPinsMutex.Unlock()
+	fake.GetChannelPinsStub = nil
+	fake.getChannelPinsReturns = struct {
+		result1 *discorda.ChannelPins
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeBotRestClient) CreateMessageReturnsOnCall(i int, result1 *discorda.Message, result2 error) {
-	fake.createMessageMutex.Lock()
-	defer fake.createMessageMutex.Unlock()
-	fake.CreateMessageStub = nil
-	if fake.createMessageReturnsOnCall == nil {
-		fake.createMessageReturnsOnCall = make(map[int]struct {
-			result1 *discorda.Message
+func (fake *FakeBotRestClient) GetChannelPinsReturnsOnCall(i int, result1 *discorda.ChannelPins, result2 error) {
+	fake.getChannelPinsMutex.Lock()
+	defer fake.getChannelPinsMutex.Unlock()
+	fake.GetChannelPinsStub = nil
+	if fake.getChannelPinsReturnsOnCall == nil {
+		fake.getChannelPinsReturnsOnCall = make(map[int]struct {
+			result1 *discorda.ChannelPins
 			result2 error
 		})
 	}
-	fake.createMessageReturnsOnCall[i] = struct {
-		result1 *discorda.Message
+	fake.getChannelPinsReturnsOnCall[i] = struct {
+		result1 *discorda.ChannelPins
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeBotRestClient) GetChannel(arg1 snowflake.ID, arg2 ...rest.RequestOpt) (discorda.Channel, error) {
-	fake.getChannelMutex.Lock()
-	ret, specificReturn := fake.getChannelReturnsOnCall[len(fake.getChannelArgsForCall)]
-	fake.getChannelArgsForCall = append(fake.getChannelArgsForCall, struct {
+func (fake *FakeBotRestClient) GetMember(arg1 snowflake.ID, arg2 snowflake.ID, arg3 ...rest.RequestOpt) (*discorda.Member, error) {
+	fake.getMemberMutex.Lock()
+	ret, specificReturn := fake.getMemberReturnsOnCall[len(fake.getMemberArgsForCall)]
+	fake.getMemberArgsForCall = append(fake.getMemberArgsForCall, struct {
 		arg1 snowflake.ID
-		arg2 []rest.RequestOpt
-	}{arg1, arg2})
-	stub := fake.GetChannelStub
-	fakeReturns := fake.getChannelReturns
-	fake.recordInvocation("GetChannel", []interface{}{arg1, arg2})
-	fake.getChannelMutex.Unlock()
+		arg2 snowflake.ID
+		arg3 []rest.RequestOpt
+	}{arg1, arg2, arg3})
+	stub := fake.GetMemberStub
+	fakeReturns := fake.getMemberReturns
+	fake.recordInvocation("GetMember", []interface{}{arg1, arg2, arg3})
+	fake.getMemberMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2...)
+		return stub(arg1, arg2, arg3...)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -284,47 +1100,47 @@ func (fake *FakeBotRestClient) GetChannel(arg1 snowflake.ID, arg2 ...rest.Reques
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeBotRestClient) GetChannelCallCount() int {
-	fake.getChannelMutex.RLock()
-	defer fake.getChannelMutex.RUnlock()
-	return len(fake.getChannelArgsForCall)
+func (fake *FakeBotRestClient) GetMemberCallCount() int {
+	fake.getMemberMutex.RLock()
+	defer fake.getMemberMutex.RUnlock()
+	return len(fake.getMemberArgsForCall)
 }
 
-func (fake *FakeBotRestClient) GetChannelCalls(stub func(snowflake.ID, ...rest.RequestOpt) (discorda.Channel, error)) {
-	fake.getChannelMutex.Lock()
-	defer fake.getChannelMutex.Unlock()
-	fake.GetChannelStub = stub
+func (fake *FakeBotRestClient) GetMemberCalls(stub func(snowflake.ID, snowflake.ID, ...rest.RequestOpt) (*discorda.Member, error)) {
+	fake.getMemberMutex.Lock()
+	defer fake.getMemberMutex.Unlock()
+	fake.GetMemberStub = stub
 }
 
-func (fake *FakeBotRestClient) GetChannelArgsForCall(i int) (snowflake.ID, []rest.RequestOpt) {
-	fake.getChannelMutex.RLock()
-	defer fake.getChannelMutex.RUnlock()
-	argsForCall := fake.getChannelArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+func (fake *FakeBotRestClient) GetMemberArgsForCall(i int) (snowflake.ID, snowflake.ID, []rest.RequestOpt) {
+	fake.getMemberMutex.RLock()
+	defer fake.getMemberMutex.RUnlock()
+	argsForCall := fake.getMemberArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeBotRestClient) GetChannelReturns(result1 discorda.Channel, result2 error) {
-	fake.getChannelMutex.Lock()
-	defer fake.getChannelMutex.Unlock()
-	fake.GetChannelStub = nil
-	fake.getChannelReturns = struct {
-		result1 discorda.Channel
+func (fake *FakeBotRestClient) GetMemberReturns(result1 *discorda.Member, result2 error) {
+	fake.getMemberMutex.Lock()
+	defer fake.getMemberMutex.Unlock()
+	fake.GetMemberStub = nil
+	fake.getMemberReturns = struct {
+		result1 *discorda.Member
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeBotRestClient) GetChannelReturnsOnCall(i int, result1 discorda.Channel, result2 error) {
-	fake.getChannelMutex.Lock()
-	defer fake.getChannelMutex.Unlock()
-	fake.GetChannelStub = nil
-	if fake.getChannelReturnsOnCall == nil {
-		fake.getChannelReturnsOnCall = make(map[int]struct {
-			result1 discorda.Channel
+func (fake *FakeBotRestClient) GetMemberReturnsOnCall(i int, result1 *discorda.Member, result2 error) {
+	fake.getMemberMutex.Lock()
+	defer fake.getMemberMutex.Unlock()
+	fake.GetMemberStub = nil
+	if fake.getMemberReturnsOnCall == nil {
+		fake.getMemberReturnsOnCall = make(map[int]struct {
+			result1 *discorda.Member
 			result2 error
 		})
 	}
-	fake.getChannelReturnsOnCall[i] = struct {
-		result1 discorda.Channel
+	fake.getMemberReturnsOnCall[i] = struct {
+		result1 *discorda.Member
 		result2 error
 	}{result1, result2}
 }
@@ -396,73 +1212,6 @@ func (fake *FakeBotRestClient) GetMembersReturnsOnCall(i int, result1 []discorda
 	}{result1, result2}
 }
 
-func (fake *FakeBotRestClient) GetChannelPins(arg1 snowflake.ID, arg2 time.Time, arg3 int, arg4 ...rest.RequestOpt) (*discorda.ChannelPins, error) {
-	fake.getChannelPinsMutex.Lock()
-	ret, specificReturn := fake.getChannelPinsReturnsOnCall[len(fake.getChannelPinsArgsForCall)]
-	fake.getChannelPinsArgsForCall = append(fake.getChannelPinsArgsForCall, struct {
-		arg1 snowflake.ID
-		arg2 time.Time
-		arg3 int
-		arg4 []rest.RequestOpt
-	}{arg1, arg2, arg3, arg4})
-	stub := fake.GetChannelPinsStub
-	fakeReturns := fake.getChannelPinsReturns
-	fake.recordInvocation("GetChannelPins", []interface{}{arg1, arg2, arg3, arg4})
-	fake.getChannelPinsMutex.Unlock()
-	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4...)
-	}
-	if specificReturn {
-		return ret.result1, ret.result2
-	}
-	return fakeReturns.result1, fakeReturns.result2
-}
-
-func (fake *FakeBotRestClient) GetChannelPinsCallCount() int {
-	fake.getChannelPinsMutex.RLock()
-	defer fake.getChannelPinsMutex.RUnlock()
-	return len(fake.getChannelPinsArgsForCall)
-}
-
-func (fake *FakeBotRestClient) GetChannelPinsCalls(stub func(snowflake.ID, time.Time, int, ...rest.RequestOpt) (*discorda.ChannelPins, error)) {
-	fake.getChannelPinsMutex.Lock()
-	defer fake.getChannelPinsMutex.Unlock()
-	fake.GetChannelPinsStub = stub
-}
-
-func (fake *FakeBotRestClient) GetChannelPinsArgsForCall(i int) (snowflake.ID, time.Time, int, []rest.RequestOpt) {
-	fake.getChannelPinsMutex.RLock()
-	defer fake.getChannelPinsMutex.RUnlock()
-	argsForCall := fake.getChannelPinsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
-}
-
-func (fake *FakeBotRestClient) GetChannelPinsReturns(result1 *discorda.ChannelPins, result2 error) {
-	fake.getChannelPinsMutex.Lock()
-	defer fake.getChannelPinsMutex.Unlock()
-	fake.GetChannelPinsStub = nil
-	fake.getChannelPinsReturns = struct {
-		result1 *discorda.ChannelPins
-		result2 error
-	}{result1, result2}
-}
-
-func (fake *FakeBotRestClient) GetChannelPinsReturnsOnCall(i int, result1 *discorda.ChannelPins, result2 error) {
-	fake.getChannelPinsMutex.Lock()
-	defer fake.getChannelPinsMutex.Unlock()
-	fake.GetChannelPinsStub = nil
-	if fake.getChannelPinsReturnsOnCall == nil {
-		fake.getChannelPinsReturnsOnCall = make(map[int]struct {
-			result1 *discorda.ChannelPins
-			result2 error
-		})
-	}
-	fake.getChannelPinsReturnsOnCall[i] = struct {
-		result1 *discorda.ChannelPins
-		result2 error
-	}{result1, result2}
-}
-
 func (fake *FakeBotRestClient) GetRoles(arg1 snowflake.ID, arg2 ...rest.RequestOpt) ([]discorda.Role, error) {
 	fake.getRolesMutex.Lock()
 	ret, specificReturn := fake.getRolesReturnsOnCall[len(fake.getRolesArgsForCall)]
@@ -591,6 +1340,70 @@ func (fake *FakeBotRestClient) PinMessageReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBotRestClient) RemoveMemberRole(arg1 snowflake.ID, arg2 snowflake.ID, arg3 snowflake.ID, arg4 ...rest.RequestOpt) error {
+	fake.removeMemberRoleMutex.Lock()
+	ret, specificReturn := fake.removeMemberRoleReturnsOnCall[len(fake.removeMemberRoleArgsForCall)]
+	fake.removeMemberRoleArgsForCall = append(fake.removeMemberRoleArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 snowflake.ID
+		arg4 []rest.RequestOpt
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RemoveMemberRoleStub
+	fakeReturns := fake.removeMemberRoleReturns
+	fake.recordInvocation("RemoveMemberRole", []interface{}{arg1, arg2, arg3, arg4})
+	fake.removeMemberRoleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBotRestClient) RemoveMemberRoleCallCount() int {
+	fake.removeMemberRoleMutex.RLock()
+	defer fake.removeMemberRoleMutex.RUnlock()
+	return len(fake.removeMemberRoleArgsForCall)
+}
+
+func (fake *FakeBotRestClient) RemoveMemberRoleCalls(stub func(snowflake.ID, snowflake.ID, snowflake.ID, ...rest.RequestOpt) error) {
+	fake.removeMemberRoleMutex.Lock()
+	defer fake.removeMemberRoleMutex.Unlock()
+	fake.RemoveMemberRoleStub = stub
+}
+
+func (fake *FakeBotRestClient) RemoveMemberRoleArgsForCall(i int) (snowflake.ID, snowflake.ID, snowflake.ID, []rest.RequestOpt) {
+	fake.removeMemberRoleMutex.RLock()
+	defer fake.removeMemberRoleMutex.RUnlock()
+	argsForCall := fake.removeMemberRoleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBotRestClient) RemoveMemberRoleReturns(result1 error) {
+	fake.removeMemberRoleMutex.Lock()
+	defer fake.removeMemberRoleMutex.Unlock()
+	fake.RemoveMemberRoleStub = nil
+	fake.removeMemberRoleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBotRestClient) RemoveMemberRoleReturnsOnCall(i int, result1 error) {
+	fake.removeMemberRoleMutex.Lock()
+	defer fake.removeMemberRoleMutex.Unlock()
+	fake.RemoveMemberRoleStub = nil
+	if fake.removeMemberRoleReturnsOnCall == nil {
+		fake.removeMemberRoleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeMemberRoleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBotRestClient) UnpinMessage(arg1 snowflake.ID, arg2 snowflake.ID, arg3 ...rest.RequestOpt) error {
 	fake.unpinMessageMutex.Lock()
 	ret, specificReturn := fake.unpinMessageReturnsOnCall[len(fake.unpinMessageArgsForCall)]
@@ -654,9 +1467,182 @@ func (fake *FakeBotRestClient) UnpinMessageReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBotRestClient) UpdateChannel(arg1 snowflake.ID, arg2 discorda.ChannelUpdate, arg3 ...rest.RequestOpt) (discorda.Channel, error) {
+	fake.updateChannelMutex.Lock()
+	ret, specificReturn := fake.updateChannelReturnsOnCall[len(fake.updateChannelArgsForCall)]
+	fake.updateChannelArgsForCall = append(fake.updateChannelArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 discorda.ChannelUpdate
+		arg3 []rest.RequestOpt
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateChannelStub
+	fakeReturns := fake.updateChannelReturns
+	fake.recordInvocation("UpdateChannel", []interface{}{arg1, arg2, arg3})
+	fake.updateChannelMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBotRestClient) UpdateChannelCallCount() int {
+	fake.updateChannelMutex.RLock()
+	defer fake.updateChannelMutex.RUnlock()
+	return len(fake.updateChannelArgsForCall)
+}
+
+func (fake *FakeBotRestClient) UpdateChannelCalls(stub func(snowflake.ID, discorda.ChannelUpdate, ...rest.RequestOpt) (discorda.Channel, error)) {
+	fake.updateChannelMutex.Lock()
+	defer fake.updateChannelMutex.Unlock()
+	fake.UpdateChannelStub = stub
+}
+
+func (fake *FakeBotRestClient) UpdateChannelArgsForCall(i int) (snowflake.ID, discorda.ChannelUpdate, []rest.RequestOpt) {
+	fake.updateChannelMutex.RLock()
+	defer fake.updateChannelMutex.RUnlock()
+	argsForCall := fake.updateChannelArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeBotRestClient) UpdateChannelReturns(result1 discorda.Channel, result2 error) {
+	fake.updateChannelMutex.Lock()
+	defer fake.updateChannelMutex.Unlock()
+	fake.UpdateChannelStub = nil
+	fake.updateChannelReturns = struct {
+		result1 discorda.Channel
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) UpdateChannelReturnsOnCall(i int, result1 discorda.Channel, result2 error) {
+	fake.updateChannelMutex.Lock()
+	defer fake.updateChannelMutex.Unlock()
+	fake.UpdateChannelStub = nil
+	if fake.updateChannelReturnsOnCall == nil {
+		fake.updateChannelReturnsOnCall = make(map[int]struct {
+			result1 discorda.Channel
+			result2 error
+		})
+	}
+	fake.updateChannelReturnsOnCall[i] = struct {
+		result1 discorda.Channel
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) UpdateMessage(arg1 snowflake.ID, arg2 snowflake.ID, arg3 discorda.MessageUpdate, arg4 ...rest.RequestOpt) (*discorda.Message, error) {
+	fake.updateMessageMutex.Lock()
+	ret, specificReturn := fake.updateMessageReturnsOnCall[len(fake.updateMessageArgsForCall)]
+	fake.updateMessageArgsForCall = append(fake.updateMessageArgsForCall, struct {
+		arg1 snowflake.ID
+		arg2 snowflake.ID
+		arg3 discorda.MessageUpdate
+		arg4 []rest.RequestOpt
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UpdateMessageStub
+	fakeReturns := fake.updateMessageReturns
+	fake.recordInvocation("UpdateMessage", []interface{}{arg1, arg2, arg3, arg4})
+	fake.updateMessageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBotRestClient) UpdateMessageCallCount() int {
+	fake.updateMessageMutex.RLock()
+	defer fake.updateMessageMutex.RUnlock()
+	return len(fake.updateMessageArgsForCall)
+}
+
+func (fake *FakeBotRestClient) UpdateMessageCalls(stub func(snowflake.ID, snowflake.ID, discorda.MessageUpdate, ...rest.RequestOpt) (*discorda.Message, error)) {
+	fake.updateMessageMutex.Lock()
+	defer fake.updateMessageMutex.Unlock()
+	fake.UpdateMessageStub = stub
+}
+
+func (fake *FakeBotRestClient) UpdateMessageArgsForCall(i int) (snowflake.ID, snowflake.ID, discorda.MessageUpdate, []rest.RequestOpt) {
+	fake.updateMessageMutex.RLock()
+	defer fake.updateMessageMutex.RUnlock()
+	argsForCall := fake.updateMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBotRestClient) UpdateMessageReturns(result1 *discorda.Message, result2 error) {
+	fake.updateMessageMutex.Lock()
+	defer fake.updateMessageMutex.Unlock()
+	fake.UpdateMessageStub = nil
+	fake.updateMessageReturns = struct {
+		result1 *discorda.Message
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBotRestClient) UpdateMessageReturnsOnCall(i int, result1 *discorda.Message, result2 error) {
+	fake.updateMessageMutex.Lock()
+	defer fake.updateMessageMutex.Unlock()
+	fake.UpdateMessageStub = nil
+	if fake.updateMessageReturnsOnCall == nil {
+		fake.updateMessageReturnsOnCall = make(map[int]struct {
+			result1 *discorda.Message
+			result2 error
+		})
+	}
+	fake.updateMessageReturnsOnCall[i] = struct {
+		result1 *discorda.Message
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBotRestClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.addMemberRoleMutex.RLock()
+	defer fake.addMemberRoleMutex.RUnlock()
+	fake.addThreadMemberMutex.RLock()
+	defer fake.addThreadMemberMutex.RUnlock()
+	fake.createDMChannelMutex.RLock()
+	defer fake.createDMChannelMutex.RUnlock()
+	fake.createGuildScheduledEventMutex.RLock()
+	defer fake.createGuildScheduledEventMutex.RUnlock()
+	fake.createMessageMutex.RLock()
+	defer fake.createMessageMutex.RUnlock()
+	fake.createThreadMutex.RLock()
+	defer fake.createThreadMutex.RUnlock()
+	fake.createThreadFromMessageMutex.RLock()
+	defer fake.createThreadFromMessageMutex.RUnlock()
+	fake.deleteChannelMutex.RLock()
+	defer fake.deleteChannelMutex.RUnlock()
+	fake.deleteGuildScheduledEventMutex.RLock()
+	defer fake.deleteGuildScheduledEventMutex.RUnlock()
+	fake.deleteMessageMutex.RLock()
+	defer fake.deleteMessageMutex.RUnlock()
+	fake.getChannelMutex.RLock()
+	defer fake.getChannelMutex.RUnlock()
+	fake.getChannelPinsMutex.RLock()
+	defer fake.getChannelPinsMutex.RUnlock()
+	fake.getMemberMutex.RLock()
+	defer fake.getMemberMutex.RUnlock()
+	fake.getMembersMutex.RLock()
+	defer fake.getMembersMutex.RUnlock()
+	fake.getRolesMutex.RLock()
+	defer fake.getRolesMutex.RUnlock()
+	fake.pinMessageMutex.RLock()
+	defer fake.pinMessageMutex.RUnlock()
+	fake.removeMemberRoleMutex.RLock()
+	defer fake.removeMemberRoleMutex.RUnlock()
+	fake.unpinMessageMutex.RLock()
+	defer fake.unpinMessageMutex.RUnlock()
+	fake.updateChannelMutex.RLock()
+	defer fake.updateChannelMutex.RUnlock()
+	fake.updateMessageMutex.RLock()
+	defer fake.updateMessageMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
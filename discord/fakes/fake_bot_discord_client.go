@@ -14,6 +14,19 @@ type FakeBotDiscordClient struct {
 	closeArgsForCall []struct {
 		arg1 context.Context
 	}
+	DoctorStub        func(context.Context) (string, bool)
+	doctorMutex       sync.RWMutex
+	doctorArgsForCall []struct {
+		arg1 context.Context
+	}
+	doctorReturns struct {
+		result1 string
+		result2 bool
+	}
+	doctorReturnsOnCall map[int]struct {
+		result1 string
+		result2 bool
+	}
 	OpenGatewayStub        func(context.Context) error
 	openGatewayMutex       sync.RWMutex
 	openGatewayArgsForCall []struct {
@@ -61,6 +74,70 @@ func (fake *FakeBotDiscordClient) CloseArgsForCall(i int) context.Context {
 	return argsForCall.arg1
 }
 
+func (fake *FakeBotDiscordClient) Doctor(arg1 context.Context) (string, bool) {
+	fake.doctorMutex.Lock()
+	ret, specificReturn := fake.doctorReturnsOnCall[len(fake.doctorArgsForCall)]
+	fake.doctorArgsForCall = append(fake.doctorArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.DoctorStub
+	fakeReturns := fake.doctorReturns
+	fake.recordInvocation("Doctor", []interface{}{arg1})
+	fake.doctorMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBotDiscordClient) DoctorCallCount() int {
+	fake.doctorMutex.RLock()
+	defer fake.doctorMutex.RUnlock()
+	return len(fake.doctorArgsForCall)
+}
+
+func (fake *FakeBotDiscordClient) DoctorCalls(stub func(context.Context) (string, bool)) {
+	fake.doctorMutex.Lock()
+	defer fake.doctorMutex.Unlock()
+	fake.DoctorStub = stub
+}
+
+func (fake *FakeBotDiscordClient) DoctorArgsForCall(i int) context.Context {
+	fake.doctorMutex.RLock()
+	defer fake.doctorMutex.RUnlock()
+	argsForCall := fake.doctorArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBotDiscordClient) DoctorReturns(result1 string, result2 bool) {
+	fake.doctorMutex.Lock()
+	defer fake.doctorMutex.Unlock()
+	fake.DoctorStub = nil
+	fake.doctorReturns = struct {
+		result1 string
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeBotDiscordClient) DoctorReturnsOnCall(i int, result1 string, result2 bool) {
+	fake.doctorMutex.Lock()
+	defer fake.doctorMutex.Unlock()
+	fake.DoctorStub = nil
+	if fake.doctorReturnsOnCall == nil {
+		fake.doctorReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 bool
+		})
+	}
+	fake.doctorReturnsOnCall[i] = struct {
+		result1 string
+		result2 bool
+	}{result1, result2}
+}
+
 func (fake *FakeBotDiscordClient) OpenGateway(arg1 context.Context) error {
 	fake.openGatewayMutex.Lock()
 	ret, specificReturn := fake.openGatewayReturnsOnCall[len(fake.openGatewayArgsForCall)]
@@ -125,6 +202,12 @@ func (fake *FakeBotDiscordClient) OpenGatewayReturnsOnCall(i int, result1 error)
 func (fake *FakeBotDiscordClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	fake.doctorMutex.RLock()
+	defer fake.doctorMutex.RUnlock()
+	fake.openGatewayMutex.RLock()
+	defer fake.openGatewayMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,380 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/simgrid"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// identitiesDocument is the state document holding driver identity links.
+const identitiesDocument = "identities.yml"
+
+// Sources of an identity link. Admin links take precedence: a driver cannot
+// self-link over a link an admin has set.
+const (
+	linkSourceSelf  = "self"
+	linkSourceAdmin = "admin"
+)
+
+// identityLink records which Discord user a SimGrid driver is, and who said so.
+type identityLink struct {
+	DiscordID snowflake.ID `yaml:"discord_id"`
+	Source    string       `yaml:"source"`
+	LinkedBy  snowflake.ID `yaml:"linked_by"`
+	LinkedAt  time.Time    `yaml:"linked_at"`
+}
+
+// identities maps SimGrid player IDs (Steam64 IDs) to Discord users. Pending
+// holds self links an admin has yet to confirm.
+type identities struct {
+	Links   map[string]identityLink `yaml:"links"`
+	Pending map[string]identityLink `yaml:"pending,omitempty"`
+}
+
+// maxSuggestionDistance is the largest edit distance between a driver's name
+// and a member's name that is still offered as a fuzzy match.
+const maxSuggestionDistance = 2
+
+// memberSuggestion is a guild member that might be a given driver.
+type memberSuggestion struct {
+	ID       snowflake.ID
+	Name     string
+	Distance int
+}
+
+func (d *DiscordClient) lookupIdentity(steamID string) (identityLink, bool, error) {
+	var ids identities
+	if err := d.drivers.Load(identitiesDocument, &ids); err != nil {
+		return identityLink{}, false, err
+	}
+	link, ok := ids.Links[steamID]
+	return link, ok, nil
+}
+
+// saveIdentity links steamID to link.DiscordID. A self link never replaces a
+// link to a different Discord user; only admins can do that.
+func (d *DiscordClient) saveIdentity(steamID string, link identityLink) error {
+	var ids identities
+	return d.drivers.Update(identitiesDocument, &ids, func() error {
+		if ids.Links == nil {
+			ids.Links = map[string]identityLink{}
+		}
+		existing, ok := ids.Links[steamID]
+		if ok && link.Source == linkSourceSelf && existing.DiscordID != link.DiscordID {
+			return errLinkedToAnother
+		}
+		ids.Links[steamID] = link
+		delete(ids.Pending, steamID)
+		return nil
+	})
+}
+
+var errLinkedToAnother = errors.New("that driver is already linked to another Discord account. Ask an admin to fix it with `!link-driver`")
+
+// savePendingLink records a self link for an admin to confirm with
+// `!link-driver`. Like saveIdentity, it won't stand in for a link to a
+// different Discord user.
+func (d *DiscordClient) savePendingLink(steamID string, link identityLink) error {
+	var ids identities
+	return d.drivers.Update(identitiesDocument, &ids, func() error {
+		if existing, ok := ids.Links[steamID]; ok && existing.DiscordID != link.DiscordID {
+			return errLinkedToAnother
+		}
+		if ids.Pending == nil {
+			ids.Pending = map[string]identityLink{}
+		}
+		ids.Pending[steamID] = link
+		return nil
+	})
+}
+
+// selfLinkMatches reports whether member's username is the driver's SimGrid
+// username, so a self link can be trusted without an admin. Nicknames and
+// global names don't count, nor do near misses: members can set those to
+// anything, including another driver's handle.
+func selfLinkMatches(member discord.Member, driver models.Driver) bool {
+	handle := normalizeName(driver.DiscordHandle)
+	return handle != "" && normalizeName(member.User.Username) == handle
+}
+
+// memberNames returns member's username, global name and nickname, skipping
+// those it doesn't have.
+func memberNames(member discord.Member) []string {
+	names := []string{member.User.Username}
+	if member.User.GlobalName != nil {
+		names = append(names, *member.User.GlobalName)
+	}
+	if member.Nick != nil {
+		names = append(names, *member.Nick)
+	}
+	return names
+}
+
+// findDriver matches a car number, Steam64 ID or SimGrid username against the
+// championship's registered drivers.
+func findDriver(driverLookup models.DriverLookup, arg string) (models.Driver, bool) {
+	if carNumber, err := strconv.Atoi(strings.TrimPrefix(arg, "#")); err == nil {
+		if driver, ok := driverLookup[carNumber]; ok {
+			return driver, true
+		}
+	}
+	steamID := strings.TrimPrefix(arg, "S")
+	for _, driver := range driverLookup {
+		if driver.SteamID != "" && driver.SteamID == steamID {
+			return driver, true
+		}
+	}
+	for _, driver := range driverLookup {
+		if driver.DiscordHandle != "" && normalizeName(driver.DiscordHandle) == normalizeName(arg) {
+			return driver, true
+		}
+	}
+	return models.Driver{}, false
+}
+
+// parseUserMention accepts a Discord user mention (<@123> or <@!123>) or a
+// bare user ID.
+func parseUserMention(arg string) (snowflake.ID, error) {
+	raw := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(arg, "<@"), "!"), ">")
+	id, err := snowflake.Parse(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a Discord user mention", arg)
+	}
+	return id, nil
+}
+
+// runLink lets a driver link their own Discord account to their SimGrid entry.
+// The link takes effect straight away when their Discord username is the
+// entry's SimGrid username; otherwise it waits for an admin to
+// confirm it, so nobody can claim another driver's pings and DMs.
+func (d *DiscordClient) runLink(userID snowflake.ID, args []string, sgClient *simgrid.SimGridClient) (string, string, error) {
+	if len(args) != 1 {
+		return "", "", fmt.Errorf("usage: `!link <car number | SimGrid username | Steam64 ID>`")
+	}
+
+	driverLookup, err := sgClient.BuildDriverLookup(d.snapshotConfig().ChampionshipId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed building driver list: %w", err)
+	}
+	driver, ok := findDriver(driverLookup, args[0])
+	if !ok {
+		return "", "", fmt.Errorf("could not find %q among the drivers registered on SimGrid", args[0])
+	}
+	if driver.SteamID == "" {
+		return "", "", errNoSteamID(driver)
+	}
+
+	members, err := d.loadMembers()
+	if err != nil {
+		return "", "", err
+	}
+	link := identityLink{
+		DiscordID: userID,
+		Source:    linkSourceSelf,
+		LinkedBy:  userID,
+		LinkedAt:  time.Now(),
+	}
+	if member, ok := members.get(userID); !ok || !selfLinkMatches(member, driver) {
+		if err := d.savePendingLink(driver.SteamID, link); err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("Your Discord username isn't #%d %s's SimGrid username `%s`, so an admin needs to confirm the link. They'll see it in `!unlinked`.",
+			driver.CarNumber, driver.Name(), driver.DiscordHandle), "", nil
+	}
+
+	if err := d.saveIdentity(driver.SteamID, link); err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("Thanks! You're now linked to #%d %s.", driver.CarNumber, driver.Name()), "", nil
+}
+
+// runLinkDriver lets an admin link a driver to a Discord user, overriding any
+// existing link.
+func (d *DiscordClient) runLinkDriver(adminID snowflake.ID, args []string, sgClient *simgrid.SimGridClient) (string, string, error) {
+	if len(args) != 2 {
+		return "", "", fmt.Errorf("usage: `!link-driver <car number | SimGrid username | Steam64 ID> <@user>`")
+	}
+	userID, err := parseUserMention(args[1])
+	if err != nil {
+		return "", "", err
+	}
+
+	driverLookup, err := sgClient.BuildDriverLookup(d.snapshotConfig().ChampionshipId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed building driver list: %w", err)
+	}
+	driver, ok := findDriver(driverLookup, args[0])
+	if !ok {
+		return "", "", fmt.Errorf("could not find %q among the drivers registered on SimGrid", args[0])
+	}
+	if driver.SteamID == "" {
+		return "", "", errNoSteamID(driver)
+	}
+
+	err = d.saveIdentity(driver.SteamID, identityLink{
+		DiscordID: userID,
+		Source:    linkSourceAdmin,
+		LinkedBy:  adminID,
+		LinkedAt:  time.Now(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("Linked #%d %s to <@%s>.", driver.CarNumber, driver.Name(), userID), "", nil
+}
+
+// errNoSteamID is returned when linking a driver whose SimGrid entry has no
+// Steam64 ID, since links are stored and looked up by it.
+func errNoSteamID(driver models.Driver) error {
+	return fmt.Errorf("#%d %s has no Steam64 ID on SimGrid, so they can't be linked. Ask them to add one to their SimGrid profile", driver.CarNumber, driver.Name())
+}
+
+// runUnlinked reports every registered driver the bot cannot mention, with
+// fuzzy-matched guild members for an admin to confirm.
+func (d *DiscordClient) runUnlinked(sgClient *simgrid.SimGridClient) (string, string, error) {
+	driverLookup, err := sgClient.BuildDriverLookup(d.snapshotConfig().ChampionshipId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed building driver list: %w", err)
+	}
+
+	drivers := make([]models.Driver, 0, len(driverLookup))
+	for _, driver := range driverLookup {
+		drivers = append(drivers, driver)
+	}
+	sort.Slice(drivers, func(i, j int) bool { return drivers[i].CarNumber < drivers[j].CarNumber })
+
+	report, err := d.unresolvedDriversReport(drivers)
+	if err != nil {
+		return "", "", err
+	}
+	if report == "" {
+		return "Every registered driver is linked to a Discord account.", "", nil
+	}
+	return report, "", nil
+}
+
+// unresolvedDriversReport lists the drivers that getDriverId cannot resolve,
+// along with suggested guild members. It returns an empty string when every
+// driver resolves.
+func (d *DiscordClient) unresolvedDriversReport(drivers []models.Driver) (string, error) {
+	var ids identities
+	if err := d.drivers.Load(identitiesDocument, &ids); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, driver := range drivers {
+		_, err := d.getDriverId(driver)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, DiscordHandleNotFoundError{}) {
+			return "", err
+		}
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "⚠️ **Drivers without a linked Discord account**\n")
+		}
+		fmt.Fprintf(&b, "- #%d %s (SimGrid `%s`)", driver.CarNumber, driver.Name(), driver.DiscordHandle)
+		if pending, ok := ids.Pending[driver.SteamID]; ok && driver.SteamID != "" {
+			fmt.Fprintf(&b, " — <@%s> asked to link themselves\n", pending.DiscordID)
+			continue
+		}
+		suggestions := d.suggestMembers(driver)
+		if len(suggestions) > 0 {
+			names := make([]string, 0, len(suggestions))
+			for _, s := range suggestions {
+				names = append(names, fmt.Sprintf("<@%s> (%q)", s.ID, s.Name))
+			}
+			fmt.Fprintf(&b, " — maybe %s", strings.Join(names, " or "))
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+	if b.Len() > 0 {
+		fmt.Fprintf(&b, "Confirm with `!link-driver <car number> <@user>`, or ask the driver to `!link` themselves.\n")
+	}
+	return b.String(), nil
+}
+
+// suggestMembers returns up to three guild members whose username, global
+// name or nickname is close to the driver's SimGrid username or full name.
+func (d *DiscordClient) suggestMembers(driver models.Driver) []memberSuggestion {
+	members, err := d.loadMembers()
+	if err != nil {
+		return nil
+	}
+	targets := []string{normalizeName(driver.DiscordHandle), normalizeName(driver.Name())}
+
+	var suggestions []memberSuggestion
+	for _, member := range members.all() {
+		names := memberNames(member)
+
+		best := memberSuggestion{ID: member.User.ID, Distance: maxSuggestionDistance + 1}
+		for _, name := range names {
+			for _, target := range targets {
+				if target == "" {
+					continue
+				}
+				distance := nameDistance(normalizeName(name), target)
+				if distance < best.Distance {
+					best.Distance = distance
+					best.Name = name
+				}
+			}
+		}
+		if best.Distance <= maxSuggestionDistance {
+			suggestions = append(suggestions, best)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance != suggestions[j].Distance {
+			return suggestions[i].Distance < suggestions[j].Distance
+		}
+		return suggestions[i].ID < suggestions[j].ID
+	})
+	if len(suggestions) > 3 {
+		suggestions = suggestions[:3]
+	}
+	return suggestions
+}
+
+// nameDistance is the Levenshtein distance between two names, except that a
+// name containing the other (e.g. a nickname "maxverstappen33") counts as a
+// distance of 1.
+func nameDistance(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if len(a) >= 4 && len(b) >= 4 && (strings.Contains(a, b) || strings.Contains(b, a)) {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,242 @@
+package discord
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("findDriver", func() {
+	lookup := models.DriverLookup{
+		7:  {FirstName: "Max", LastName: "Verstappen", CarNumber: 7, DiscordHandle: "max.v", SteamID: "111"},
+		31: {FirstName: "Esteban", LastName: "Ocon", CarNumber: 31, DiscordHandle: "ocon31", SteamID: "222"},
+	}
+
+	It("matches car numbers with or without #", func() {
+		driver, ok := findDriver(lookup, "#31")
+		Expect(ok).To(BeTrue())
+		Expect(driver.SteamID).To(Equal("222"))
+		driver, ok = findDriver(lookup, "7")
+		Expect(ok).To(BeTrue())
+		Expect(driver.SteamID).To(Equal("111"))
+	})
+
+	It("matches Steam64 IDs with or without the S prefix", func() {
+		driver, ok := findDriver(lookup, "S222")
+		Expect(ok).To(BeTrue())
+		Expect(driver.CarNumber).To(Equal(31))
+		driver, ok = findDriver(lookup, "111")
+		Expect(ok).To(BeTrue())
+		Expect(driver.CarNumber).To(Equal(7))
+	})
+
+	It("matches normalized SimGrid usernames", func() {
+		driver, ok := findDriver(lookup, "MaxV")
+		Expect(ok).To(BeTrue())
+		Expect(driver.CarNumber).To(Equal(7))
+	})
+
+	It("reports unknown drivers", func() {
+		_, ok := findDriver(lookup, "nobody")
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("parseUserMention", func() {
+	It("accepts mentions and bare IDs", func() {
+		for _, arg := range []string{"<@123>", "<@!123>", "123"} {
+			id, err := parseUserMention(arg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal(snowflakeID(123)))
+		}
+	})
+
+	It("rejects anything else", func() {
+		_, err := parseUserMention("@someone")
+		Expect(err).To(MatchError(ContainSubstring("not a Discord user mention")))
+	})
+})
+
+var _ = Describe("nameDistance", func() {
+	It("is zero for equal names", func() {
+		Expect(nameDistance("maxv", "maxv")).To(Equal(0))
+	})
+
+	It("counts edits", func() {
+		Expect(nameDistance("maxv", "maxb")).To(Equal(1))
+		Expect(nameDistance("ocon", "ocan31")).To(Equal(3))
+	})
+
+	It("treats containment as a near match", func() {
+		Expect(nameDistance("maxverstappen33", "maxverstappen")).To(Equal(1))
+	})
+})
+
+var _ = Describe("driver identity links", func() {
+	var (
+		client   *DiscordClient
+		stateDir string
+		sgServer *httptest.Server
+		sgClient *simgrid.SimGridClient
+		members  []dgo.Member
+	)
+
+	// exactMember's username is the driver's SimGrid username.
+	exactMember := dgo.Member{User: dgo.User{ID: snowflakeID(504), Username: "Max.Verstappen"}}
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = os.MkdirTemp("", "rookies-bot-identities")
+		Expect(err).NotTo(HaveOccurred())
+
+		client = NewTestDiscordClient(&stubRest{
+			getMembersFn: func(guildID snowflake.ID, limit int, after snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Member, error) {
+				return pagedMembers(members...)(after)
+			},
+		}, snowflakeID(1), &config.Config{BotConfig: config.BotConfig{StateDir: stateDir}}, nil)
+		members = []dgo.Member{
+			{User: dgo.User{ID: snowflakeID(500), Username: "maxverstappen33"}},
+			{User: dgo.User{ID: snowflakeID(501), Username: "lando"}},
+		}
+
+		sgServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(r.URL.Path, "entrylist") {
+				_, _ = w.Write([]byte(`{"entries":[{"drivers":[{"firstName":"Max","lastName":"Verstappen","playerId":"S111"}],"raceNumber":7}]}`))
+			} else {
+				_, _ = w.Write([]byte(`[{"steam64_id":"111","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"}]`))
+			}
+		}))
+		sgClient = simgrid.NewClient("test-token")
+		sgClient.BaseURL = sgServer.URL
+	})
+
+	AfterEach(func() {
+		sgServer.Close()
+		os.RemoveAll(stateDir)
+	})
+
+	It("lets a driver whose username is their SimGrid username link themselves", func() {
+		members = append(members, exactMember)
+		msg, _, err := client.runLink(snowflakeID(504), []string{"7"}, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("#7 Max Verstappen"))
+
+		link, ok, err := client.lookupIdentity("111")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(link.DiscordID).To(Equal(snowflakeID(504)))
+	})
+
+	It("holds near-miss usernames and matching nicknames or global names for an admin", func() {
+		members = append(members, dgo.Member{User: dgo.User{ID: snowflakeID(503), Username: "impostor", GlobalName: strPtr("Max Verstappen")}, Nick: strPtr("max.verstappen")})
+		for _, id := range []uint64{500, 503} {
+			msg, _, err := client.runLink(snowflakeID(id), []string{"7"}, sgClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg).To(HavePrefix("Your Discord username isn't #7 Max Verstappen's SimGrid username"))
+			_, ok, err := client.lookupIdentity("111")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		}
+	})
+
+	It("holds a self link for an admin when the Discord name doesn't match", func() {
+		msg, _, err := client.runLink(snowflakeID(501), []string{"7"}, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal("Your Discord username isn't #7 Max Verstappen's SimGrid username `max.verstappen`, so an admin needs to confirm the link. They'll see it in `!unlinked`."))
+
+		_, ok, err := client.lookupIdentity("111")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+		msg, _, err = client.runUnlinked(sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("#7 Max Verstappen (SimGrid `max.verstappen`) — <@501> asked to link themselves\n"))
+
+		_, _, err = client.runLinkDriver(snowflakeID(9), []string{"7", "<@501>"}, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		var ids identities
+		Expect(client.drivers.Load(identitiesDocument, &ids)).To(Succeed())
+		Expect(ids.Pending).To(BeEmpty())
+		Expect(ids.Links["111"].DiscordID).To(Equal(snowflakeID(501)))
+	})
+
+	It("refuses to link a driver without a Steam64 ID", func() {
+		sgServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(r.URL.Path, "entrylist") {
+				_, _ = w.Write([]byte(`{"entries":[{"drivers":[{"firstName":"Max","lastName":"Verstappen","playerId":"S"}],"raceNumber":7}]}`))
+			} else {
+				_, _ = w.Write([]byte(`[{"steam64_id":"","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"}]`))
+			}
+		})
+
+		_, _, err := client.runLink(snowflakeID(500), []string{"7"}, sgClient)
+		Expect(err).To(MatchError(ContainSubstring("#7 Max Verstappen has no Steam64 ID on SimGrid, so they can't be linked")))
+		_, _, err = client.runLinkDriver(snowflakeID(9), []string{"7", "<@500>"}, sgClient)
+		Expect(err).To(MatchError(ContainSubstring("has no Steam64 ID")))
+	})
+
+	It("prints usage without exactly one argument", func() {
+		_, _, err := client.runLink(snowflakeID(500), nil, sgClient)
+		Expect(err).To(MatchError(ContainSubstring("usage")))
+	})
+
+	It("reports drivers that are not registered", func() {
+		_, _, err := client.runLink(snowflakeID(500), []string{"99"}, sgClient)
+		Expect(err).To(MatchError(ContainSubstring("could not find")))
+	})
+
+	It("does not let a driver self-link over an admin link", func() {
+		_, _, err := client.runLinkDriver(snowflakeID(9), []string{"7", "<@501>"}, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _, err = client.runLink(snowflakeID(500), []string{"7"}, sgClient)
+		Expect(err).To(MatchError(ContainSubstring("already linked")))
+
+		link, ok, err := client.lookupIdentity("111")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(link.DiscordID).To(Equal(snowflakeID(501)))
+		Expect(link.Source).To(Equal(linkSourceAdmin))
+		Expect(link.LinkedBy).To(Equal(snowflakeID(9)))
+	})
+
+	It("lets admins override existing links", func() {
+		Expect(client.saveIdentity("111", identityLink{DiscordID: snowflakeID(500), Source: linkSourceSelf, LinkedAt: time.Now()})).To(Succeed())
+		msg, _, err := client.runLinkDriver(snowflakeID(9), []string{"max.verstappen", "<@!501>"}, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal("Linked #7 Max Verstappen to <@501>."))
+
+		id, err := client.getDriverId(models.Driver{SteamID: "111"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal(snowflakeID(501)))
+	})
+
+	It("reports unlinked drivers with suggested members", func() {
+		msg, _, err := client.runUnlinked(sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("#7 Max Verstappen (SimGrid `max.verstappen`)"))
+		Expect(msg).To(ContainSubstring(`maybe <@500> ("maxverstappen33")`))
+		Expect(msg).NotTo(ContainSubstring("<@501>"))
+	})
+
+	It("reports when every driver is linked", func() {
+		members = append(members, exactMember)
+		_, _, err := client.runLink(snowflakeID(504), []string{"S111"}, sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		msg, _, err := client.runUnlinked(sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal("Every registered driver is linked to a Discord account."))
+	})
+})
@@ -19,12 +19,25 @@ type BotRestClient interface {
 	PinMessage(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error
 	GetChannel(channelID snowflake.ID, opts ...rest.RequestOpt) (dgo.Channel, error)
 	GetRoles(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error)
+	GetMember(guildID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.Member, error)
 	GetMembers(guildID snowflake.ID, limit int, after snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Member, error)
 	CreateGuildScheduledEvent(guildID snowflake.ID, guildScheduledEventCreate dgo.GuildScheduledEventCreate, opts ...rest.RequestOpt) (*dgo.GuildScheduledEvent, error)
+	CreateDMChannel(userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.DMChannel, error)
+	CreateThreadFromMessage(channelID snowflake.ID, messageID snowflake.ID, threadCreateFromMessage dgo.ThreadCreateFromMessage, opts ...rest.RequestOpt) (*dgo.GuildThread, error)
+	CreateThread(channelID snowflake.ID, threadCreate dgo.ThreadCreate, opts ...rest.RequestOpt) (*dgo.GuildThread, error)
+	AddThreadMember(threadID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) error
+	UpdateMessage(channelID snowflake.ID, messageID snowflake.ID, messageUpdate dgo.MessageUpdate, opts ...rest.RequestOpt) (*dgo.Message, error)
+	UpdateChannel(channelID snowflake.ID, channelUpdate dgo.ChannelUpdate, opts ...rest.RequestOpt) (dgo.Channel, error)
+	DeleteChannel(channelID snowflake.ID, opts ...rest.RequestOpt) error
+	DeleteMessage(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error
+	DeleteGuildScheduledEvent(guildID snowflake.ID, guildScheduledEventID snowflake.ID, opts ...rest.RequestOpt) error
+	AddMemberRole(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) error
+	RemoveMemberRole(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) error
 }
 
 //counterfeiter:generate . BotDiscordClient
 type BotDiscordClient interface {
 	OpenGateway(ctx context.Context) error
 	Close(ctx context.Context)
+	Doctor(ctx context.Context) (string, bool)
 }
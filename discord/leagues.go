@@ -0,0 +1,124 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/state"
+)
+
+// leagueFlag picks the league a command acts on, overriding the channel it
+// was sent in, e.g. "!race-setup --league gt4".
+const leagueFlag = "--league"
+
+// newLeagueClient returns a client for one league's config, sharing d's
+// Discord and Google connections, guild member caches and driver state.
+// Leagues in the same guild share its member cache.
+func (d *DiscordClient) newLeagueClient(conf config.BotConfig) (*DiscordClient, error) {
+	league := &DiscordClient{
+		conf:          &config.Config{BotConfig: conf},
+		botClient:     d.botClient,
+		rest:          d.rest,
+		applicationID: d.applicationID,
+		gcloud:        d.gcloud,
+		connectGCloud: d.connectGCloud,
+		configPath:    d.configPath,
+		messages:      d.messages,
+		members:       d.members,
+		state:         state.NewStore(conf.StateDir),
+		drivers:       d.drivers,
+		ctx:           d.ctx,
+		cancel:        d.cancel,
+		commands:      d.commands,
+		running:       newLeagueLock(),
+	}
+	if conf.Jobs != (config.Jobs{}) {
+		var err error
+		league.scheduler, err = league.newScheduler(conf)
+		if err != nil {
+			return nil, fmt.Errorf("league %s: %w", conf.League, err)
+		}
+	}
+	return league, nil
+}
+
+// leagueClients returns the client for every league, or d itself for a bot
+// without leagues.
+func (d *DiscordClient) leagueClients() []*DiscordClient {
+	if len(d.leagues) == 0 {
+		return []*DiscordClient{d}
+	}
+	return d.leagues
+}
+
+// resolveLeague finds the league a command acts on and returns the command's
+// args without any --league option. An explicit --league wins; otherwise the
+// league whose announcement, admin or briefing channel the command was sent
+// in, or the only league if there is just one.
+func (d *DiscordClient) resolveLeague(channelID snowflake.ID, args []string) (*DiscordClient, []string, error) {
+	name, args, err := parseLeagueFlag(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(d.leagues) == 0 {
+		if name != "" {
+			return nil, nil, fmt.Errorf("no leagues are configured, so `%s` can't be used", leagueFlag)
+		}
+		return d, args, nil
+	}
+
+	if name != "" {
+		for _, league := range d.leagues {
+			if strings.EqualFold(league.snapshotConfig().League, name) {
+				return league, args, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("unknown league %q. Leagues: %s", name, d.leagueNames())
+	}
+
+	for _, league := range d.leagues {
+		conf := league.snapshotConfig()
+		for _, channel := range []snowflake.ID{conf.DiscordChannelId, conf.DiscordAdminChannelId, conf.DiscordBriefingChannelId} {
+			if channel != 0 && channel == channelID {
+				return league, args, nil
+			}
+		}
+	}
+	if len(d.leagues) == 1 {
+		return d.leagues[0], args, nil
+	}
+	return nil, nil, fmt.Errorf("which league? Send the command in a league's channel or add `%s <name>`. Leagues: %s", leagueFlag, d.leagueNames())
+}
+
+// parseLeagueFlag removes "--league <name>" or "--league=<name>" from args,
+// returning the name.
+func parseLeagueFlag(args []string) (string, []string, error) {
+	var name string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == leagueFlag:
+			if i+1 == len(args) {
+				return "", nil, fmt.Errorf("`%s` needs a league name", leagueFlag)
+			}
+			name = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], leagueFlag+"="):
+			name = strings.TrimPrefix(args[i], leagueFlag+"=")
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return name, rest, nil
+}
+
+func (d *DiscordClient) leagueNames() string {
+	names := make([]string, 0, len(d.leagues))
+	for _, league := range d.leagues {
+		names = append(names, fmt.Sprintf("`%s`", league.snapshotConfig().League))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,189 @@
+package discord
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("leagues", func() {
+	var (
+		client   *DiscordClient
+		stateDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = os.MkdirTemp("", "rookies-bot-leagues")
+		Expect(err).NotTo(HaveOccurred())
+
+		conf := &config.Config{BotConfig: config.BotConfig{
+			Season:   "2026 Fall",
+			StateDir: stateDir,
+			Leagues: []config.League{
+				{Name: "gt4", ChampionshipId: "100", DiscordChannelId: snowflakeID(10), DiscordAdminChannelId: snowflakeID(11)},
+				{Name: "gt3", ChampionshipId: "200", DiscordChannelId: snowflakeID(20), DiscordBriefingChannelId: snowflakeID(21)},
+			},
+		}}
+		client = NewTestDiscordClient(&stubRest{}, snowflakeID(1), conf, nil)
+		for _, leagueConf := range conf.LeagueConfigs() {
+			league, err := client.newLeagueClient(leagueConf)
+			Expect(err).NotTo(HaveOccurred())
+			client.leagues = append(client.leagues, league)
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
+	})
+
+	resolve := func(channel uint64, args ...string) (string, []string) {
+		league, rest, err := client.resolveLeague(snowflakeID(channel), args)
+		Expect(err).NotTo(HaveOccurred())
+		return league.snapshotConfig().League, rest
+	}
+
+	It("resolves the league from the channel a command is sent in", func() {
+		name, args := resolve(10, "4")
+		Expect(name).To(Equal("gt4"))
+		Expect(args).To(Equal([]string{"4"}))
+		name, _ = resolve(11)
+		Expect(name).To(Equal("gt4"))
+		name, _ = resolve(21)
+		Expect(name).To(Equal("gt3"))
+	})
+
+	It("only answers admin commands in DMs and the leagues' admin channels", func() {
+		guild := snowflakeID(5)
+		Expect(client.inAdminChannel(nil, snowflakeID(99))).To(BeTrue())
+		Expect(client.inAdminChannel(&guild, snowflakeID(11))).To(BeTrue())
+		Expect(client.inAdminChannel(&guild, snowflakeID(10))).To(BeFalse())
+		Expect(client.inAdminChannel(&guild, snowflakeID(99))).To(BeFalse())
+		Expect(client.inAdminChannel(&guild, snowflakeID(0))).To(BeFalse())
+	})
+
+	It("answers admin commands in the channels of a league without an admin channel", func() {
+		guild := snowflakeID(5)
+		Expect(client.inAdminChannel(&guild, snowflakeID(20))).To(BeTrue())
+		Expect(client.inAdminChannel(&guild, snowflakeID(21))).To(BeTrue())
+		name, _ := resolve(21)
+		Expect(name).To(Equal("gt3"))
+
+		Expect(client.adminChannelHint("!jobs")).To(Equal("`!jobs` is only answered in DMs and <#11>, <#20>, <#21>."))
+	})
+
+	It("answers a single league's admin commands in its announcement channel when no admin channel is set", func() {
+		single := NewTestDiscordClient(&stubRest{}, snowflakeID(1), &config.Config{BotConfig: config.BotConfig{
+			Season:           "2026 Fall",
+			StateDir:         stateDir,
+			DiscordChannelId: snowflakeID(30),
+		}}, nil)
+		guild := snowflakeID(5)
+		Expect(single.inAdminChannel(&guild, snowflakeID(30))).To(BeTrue())
+		Expect(single.inAdminChannel(&guild, snowflakeID(31))).To(BeFalse())
+		Expect(single.adminChannelHint("!status")).To(Equal("`!status` is only answered in DMs and <#30>."))
+
+		single.conf.DiscordChannelId = 0
+		Expect(single.adminChannelHint("!status")).To(Equal("`!status` is only answered in DMs."))
+	})
+
+	It("prefers an explicit --league and strips it from the args", func() {
+		name, args := resolve(10, "cancel", "--league", "GT3", "4")
+		Expect(name).To(Equal("gt3"))
+		Expect(args).To(Equal([]string{"cancel", "4"}))
+
+		name, args = resolve(99, "--league=gt4")
+		Expect(name).To(Equal("gt4"))
+		Expect(args).To(BeEmpty())
+	})
+
+	It("asks which league when it can't tell", func() {
+		_, _, err := client.resolveLeague(snowflakeID(99), nil)
+		Expect(err).To(MatchError(ContainSubstring("which league? Send the command in a league's channel or add `--league <name>`. Leagues: `gt4`, `gt3`")))
+
+		_, _, err = client.resolveLeague(snowflakeID(10), []string{"--league", "gt2"})
+		Expect(err).To(MatchError(ContainSubstring(`unknown league "gt2"`)))
+
+		_, _, err = client.resolveLeague(snowflakeID(10), []string{"--league"})
+		Expect(err).To(MatchError(ContainSubstring("needs a league name")))
+	})
+
+	It("uses the only league, or the bot itself without leagues", func() {
+		client.leagues = client.leagues[:1]
+		name, _ := resolve(99)
+		Expect(name).To(Equal("gt4"))
+
+		client.leagues = nil
+		league, _, err := client.resolveLeague(snowflakeID(99), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(league).To(BeIdenticalTo(client))
+		_, _, err = client.resolveLeague(snowflakeID(99), []string{"--league", "gt4"})
+		Expect(err).To(MatchError(ContainSubstring("no leagues are configured")))
+	})
+
+	It("keeps round state per league and driver links across leagues", func() {
+		gt4, gt3 := client.leagues[0], client.leagues[1]
+		Expect(gt4.snapshotConfig().ChampionshipId).To(Equal("100"))
+		Expect(gt3.snapshotConfig().ChampionshipId).To(Equal("200"))
+
+		Expect(gt4.saveCurrentRound(&config.RoundConfig{NextRound: config.Round{Number: 4, Track: "Monza"}})).To(Succeed())
+		Expect(filepath.Join(stateDir, "leagues", "gt4", currentRoundDocument)).To(BeAnExistingFile())
+		_, err := gt3.loadCurrentRound()
+		Expect(err).To(MatchError(ContainSubstring("no round config is stored yet")))
+
+		Expect(gt4.saveIdentity("111", identityLink{DiscordID: snowflakeID(42), Source: linkSourceAdmin, LinkedAt: time.Now()})).To(Succeed())
+		link, ok, err := gt3.lookupIdentity("111")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(link.DiscordID).To(Equal(snowflakeID(42)))
+		Expect(filepath.Join(stateDir, identitiesDocument)).To(BeAnExistingFile())
+	})
+
+	It("matches each league's drivers against its own guild's members", func() {
+		stub := &stubRest{
+			getChannelFn: func(channelID snowflake.ID, opts ...rest.RequestOpt) (dgo.Channel, error) {
+				guild := snowflakeID(1000)
+				if channelID == snowflakeID(20) {
+					guild = snowflakeID(2000)
+				}
+				return guildTextChannel(channelID, guild, "[]"), nil
+			},
+			getMembersFn: func(guildID snowflake.ID, limit int, after snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Member, error) {
+				if after != 0 {
+					return nil, nil
+				}
+				return []dgo.Member{{User: dgo.User{ID: guildID + 1, Username: "maxv"}}}, nil
+			},
+		}
+		for _, league := range client.leagues {
+			league.rest = stub
+		}
+
+		id, err := client.leagues[0].getDriverId(models.Driver{DiscordHandle: "maxv"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal(snowflakeID(1001)))
+		id, err = client.leagues[1].getDriverId(models.Driver{DiscordHandle: "maxv"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal(snowflakeID(2001)))
+	})
+
+	It("gives each league with jobs its own scheduler", func() {
+		conf := client.conf.BotConfig
+		conf.Leagues[1].Jobs = &config.Jobs{RaceSetup: "0 12 * * 1"}
+		configs := conf.LeagueConfigs()
+		gt4, err := client.newLeagueClient(configs[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gt4.scheduler).To(BeNil())
+		gt3, err := client.newLeagueClient(configs[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gt3.scheduler).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,228 @@
+package discord
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// memberCache indexes the guild's members by username, global name and
+// nickname. Names can be shared, so each maps to every member using it. It is seeded once from the REST member list and then kept in sync
+// by the GuildMemberJoin/Update/Leave gateway events, so drivers who join
+// after startup can be mentioned without a restart.
+type memberCache struct {
+	mu      sync.RWMutex
+	loaded  bool
+	guildID snowflake.ID
+	members map[snowflake.ID]discord.Member
+
+	byUsername   nameIndex
+	byGlobalName nameIndex
+	byNickname   nameIndex
+}
+
+func newMemberCache() *memberCache {
+	return &memberCache{
+		members:      map[snowflake.ID]discord.Member{},
+		byUsername:   nameIndex{},
+		byGlobalName: nameIndex{},
+		byNickname:   nameIndex{},
+	}
+}
+
+// nameIndex maps a normalized name to the IDs of the members using it.
+type nameIndex map[string][]snowflake.ID
+
+func (n nameIndex) add(name string, id snowflake.ID) {
+	key := normalizeName(name)
+	if !slices.Contains(n[key], id) {
+		n[key] = append(n[key], id)
+	}
+}
+
+func (n nameIndex) remove(id snowflake.ID) {
+	for key, ids := range n {
+		ids = slices.DeleteFunc(ids, func(indexed snowflake.ID) bool { return indexed == id })
+		if len(ids) == 0 {
+			delete(n, key)
+		} else {
+			n[key] = ids
+		}
+	}
+}
+
+// normalizeName lowercases a name and strips dots and spaces, so that a
+// SimGrid username like "max.verstappen" matches "Max Verstappen".
+func normalizeName(name string) string {
+	return strings.NewReplacer(".", "", " ", "").Replace(strings.ToLower(name))
+}
+
+// load seeds the cache by paging through every guild member. It is a no-op
+// once the cache has been loaded.
+func (c *memberCache) load(guildID snowflake.ID, fetch func(after snowflake.ID) ([]discord.Member, error)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loaded {
+		return nil
+	}
+
+	var lastUser snowflake.ID
+	for {
+		members, err := fetch(lastUser)
+		if err != nil {
+			return err
+		}
+		if len(members) == 0 {
+			break
+		}
+		for _, member := range members {
+			c.put(member)
+		}
+		lastUser = members[len(members)-1].User.ID
+	}
+	c.guildID = guildID
+	c.loaded = true
+	return nil
+}
+
+// lookup finds a member by name, preferring a username match over a global
+// name match over a nickname match. A name several members share at the
+// first level it matches is ambiguous and finds nobody; the driver then needs
+// linking by an admin rather than guessing who was meant.
+func (c *memberCache) lookup(name string) (snowflake.ID, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key := normalizeName(name)
+	for _, index := range []nameIndex{c.byUsername, c.byGlobalName, c.byNickname} {
+		switch ids := index[key]; len(ids) {
+		case 0:
+			continue
+		case 1:
+			return ids[0], true
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// upsert adds or refreshes a member in response to a gateway event. Events
+// for other guilds, or that arrive before the cache is seeded, are ignored;
+// the initial load picks up the current state anyway.
+func (c *memberCache) upsert(guildID snowflake.ID, member discord.Member) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded || guildID != c.guildID {
+		return
+	}
+	c.remove(member.User.ID)
+	c.put(member)
+}
+
+// delete drops a member that has left the guild.
+func (c *memberCache) delete(guildID, userID snowflake.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded || guildID != c.guildID {
+		return
+	}
+	c.remove(userID)
+}
+
+// put indexes member. Callers must hold mu.
+func (c *memberCache) put(member discord.Member) {
+	id := member.User.ID
+	c.members[id] = member
+	c.byUsername.add(member.User.Username, id)
+	if member.User.GlobalName != nil && *member.User.GlobalName != "" {
+		c.byGlobalName.add(*member.User.GlobalName, id)
+	}
+	if member.Nick != nil && *member.Nick != "" {
+		c.byNickname.add(*member.Nick, id)
+	}
+}
+
+// remove unindexes the member with the given ID. Callers must hold mu.
+func (c *memberCache) remove(id snowflake.ID) {
+	if _, ok := c.members[id]; !ok {
+		return
+	}
+	delete(c.members, id)
+	for _, index := range []nameIndex{c.byUsername, c.byGlobalName, c.byNickname} {
+		index.remove(id)
+	}
+}
+
+// all returns a snapshot of every cached member.
+func (c *memberCache) all() []discord.Member {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	members := make([]discord.Member, 0, len(c.members))
+	for _, member := range c.members {
+		members = append(members, member)
+	}
+	return members
+}
+
+// get returns the cached member with the given ID.
+func (c *memberCache) get(id snowflake.ID) (discord.Member, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	member, ok := c.members[id]
+	return member, ok
+}
+
+// withRole returns the cached members holding roleID, ordered by ID.
+func (c *memberCache) withRole(roleID snowflake.ID) []discord.Member {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var members []discord.Member
+	for _, member := range c.members {
+		if slices.Contains(member.RoleIDs, roleID) {
+			members = append(members, member)
+		}
+	}
+	slices.SortFunc(members, func(a, b discord.Member) int { return cmp.Compare(a.User.ID, b.User.ID) })
+	return members
+}
+
+// guildMembers holds a member cache per guild, so leagues in different
+// guilds each match drivers against their own guild's members.
+type guildMembers struct {
+	mu     sync.Mutex
+	caches map[snowflake.ID]*memberCache
+}
+
+func newGuildMembers() *guildMembers {
+	return &guildMembers{caches: map[snowflake.ID]*memberCache{}}
+}
+
+// forGuild returns guildID's member cache, creating an empty one the first
+// time it's asked for.
+func (g *guildMembers) forGuild(guildID snowflake.ID) *memberCache {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	cache, ok := g.caches[guildID]
+	if !ok {
+		cache = newMemberCache()
+		g.caches[guildID] = cache
+	}
+	return cache
+}
+
+func (d *DiscordClient) onGuildMemberJoin(event *events.GuildMemberJoin) {
+	d.members.forGuild(event.GuildID).upsert(event.GuildID, event.Member)
+}
+
+func (d *DiscordClient) onGuildMemberUpdate(event *events.GuildMemberUpdate) {
+	d.members.forGuild(event.GuildID).upsert(event.GuildID, event.Member)
+}
+
+func (d *DiscordClient) onGuildMemberLeave(event *events.GuildMemberLeave) {
+	d.members.forGuild(event.GuildID).delete(event.GuildID, event.User.ID)
+}
@@ -0,0 +1,198 @@
+package discord
+
+import (
+	"fmt"
+	"sync"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// pagedMembers returns a fetch func serving members as a single page followed
+// by an empty page.
+func pagedMembers(members ...dgo.Member) func(after snowflake.ID) ([]dgo.Member, error) {
+	return func(after snowflake.ID) ([]dgo.Member, error) {
+		if after == 0 {
+			return members, nil
+		}
+		return nil, nil
+	}
+}
+
+var _ = Describe("memberCache", func() {
+	var cache *memberCache
+
+	BeforeEach(func() {
+		cache = newMemberCache()
+		Expect(cache.load(snowflakeID(777), pagedMembers(
+			dgo.Member{User: dgo.User{ID: snowflakeID(1), Username: "max.v"}},
+			dgo.Member{User: dgo.User{ID: snowflakeID(2), Username: "lando4", GlobalName: strPtr("Lando Norris")}},
+			dgo.Member{User: dgo.User{ID: snowflakeID(3), Username: "ocon31"}, Nick: strPtr("Esteban")},
+		))).To(Succeed())
+	})
+
+	It("finds members by normalized username", func() {
+		id, ok := cache.lookup("MaxV")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(snowflakeID(1)))
+	})
+
+	It("finds members by global name", func() {
+		id, ok := cache.lookup("landonorris")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(snowflakeID(2)))
+	})
+
+	It("finds members by nickname", func() {
+		id, ok := cache.lookup("esteban")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(snowflakeID(3)))
+	})
+
+	It("prefers a username match over another member's nickname", func() {
+		cache.upsert(snowflakeID(777), dgo.Member{User: dgo.User{ID: snowflakeID(4), Username: "someone"}, Nick: strPtr("max.v")})
+		id, ok := cache.lookup("maxv")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(snowflakeID(1)))
+	})
+
+	It("treats a nickname shared by several members as ambiguous", func() {
+		cache.upsert(snowflakeID(777), dgo.Member{User: dgo.User{ID: snowflakeID(4), Username: "imposter"}, Nick: strPtr("Esteban")})
+		_, ok := cache.lookup("esteban")
+		Expect(ok).To(BeFalse())
+
+		cache.delete(snowflakeID(777), snowflakeID(4))
+		id, ok := cache.lookup("esteban")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(snowflakeID(3)))
+	})
+
+	It("keeps another member's entry when a member sharing its name leaves", func() {
+		cache.upsert(snowflakeID(777), dgo.Member{User: dgo.User{ID: snowflakeID(4), Username: "lando.fan", GlobalName: strPtr("Lando Norris")}})
+		cache.delete(snowflakeID(777), snowflakeID(2))
+		id, ok := cache.lookup("landonorris")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(snowflakeID(4)))
+	})
+
+	It("only loads once", func() {
+		calls := 0
+		err := cache.load(snowflakeID(777), func(after snowflake.ID) ([]dgo.Member, error) {
+			calls++
+			return nil, nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal(0))
+	})
+
+	It("stays unloaded when the fetch fails so the next call retries", func() {
+		fresh := newMemberCache()
+		err := fresh.load(snowflakeID(777), func(after snowflake.ID) ([]dgo.Member, error) {
+			return nil, fmt.Errorf("boom")
+		})
+		Expect(err).To(MatchError("boom"))
+		Expect(fresh.load(snowflakeID(777), pagedMembers())).To(Succeed())
+	})
+
+	It("adds members that join after the initial load", func() {
+		cache.upsert(snowflakeID(777), dgo.Member{User: dgo.User{ID: snowflakeID(5), Username: "newbie"}})
+		id, ok := cache.lookup("newbie")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(snowflakeID(5)))
+	})
+
+	It("reindexes a member whose nickname changes", func() {
+		cache.upsert(snowflakeID(777), dgo.Member{User: dgo.User{ID: snowflakeID(3), Username: "ocon31"}, Nick: strPtr("Estie")})
+		_, ok := cache.lookup("esteban")
+		Expect(ok).To(BeFalse())
+		id, ok := cache.lookup("estie")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(snowflakeID(3)))
+	})
+
+	It("forgets members that leave", func() {
+		cache.delete(snowflakeID(777), snowflakeID(1))
+		_, ok := cache.lookup("maxv")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("ignores events for other guilds", func() {
+		cache.upsert(snowflakeID(888), dgo.Member{User: dgo.User{ID: snowflakeID(6), Username: "elsewhere"}})
+		cache.delete(snowflakeID(888), snowflakeID(1))
+		_, ok := cache.lookup("elsewhere")
+		Expect(ok).To(BeFalse())
+		_, ok = cache.lookup("maxv")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("ignores events that arrive before the initial load", func() {
+		fresh := newMemberCache()
+		fresh.upsert(snowflakeID(777), dgo.Member{User: dgo.User{ID: snowflakeID(6), Username: "early"}})
+		_, ok := fresh.lookup("early")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("is safe for concurrent event handlers and lookups", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				cache.upsert(snowflakeID(777), dgo.Member{User: dgo.User{ID: snowflake.ID(100 + i), Username: fmt.Sprintf("driver%d", i)}})
+			}(i)
+			go func() {
+				defer wg.Done()
+				_, _ = cache.lookup("maxv")
+			}()
+		}
+		wg.Wait()
+		_, ok := cache.lookup("driver49")
+		Expect(ok).To(BeTrue())
+	})
+})
+
+var _ = Describe("guild member event handlers", func() {
+	var client *DiscordClient
+
+	BeforeEach(func() {
+		client = NewTestDiscordClient(&stubRest{}, snowflakeID(1), nil, nil)
+		Expect(client.members.forGuild(snowflakeID(777)).load(snowflakeID(777), pagedMembers(
+			dgo.Member{User: dgo.User{ID: snowflakeID(1), Username: "maxv"}},
+		))).To(Succeed())
+	})
+
+	It("indexes joining members", func() {
+		client.onGuildMemberJoin(&events.GuildMemberJoin{GenericGuildMember: &events.GenericGuildMember{
+			GuildID: snowflakeID(777),
+			Member:  dgo.Member{User: dgo.User{ID: snowflakeID(2), Username: "joiner"}},
+		}})
+		_, ok := client.members.forGuild(snowflakeID(777)).lookup("joiner")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("reindexes updated members", func() {
+		client.onGuildMemberUpdate(&events.GuildMemberUpdate{GenericGuildMember: &events.GenericGuildMember{
+			GuildID: snowflakeID(777),
+			Member:  dgo.Member{User: dgo.User{ID: snowflakeID(1), Username: "maxv"}, Nick: strPtr("Super Max")},
+		}})
+		id, ok := client.members.forGuild(snowflakeID(777)).lookup("supermax")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(snowflakeID(1)))
+	})
+
+	It("drops members that leave", func() {
+		client.onGuildMemberLeave(&events.GuildMemberLeave{
+			GuildID: snowflakeID(777),
+			User:    dgo.User{ID: snowflakeID(1), Username: "maxv"},
+		})
+		_, ok := client.members.forGuild(snowflakeID(777)).lookup("maxv")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,172 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+)
+
+// dmPreferencesDocument is the state document recording which drivers have
+// opted out of penalty DMs.
+const dmPreferencesDocument = "dm_preferences.yml"
+
+// dmPreferences records when each opted-out driver asked to stop receiving
+// penalty DMs, keyed by Discord user ID.
+type dmPreferences struct {
+	OptedOut map[string]time.Time `yaml:"opted_out"`
+}
+
+// driverPenalty is a single penalty a driver serves.
+type driverPenalty struct {
+	Title       string
+	CarriedOver bool
+}
+
+// PenaltyDMData is the data model for penalty_dm.tmpl.
+type PenaltyDMData struct {
+	// Round is the upcoming round where the penalties are served.
+	Round config.Round
+	// PreviousRound is the round whose stewarding produced the penalties.
+	PreviousRound config.Round
+	// Driver is the penalized driver.
+	Driver models.Driver
+	// Penalties lists every penalty the driver serves, in announcement order.
+	Penalties []driverPenalty
+	// TrackerURL links the previous round's penalty tracker.
+	TrackerURL string
+}
+
+// penaltiesByCar groups the penalties each driver serves by car number.
+func penaltiesByCar(penalties *models.Penalties) map[int][]driverPenalty {
+	byCar := map[int][]driverPenalty{}
+	for _, category := range penaltyCategories(penalties) {
+		for _, driver := range category.carriedOver {
+			byCar[driver.CarNumber] = append(byCar[driver.CarNumber], driverPenalty{Title: category.title, CarriedOver: true})
+		}
+		for _, driver := range category.current {
+			byCar[driver.CarNumber] = append(byCar[driver.CarNumber], driverPenalty{Title: category.title})
+		}
+	}
+	return byCar
+}
+
+func (d *DiscordClient) penaltyDMsOptedOut(userID snowflake.ID) (bool, error) {
+	var prefs dmPreferences
+	if err := d.drivers.Load(dmPreferencesDocument, &prefs); err != nil {
+		return false, err
+	}
+	_, ok := prefs.OptedOut[userID.String()]
+	return ok, nil
+}
+
+// runPenaltyDMs lets a driver turn their penalty DMs off or back on.
+func (d *DiscordClient) runPenaltyDMs(userID snowflake.ID, args []string) (string, string, error) {
+	if len(args) == 0 {
+		optedOut, err := d.penaltyDMsOptedOut(userID)
+		if err != nil {
+			return "", "", err
+		}
+		if optedOut {
+			return "Penalty DMs are off for you. Send `!penalty-dms on` to turn them back on.", "", nil
+		}
+		return "Penalty DMs are on for you. Send `!penalty-dms off` to stop them.", "", nil
+	}
+	if len(args) != 1 || (args[0] != "on" && args[0] != "off") {
+		return "", "", fmt.Errorf("usage: `!penalty-dms [on | off]`")
+	}
+
+	var prefs dmPreferences
+	err := d.drivers.Update(dmPreferencesDocument, &prefs, func() error {
+		if prefs.OptedOut == nil {
+			prefs.OptedOut = map[string]time.Time{}
+		}
+		if args[0] == "off" {
+			prefs.OptedOut[userID.String()] = time.Now()
+		} else {
+			delete(prefs.OptedOut, userID.String())
+		}
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if args[0] == "off" {
+		return "Ok, I won't DM you about your penalties anymore. They are still announced in the league channel.", "", nil
+	}
+	return "Ok, I'll DM you when you have penalties to serve.", "", nil
+}
+
+// sendPenaltyDMs messages each penalized driver that the bot can resolve and
+// that has not opted out, listing the penalties they serve. It returns how
+// many DMs were sent and a line for each driver that could not be messaged.
+// Drivers without a Discord account are skipped; they are reported separately
+// by unresolvedDriversReport.
+func (d *DiscordClient) sendPenaltyDMs(penalties *models.Penalties, roundConfig *config.RoundConfig) (int, []string, error) {
+	byCar := penaltiesByCar(penalties)
+	sent := 0
+	var failed []string
+	for _, driver := range penalties.UniqueDrivers() {
+		userID, err := d.getDriverId(driver)
+		if err != nil {
+			if errors.Is(err, DiscordHandleNotFoundError{}) {
+				continue
+			}
+			return sent, failed, err
+		}
+		optedOut, err := d.penaltyDMsOptedOut(userID)
+		if err != nil {
+			return sent, failed, err
+		}
+		if optedOut {
+			continue
+		}
+
+		content, err := d.messages.render(templatePenaltyDM, PenaltyDMData{
+			Round:         roundConfig.NextRound,
+			PreviousRound: roundConfig.PreviousRound,
+			Driver:        driver,
+			Penalties:     byCar[driver.CarNumber],
+			TrackerURL:    roundConfig.PreviousRound.PenaltyTrackerLink,
+		})
+		if err != nil {
+			return sent, failed, err
+		}
+
+		if err := d.sendDM(userID, discord.MessageCreate{Content: content}); err != nil {
+			failed = append(failed, fmt.Sprintf("#%d %s (<@%s>): %s", driver.CarNumber, driver.Name(), userID, err))
+			continue
+		}
+		sent++
+	}
+	return sent, failed, nil
+}
+
+// sendDM opens a DM channel with the user and sends them msg. This fails when
+// the user has DMs from server members turned off.
+func (d *DiscordClient) sendDM(userID snowflake.ID, msg discord.MessageCreate) error {
+	channel, err := d.rest.CreateDMChannel(userID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return fmt.Errorf("no DM channel returned")
+	}
+	_, err = d.rest.CreateMessage(channel.ID(), msg)
+	return err
+}
+
+// penaltyDMReport summarizes sendPenaltyDMs for the admin.
+func penaltyDMReport(sent int, failed []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "I DMed %d penalized driver(s) about their penalties.", sent)
+	if len(failed) > 0 {
+		fmt.Fprintf(&b, "\nI could not DM these drivers (their DMs are probably closed):\n- %s", strings.Join(failed, "\n- "))
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// dmChannel builds a DM channel with the given ID; the ID field is only
+// settable by unmarshalling.
+func dmChannel(id snowflake.ID) *dgo.DMChannel {
+	var channel dgo.DMChannel
+	Expect(json.Unmarshal([]byte(fmt.Sprintf(`{"id":"%s","type":1}`, id)), &channel)).To(Succeed())
+	return &channel
+}
+
+var _ = Describe("penaltiesByCar", func() {
+	It("lists each driver's penalties in announcement order", func() {
+		max := models.Driver{CarNumber: 7}
+		lando := models.Driver{CarNumber: 4}
+		byCar := penaltiesByCar(&models.Penalties{
+			QualiBansR2:            []models.Driver{max},
+			PitStartsR1CarriedOver: []models.Driver{max},
+			PitStartsR2:            []models.Driver{lando},
+		})
+		Expect(byCar[7]).To(Equal([]driverPenalty{
+			{Title: "Pit Starts R1", CarriedOver: true},
+			{Title: "Quali Bans R2"},
+		}))
+		Expect(byCar[4]).To(Equal([]driverPenalty{{Title: "Pit Starts R2"}}))
+	})
+})
+
+var _ = Describe("penalty DMs", func() {
+	var (
+		client      *DiscordClient
+		stub        *stubRest
+		stateDir    string
+		roundConfig *config.RoundConfig
+		penalties   *models.Penalties
+		sent        map[snowflake.ID]string
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = os.MkdirTemp("", "rookies-bot-penalty-dms")
+		Expect(err).NotTo(HaveOccurred())
+
+		sent = map[snowflake.ID]string{}
+		stub = &stubRest{
+			getMembersFn: func(guildID snowflake.ID, limit int, after snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Member, error) {
+				if after != 0 {
+					return nil, nil
+				}
+				return []dgo.Member{
+					{User: dgo.User{ID: snowflakeID(500), Username: "maxv"}},
+					{User: dgo.User{ID: snowflakeID(501), Username: "lando"}},
+				}, nil
+			},
+			createDMChannelFn: func(userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.DMChannel, error) {
+				if userID == snowflakeID(501) {
+					return nil, fmt.Errorf("Cannot send messages to this user")
+				}
+				return dmChannel(userID + 1000), nil
+			},
+			createMessageFn: func(channelID snowflake.ID, messageCreate dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+				sent[channelID] = messageCreate.Content
+				return &dgo.Message{}, nil
+			},
+		}
+		client = NewTestDiscordClient(stub, snowflakeID(1), &config.Config{BotConfig: config.BotConfig{StateDir: stateDir}}, nil)
+
+		roundConfig = &config.RoundConfig{
+			PreviousRound: config.Round{Number: 4, Track: "Spa", PenaltyTrackerLink: "https://tracker"},
+			NextRound:     config.Round{Number: 5, Track: "Monza"},
+		}
+		max := models.Driver{FirstName: "Max", LastName: "Verstappen", CarNumber: 7, DiscordHandle: "maxv"}
+		lando := models.Driver{FirstName: "Lando", LastName: "Norris", CarNumber: 4, DiscordHandle: "lando"}
+		ghost := models.Driver{FirstName: "Nobody", CarNumber: 99, DiscordHandle: "ghost"}
+		penalties = &models.Penalties{
+			QualiBansR1CarriedOver: []models.Driver{max},
+			PitStartsR2:            []models.Driver{max, lando, ghost},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
+	})
+
+	It("DMs each resolved driver their own penalties", func() {
+		count, failed, err := client.sendPenaltyDMs(penalties, roundConfig)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(1))
+		Expect(sent).To(HaveLen(1))
+
+		dm := sent[snowflakeID(1500)]
+		Expect(dm).To(ContainSubstring("Hi Max, stewarding is in from Round 4 - Spa."))
+		Expect(dm).To(ContainSubstring("You (car #7) have the following penalties to serve at Round 5 - Monza:\n- Quali Bans R1 (carried over)\n- Pit Starts R2\n"))
+		Expect(dm).To(ContainSubstring("<https://tracker>"))
+		Expect(failed).To(ConsistOf(ContainSubstring("#4 Lando Norris (<@501>): Cannot send messages to this user")))
+	})
+
+	It("skips drivers who opted out", func() {
+		_, _, err := client.runPenaltyDMs(snowflakeID(500), []string{"off"})
+		Expect(err).NotTo(HaveOccurred())
+
+		count, _, err := client.sendPenaltyDMs(penalties, roundConfig)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(0))
+		Expect(sent).To(BeEmpty())
+	})
+
+	It("resumes DMs when a driver opts back in", func() {
+		_, _, err := client.runPenaltyDMs(snowflakeID(500), []string{"off"})
+		Expect(err).NotTo(HaveOccurred())
+		msg, _, err := client.runPenaltyDMs(snowflakeID(500), []string{"on"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("I'll DM you"))
+
+		count, _, err := client.sendPenaltyDMs(penalties, roundConfig)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(1))
+	})
+
+	It("reports the current preference", func() {
+		msg, _, err := client.runPenaltyDMs(snowflakeID(500), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("Penalty DMs are on"))
+
+		_, _, err = client.runPenaltyDMs(snowflakeID(500), []string{"off"})
+		Expect(err).NotTo(HaveOccurred())
+		msg, _, err = client.runPenaltyDMs(snowflakeID(500), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("Penalty DMs are off"))
+	})
+
+	It("rejects unknown arguments", func() {
+		_, _, err := client.runPenaltyDMs(snowflakeID(500), []string{"maybe"})
+		Expect(err).To(MatchError(ContainSubstring("usage")))
+	})
+})
+
+var _ = Describe("penaltyDMReport", func() {
+	It("counts sent DMs", func() {
+		Expect(penaltyDMReport(3, nil)).To(Equal("I DMed 3 penalized driver(s) about their penalties."))
+	})
+
+	It("lists failed DMs", func() {
+		report := penaltyDMReport(1, []string{"#4 Lando Norris (<@501>): closed"})
+		Expect(report).To(ContainSubstring("I could not DM these drivers"))
+		Expect(report).To(ContainSubstring("\n- #4 Lando Norris (<@501>): closed"))
+	})
+})
@@ -0,0 +1,297 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/events"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+)
+
+// remindersDocument is the state document holding reminders that have not
+// been posted yet.
+const remindersDocument = "reminders.yml"
+
+// reminderPollInterval is how often the reminder loop checks for due
+// reminders.
+const reminderPollInterval = 30 * time.Second
+
+// Events a reminder can be for.
+const (
+	reminderBriefing = "briefing"
+	reminderRace     = "race"
+)
+
+// reminder is one pending reminder post. Everything it shows is captured at
+// race setup, so a reminder posts the same briefing doc and penalties as the
+// race-day announcement.
+type reminder struct {
+	// Key is the roundKey of the round, used to replace or cancel all of a
+	// round's reminders together.
+	Key            string           `yaml:"key"`
+	Round          config.Round     `yaml:"round"`
+	Event          string           `yaml:"event"`
+	EventAt        time.Time        `yaml:"event_at"`
+	SendAt         time.Time        `yaml:"send_at"`
+	BriefingDocURL string           `yaml:"briefing_doc_url,omitempty"`
+	Penalties      []penaltySection `yaml:"penalties,omitempty"`
+}
+
+type reminderQueue struct {
+	Reminders []reminder `yaml:"reminders"`
+}
+
+// ReminderData is the data model for reminder.tmpl.
+type ReminderData struct {
+	Round  config.Round
+	Season string
+	// RoleMention mentions the season role, e.g. "<@&123>".
+	RoleMention string
+	// Event is "briefing" or "race".
+	Event     string
+	EventTime time.Time
+	// EventTimestamp is EventTime as a relative Discord timestamp, e.g.
+	// "<t:1700000000:R>".
+	EventTimestamp string
+	BriefingDocURL string
+	// Penalties lists only the penalty categories somebody is serving.
+	Penalties []penaltySection
+}
+
+// scheduleReminders queues the configured reminders for the round being set
+// up, replacing any queued for it before (e.g. when race setup is re-run
+// after the round is rescheduled). It returns a note for the admin response.
+func (d *DiscordClient) scheduleReminders(roundConfig *config.RoundConfig, briefingTime time.Time, briefingUrl string, penalties *models.Penalties, now time.Time) string {
+	if !d.remindersEnabled() {
+		return ""
+	}
+	conf := d.snapshotConfig()
+
+	raceTime, err := conf.Schedule.RaceTimeFor(briefingTime)
+	if err != nil {
+		return fmt.Sprintf("\n\nCould not schedule reminders: %s", err)
+	}
+	sections, err := d.buildPenaltySections(penalties)
+	if err != nil {
+		return fmt.Sprintf("\n\nCould not schedule reminders: %s", err)
+	}
+	var serving []penaltySection
+	for _, section := range sections {
+		if len(section.Lines) > 0 {
+			serving = append(serving, section)
+		}
+	}
+
+	key := roundKey(conf.Season, roundConfig.NextRound)
+	var queued []reminder
+	for _, event := range []struct {
+		name    string
+		at      time.Time
+		offsets []time.Duration
+	}{
+		{reminderBriefing, briefingTime, conf.Reminders.BeforeBriefing},
+		{reminderRace, raceTime, conf.Reminders.BeforeRace},
+	} {
+		for _, offset := range event.offsets {
+			sendAt := event.at.Add(-offset)
+			if !sendAt.After(now) {
+				continue
+			}
+			queued = append(queued, reminder{
+				Key:            key,
+				Round:          roundConfig.NextRound,
+				Event:          event.name,
+				EventAt:        event.at,
+				SendAt:         sendAt,
+				BriefingDocURL: briefingUrl,
+				Penalties:      serving,
+			})
+		}
+	}
+
+	var queue reminderQueue
+	err = d.state.Update(remindersDocument, &queue, func() error {
+		queue.Reminders = append(withoutRound(queue.Reminders, key), queued...)
+		sort.Slice(queue.Reminders, func(i, j int) bool {
+			return queue.Reminders[i].SendAt.Before(queue.Reminders[j].SendAt)
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Sprintf("\n\nCould not schedule reminders: %s", err)
+	}
+	return fmt.Sprintf("\n\nScheduled %d reminder(s). `!reminders` lists them.", len(queued))
+}
+
+// remindersEnabled reports whether any reminder offsets are configured.
+func (d *DiscordClient) remindersEnabled() bool {
+	reminders := d.snapshotConfig().Reminders
+	return len(reminders.BeforeBriefing) > 0 || len(reminders.BeforeRace) > 0
+}
+
+// cancelReminders drops every queued reminder for the round with the given
+// roundKey, returning how many were dropped.
+func (d *DiscordClient) cancelReminders(key string) (int, error) {
+	var queue reminderQueue
+	var dropped int
+	err := d.state.Update(remindersDocument, &queue, func() error {
+		kept := withoutRound(queue.Reminders, key)
+		dropped = len(queue.Reminders) - len(kept)
+		queue.Reminders = kept
+		return nil
+	})
+	return dropped, err
+}
+
+func withoutRound(reminders []reminder, key string) []reminder {
+	kept := make([]reminder, 0, len(reminders))
+	for _, r := range reminders {
+		if r.Key != key {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// sendDueReminders posts every reminder due by now. A reminder is dequeued
+// before it is posted so a failed post is reported once rather than retried
+// into the middle of the event; reminders whose event already started are
+// dropped unposted.
+func (d *DiscordClient) sendDueReminders(now time.Time) []error {
+	var queue reminderQueue
+	var due []reminder
+	err := d.state.Update(remindersDocument, &queue, func() error {
+		due = nil
+		var pending []reminder
+		for _, r := range queue.Reminders {
+			if r.SendAt.After(now) {
+				pending = append(pending, r)
+			} else {
+				due = append(due, r)
+			}
+		}
+		queue.Reminders = pending
+		return nil
+	})
+	if err != nil {
+		return []error{fmt.Errorf("failed reading reminders: %w", err)}
+	}
+
+	var errs []error
+	for _, r := range due {
+		if !r.EventAt.After(now) {
+			continue
+		}
+		if err := d.sendReminder(r); err != nil {
+			errs = append(errs, fmt.Errorf("failed sending %s reminder for %s: %w", r.Event, r.Round, err))
+		}
+	}
+	return errs
+}
+
+func (d *DiscordClient) sendReminder(r reminder) error {
+	conf := d.snapshotConfig()
+	role, err := d.lookupRole(conf.DiscordRoleName)
+	if err != nil {
+		return err
+	}
+	message, err := d.messages.render(templateReminder, ReminderData{
+		Round:          r.Round,
+		Season:         conf.Season,
+		RoleMention:    fmt.Sprintf("<@&%s>", role.ID),
+		Event:          r.Event,
+		EventTime:      r.EventAt,
+		EventTimestamp: fmt.Sprintf("<t:%d:R>", r.EventAt.Unix()),
+		BriefingDocURL: r.BriefingDocURL,
+		Penalties:      r.Penalties,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = d.SendMessage(buildMessage(message))
+	return err
+}
+
+// watchReminders posts reminders as they come due until ctx is cancelled,
+// reporting failures to the admins.
+func (d *DiscordClient) watchReminders(ctx context.Context) {
+	ticker := time.NewTicker(reminderPollInterval)
+	defer ticker.Stop()
+	for {
+		if d.remindersEnabled() {
+			for _, err := range d.sendDueReminders(time.Now()) {
+				d.reportToAdmins(fmt.Sprintf("⏰ %s", err))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *DiscordClient) reminders(event *events.MessageCreate, args []string) {
+	msg, err := d.runReminders(args)
+	if err != nil {
+		msg = err.Error()
+	}
+	sendBotResponse(event, msg, "")
+}
+
+// runReminders lists the queued reminders, or with "cancel [round number]"
+// cancels them, either all or only those for one round of this season.
+func (d *DiscordClient) runReminders(args []string) (string, error) {
+	if len(args) > 0 && args[0] == "cancel" {
+		return d.runCancelReminders(args[1:])
+	}
+	if len(args) > 0 {
+		return "", fmt.Errorf("usage: `!reminders` or `!reminders cancel [round number]`")
+	}
+
+	var queue reminderQueue
+	if err := d.state.Load(remindersDocument, &queue); err != nil {
+		return "", err
+	}
+	if len(queue.Reminders) == 0 {
+		return "No reminders are scheduled.", nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Scheduled reminders**\n")
+	for _, r := range queue.Reminders {
+		fmt.Fprintf(&b, "- <t:%d:F>: %s for %s (<t:%d:t>)\n", r.SendAt.Unix(), r.Event, r.Round, r.EventAt.Unix())
+	}
+	return b.String(), nil
+}
+
+func (d *DiscordClient) runCancelReminders(args []string) (string, error) {
+	if len(args) == 0 {
+		var queue reminderQueue
+		var dropped int
+		err := d.state.Update(remindersDocument, &queue, func() error {
+			dropped = len(queue.Reminders)
+			queue.Reminders = nil
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Cancelled %d reminder(s).", dropped), nil
+	}
+
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid round number %q", args[0])
+	}
+	round := config.Round{Number: number}
+	dropped, err := d.cancelReminders(roundKey(d.snapshotConfig().Season, round))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Cancelled %d reminder(s) for round %d.", dropped, number), nil
+}
@@ -0,0 +1,191 @@
+package discord
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("race night reminders", func() {
+	var (
+		client    *DiscordClient
+		stub      *stubRest
+		stateDir  string
+		rc        *config.RoundConfig
+		penalties *models.Penalties
+		briefing  time.Time
+		now       time.Time
+		sent      []string
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = os.MkdirTemp("", "rookies-bot-reminders")
+		Expect(err).NotTo(HaveOccurred())
+
+		sent = nil
+		stub = &stubRest{
+			getRolesFn: func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+				return []dgo.Role{{Name: "Rookies", ID: snowflakeID(777)}}, nil
+			},
+			getMembersFn: func(guildID snowflake.ID, limit int, after snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Member, error) {
+				return pagedMembers(dgo.Member{User: dgo.User{ID: snowflakeID(42), Username: "fast.driver"}})(after)
+			},
+			createMessageFn: func(channelID snowflake.ID, messageCreate dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+				sent = append(sent, messageCreate.Content)
+				return &dgo.Message{}, nil
+			},
+		}
+		conf := &config.Config{BotConfig: config.BotConfig{
+			Season:          "2026 Fall",
+			StateDir:        stateDir,
+			DiscordRoleName: "Rookies",
+			Reminders: config.Reminders{
+				BeforeBriefing: []time.Duration{24 * time.Hour, time.Hour},
+				BeforeRace:     []time.Duration{10 * time.Minute},
+			},
+		}}
+		client = NewTestDiscordClient(stub, snowflakeID(1), conf, nil)
+
+		rc = &config.RoundConfig{
+			PreviousRound: config.Round{Number: 3, Track: "Spa"},
+			NextRound:     config.Round{Number: 4, Track: "Monza"},
+		}
+		penalties = &models.Penalties{
+			PitStartsR1: []models.Driver{{CarNumber: 7, FirstName: "Fast", LastName: "Driver", DiscordHandle: "fast.driver"}},
+		}
+		briefing = time.Date(2026, time.March, 9, 23, 30, 0, 0, time.UTC)
+		now = briefing.Add(-48 * time.Hour)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
+	})
+
+	pending := func() []reminder {
+		var queue reminderQueue
+		Expect(client.state.Load(remindersDocument, &queue)).To(Succeed())
+		return queue.Reminders
+	}
+
+	It("queues a reminder for each configured offset", func() {
+		note := client.scheduleReminders(rc, briefing, "https://docs/briefing", penalties, now)
+		Expect(note).To(ContainSubstring("Scheduled 3 reminder(s)"))
+
+		reminders := pending()
+		Expect(reminders).To(HaveLen(3))
+		Expect(reminders[0].SendAt).To(BeTemporally("==", briefing.Add(-24*time.Hour)))
+		Expect(reminders[1].SendAt).To(BeTemporally("==", briefing.Add(-time.Hour)))
+		Expect(reminders[2].Event).To(Equal(reminderRace))
+		Expect(reminders[2].EventAt).To(BeTemporally("==", briefing.Add(config.DefaultRaceDelay)))
+		Expect(reminders[2].SendAt).To(BeTemporally("==", briefing.Add(20*time.Minute)))
+		Expect(reminders[0].Key).To(Equal("2026 Fall/round-4"))
+	})
+
+	It("skips offsets that have already passed", func() {
+		note := client.scheduleReminders(rc, briefing, "https://docs/briefing", penalties, briefing.Add(-2*time.Hour))
+		Expect(note).To(ContainSubstring("Scheduled 2 reminder(s)"))
+	})
+
+	It("replaces the round's reminders when race setup runs again", func() {
+		client.scheduleReminders(rc, briefing, "https://docs/briefing", penalties, now)
+		rescheduled := briefing.Add(24 * time.Hour)
+		client.scheduleReminders(rc, rescheduled, "https://docs/briefing-v2", penalties, now)
+
+		reminders := pending()
+		Expect(reminders).To(HaveLen(3))
+		for _, r := range reminders {
+			Expect(r.BriefingDocURL).To(Equal("https://docs/briefing-v2"))
+		}
+	})
+
+	It("does nothing when no reminders are configured", func() {
+		client.conf.Reminders = config.Reminders{}
+		Expect(client.scheduleReminders(rc, briefing, "https://docs/briefing", penalties, now)).To(BeEmpty())
+		Expect(pending()).To(BeEmpty())
+	})
+
+	It("posts due reminders with the role, briefing doc and penalties being served", func() {
+		client.scheduleReminders(rc, briefing, "https://docs/briefing", penalties, now)
+
+		Expect(client.sendDueReminders(briefing.Add(-time.Hour))).To(BeEmpty())
+		Expect(sent).To(HaveLen(2))
+		Expect(sent[1]).To(ContainSubstring("<@&777>"))
+		Expect(sent[1]).To(ContainSubstring("drivers' briefing for Round 4 - Monza"))
+		Expect(sent[1]).To(ContainSubstring("<t:%d:R>", briefing.Unix()))
+		Expect(sent[1]).To(ContainSubstring("(https://docs/briefing)"))
+		Expect(sent[1]).To(ContainSubstring("**Pit Starts R1**\n- <@42>"))
+		Expect(sent[1]).NotTo(ContainSubstring("Quali Bans"))
+		Expect(pending()).To(HaveLen(1))
+
+		Expect(client.sendDueReminders(briefing.Add(25 * time.Minute))).To(BeEmpty())
+		Expect(sent).To(HaveLen(3))
+		Expect(sent[2]).To(ContainSubstring("Racing at Round 4 - Monza starts"))
+		Expect(pending()).To(BeEmpty())
+	})
+
+	It("drops reminders whose event has already started", func() {
+		client.scheduleReminders(rc, briefing, "https://docs/briefing", penalties, now)
+		Expect(client.sendDueReminders(briefing.Add(time.Hour))).To(BeEmpty())
+		Expect(sent).To(BeEmpty())
+		Expect(pending()).To(BeEmpty())
+	})
+
+	It("reports reminders that fail to post without retrying them", func() {
+		client.scheduleReminders(rc, briefing, "https://docs/briefing", penalties, now)
+		stub.createMessageFn = func(channelID snowflake.ID, messageCreate dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+			return nil, errors.New("discord down")
+		}
+		errs := client.sendDueReminders(briefing.Add(-23 * time.Hour))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0]).To(MatchError(ContainSubstring("briefing reminder for Round 4 - Monza: discord down")))
+		Expect(pending()).To(HaveLen(2))
+	})
+
+	Describe("!reminders", func() {
+		BeforeEach(func() {
+			client.scheduleReminders(rc, briefing, "https://docs/briefing", penalties, now)
+		})
+
+		It("lists queued reminders", func() {
+			msg, err := client.runReminders(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg).To(ContainSubstring("briefing for Round 4 - Monza"))
+			Expect(msg).To(ContainSubstring("race for Round 4 - Monza"))
+		})
+
+		It("cancels one round's reminders", func() {
+			msg, err := client.runReminders([]string{"cancel", "5"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg).To(Equal("Cancelled 0 reminder(s) for round 5."))
+
+			msg, err = client.runReminders([]string{"cancel", "4"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg).To(Equal("Cancelled 3 reminder(s) for round 4."))
+			Expect(pending()).To(BeEmpty())
+		})
+
+		It("cancels every reminder", func() {
+			msg, err := client.runReminders([]string{"cancel"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg).To(Equal("Cancelled 3 reminder(s)."))
+
+			msg, err = client.runReminders(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg).To(Equal("No reminders are scheduled."))
+		})
+
+		It("rejects unknown arguments", func() {
+			_, err := client.runReminders([]string{"later"})
+			Expect(err).To(MatchError(ContainSubstring("usage")))
+		})
+	})
+})
@@ -0,0 +1,159 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/simgrid"
+)
+
+// removeWithdrawnFlag makes `!sync-roles` also take the season role from
+// members who are no longer registered.
+const removeWithdrawnFlag = "--remove-withdrawn"
+
+func (d *DiscordClient) syncRoles(event *events.MessageCreate, args []string) {
+	var msg string
+	defer func() { sendBotResponse(event, msg, "") }()
+	conf := d.snapshotConfig()
+	sgClient := d.newSimGridClient()
+	removeWithdrawn := conf.RoleSync.RemoveWithdrawn || slices.Contains(args, removeWithdrawnFlag)
+	var err error
+	msg, err = d.runSyncRoles(sgClient, removeWithdrawn)
+	if err != nil {
+		msg = err.Error()
+	}
+}
+
+// roleSyncKey keys the scheduled role sync by the minute it fires, so it runs
+// on every tick of its schedule rather than once per round.
+func (d *DiscordClient) roleSyncKey() (string, error) {
+	return fmt.Sprintf("%s/%s", d.snapshotConfig().Season, time.Now().Format("2006-01-02 15:04")), nil
+}
+
+func (d *DiscordClient) runScheduledRoleSync(ctx context.Context) (string, error) {
+	sgClient := d.newSimGridClient().WithContext(ctx)
+	return d.runSyncRoles(sgClient, d.snapshotConfig().RoleSync.RemoveWithdrawn)
+}
+
+// runSyncRoles gives the season role to every driver registered for the
+// championship who doesn't have it. With removeWithdrawn it also takes the
+// role from members who hold it but are no longer registered, unless some
+// registered drivers could not be matched, since any of them might be one of
+// those members.
+func (d *DiscordClient) runSyncRoles(sgClient *simgrid.SimGridClient, removeWithdrawn bool) (string, error) {
+	conf := d.snapshotConfig()
+	users, err := sgClient.UsersForChampionship(conf.ChampionshipId)
+	if err != nil {
+		return "", fmt.Errorf("failed getting registered drivers: %w", err)
+	}
+	role, err := d.lookupRole(conf.DiscordRoleName)
+	if err != nil {
+		return "", err
+	}
+	guild, err := d.getGuild()
+	if err != nil {
+		return "", err
+	}
+	// Linked drivers resolve without the member cache, but it's needed to
+	// see who already holds the role.
+	members, err := d.loadMembers()
+	if err != nil {
+		return "", err
+	}
+
+	registered := map[snowflake.ID]models.Driver{}
+	var unmatched []models.Driver
+	for _, user := range users {
+		driver := models.Driver{
+			FirstName:     user.FirstName,
+			LastName:      user.LastName,
+			DiscordHandle: user.DiscordHandle,
+			SteamID:       user.SteamID,
+		}
+		id, err := d.getDriverId(driver)
+		if errors.Is(err, DiscordHandleNotFoundError{}) {
+			unmatched = append(unmatched, driver)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		registered[id] = driver
+	}
+
+	holders := map[snowflake.ID]bool{}
+	var withdrawn []snowflake.ID
+	for _, member := range members.withRole(role.ID) {
+		holders[member.User.ID] = true
+		if _, ok := registered[member.User.ID]; !ok && !member.User.Bot {
+			withdrawn = append(withdrawn, member.User.ID)
+		}
+	}
+
+	ids := make([]snowflake.ID, 0, len(registered))
+	for id := range registered {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var added, removed, failed []string
+	for _, id := range ids {
+		if holders[id] {
+			continue
+		}
+		if err := d.rest.AddMemberRole(guild, id, role.ID); err != nil {
+			failed = append(failed, fmt.Sprintf("- Could not give the role to %s (<@%s>): %s", registered[id].Name(), id, err))
+			continue
+		}
+		added = append(added, fmt.Sprintf("- %s (<@%s>)", registered[id].Name(), id))
+	}
+
+	var kept []string
+	for _, id := range withdrawn {
+		if !removeWithdrawn || len(unmatched) > 0 {
+			kept = append(kept, fmt.Sprintf("- <@%s>", id))
+			continue
+		}
+		if err := d.rest.RemoveMemberRole(guild, id, role.ID); err != nil {
+			failed = append(failed, fmt.Sprintf("- Could not take the role from <@%s>: %s", id, err))
+			continue
+		}
+		removed = append(removed, fmt.Sprintf("- <@%s>", id))
+	}
+
+	var sections []string
+	if len(added) > 0 {
+		sections = append(sections, fmt.Sprintf("Gave `%s` to %d driver(s):\n%s", role.Name, len(added), strings.Join(added, "\n")))
+	}
+	if len(removed) > 0 {
+		sections = append(sections, fmt.Sprintf("Took `%s` from %d withdrawn member(s):\n%s", role.Name, len(removed), strings.Join(removed, "\n")))
+	}
+	if len(kept) > 0 {
+		reason := fmt.Sprintf("Run `!sync-roles %s` to take it from them.", removeWithdrawnFlag)
+		if removeWithdrawn {
+			reason = "Left them alone until every registered driver is matched."
+		}
+		sections = append(sections, fmt.Sprintf("%d member(s) hold `%s` but are not registered. %s\n%s", len(kept), role.Name, reason, strings.Join(kept, "\n")))
+	}
+	if len(unmatched) > 0 {
+		lines := make([]string, 0, len(unmatched))
+		for _, driver := range unmatched {
+			lines = append(lines, fmt.Sprintf("- %s (SimGrid `%s`)", driver.Name(), driver.DiscordHandle))
+		}
+		sections = append(sections, fmt.Sprintf("⚠️ Could not match %d registered driver(s) to a Discord member. Link them with `!link-driver`:\n%s", len(unmatched), strings.Join(lines, "\n")))
+	}
+	if len(failed) > 0 {
+		sections = append(sections, strings.Join(failed, "\n"))
+	}
+	if len(sections) == 0 {
+		return fmt.Sprintf("Every registered driver already has `%s`.", role.Name), nil
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
@@ -0,0 +1,121 @@
+package discord
+
+import (
+	"errors"
+	"time"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("syncing the season role", func() {
+	var (
+		league   *testLeague
+		client   *DiscordClient
+		stub     *stubRest
+		sgClient *simgrid.SimGridClient
+		added    []snowflake.ID
+		removed  []snowflake.ID
+	)
+
+	role := snowflakeID(777)
+
+	BeforeEach(func() {
+		league = newTestLeague("roles", config.BotConfig{DiscordRoleName: "Rookies"}, nil)
+		client, stub, sgClient = league.client, league.stub, league.sgClient
+
+		added, removed = nil, nil
+		league.members = []dgo.Member{
+			{User: dgo.User{ID: snowflakeID(42), Username: "max.verstappen"}},
+			{User: dgo.User{ID: snowflakeID(43), Username: "lando.norris"}, RoleIDs: []snowflake.ID{role}},
+			{User: dgo.User{ID: snowflakeID(44), Username: "seb"}, RoleIDs: []snowflake.ID{role}},
+			{User: dgo.User{ID: snowflakeID(45), Username: "rookies-bot", Bot: true}, RoleIDs: []snowflake.ID{role}},
+		}
+		league.users = `[
+			{"steam64_id":"111","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"},
+			{"steam64_id":"222","username":"lando.norris","first_name":"Lando","last_name":"Norris"},
+			{"steam64_id":"333","username":"charles","first_name":"Charles","last_name":"Leclerc"}
+		]`
+		stub.getRolesFn = func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+			return []dgo.Role{{Name: "Rookies", ID: role}}, nil
+		}
+		stub.addMemberRoleFn = func(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) error {
+			Expect(roleID).To(Equal(role))
+			added = append(added, userID)
+			return nil
+		}
+		stub.removeMemberRoleFn = func(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) error {
+			Expect(roleID).To(Equal(role))
+			removed = append(removed, userID)
+			return nil
+		}
+	})
+	AfterEach(func() {
+		league.close()
+	})
+
+	It("gives the role to registered drivers and reports who could not be matched", func() {
+		msg, err := client.runSyncRoles(sgClient, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(Equal([]snowflake.ID{snowflakeID(42)}))
+		Expect(removed).To(BeEmpty())
+		Expect(msg).To(Equal("Gave `Rookies` to 1 driver(s):\n- Max Verstappen (<@42>)\n\n" +
+			"1 member(s) hold `Rookies` but are not registered. Run `!sync-roles --remove-withdrawn` to take it from them.\n- <@44>\n\n" +
+			"⚠️ Could not match 1 registered driver(s) to a Discord member. Link them with `!link-driver`:\n- Charles Leclerc (SimGrid `charles`)"))
+	})
+
+	It("matches drivers through their identity links", func() {
+		Expect(client.saveIdentity("333", identityLink{DiscordID: snowflakeID(44), Source: linkSourceAdmin, LinkedAt: time.Now()})).To(Succeed())
+		msg, err := client.runSyncRoles(sgClient, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(Equal([]snowflake.ID{snowflakeID(42)}))
+		Expect(removed).To(BeEmpty())
+		Expect(msg).To(Equal("Gave `Rookies` to 1 driver(s):\n- Max Verstappen (<@42>)"))
+	})
+
+	It("takes the role from withdrawn members once every driver is matched", func() {
+		league.users = `[{"steam64_id":"111","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"}]`
+		msg, err := client.runSyncRoles(sgClient, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(Equal([]snowflake.ID{snowflakeID(42)}))
+		Expect(removed).To(Equal([]snowflake.ID{snowflakeID(43), snowflakeID(44)}))
+		Expect(msg).To(ContainSubstring("Took `Rookies` from 2 withdrawn member(s):\n- <@43>\n- <@44>"))
+	})
+
+	It("holds back removals while some drivers are unmatched", func() {
+		msg, err := client.runSyncRoles(sgClient, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(BeEmpty())
+		Expect(msg).To(ContainSubstring("1 member(s) hold `Rookies` but are not registered. Left them alone until every registered driver is matched.\n- <@44>"))
+	})
+
+	It("reports role changes that fail", func() {
+		stub.addMemberRoleFn = func(guildID snowflake.ID, userID snowflake.ID, roleID snowflake.ID, opts ...rest.RequestOpt) error {
+			return errors.New("missing permissions")
+		}
+		msg, err := client.runSyncRoles(sgClient, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("- Could not give the role to Max Verstappen (<@42>): missing permissions"))
+	})
+
+	It("has nothing to do when everyone has the role", func() {
+		league.users = `[{"steam64_id":"222","username":"lando.norris","first_name":"Lando","last_name":"Norris"}]`
+		league.members = []dgo.Member{{User: dgo.User{ID: snowflakeID(43), Username: "lando.norris"}, RoleIDs: []snowflake.ID{role}}}
+		msg, err := client.runSyncRoles(sgClient, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal("Every registered driver already has `Rookies`."))
+	})
+
+	It("fails when the role does not exist", func() {
+		stub.getRolesFn = func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+			return nil, nil
+		}
+		_, err := client.runSyncRoles(sgClient, false)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,301 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+)
+
+// Kinds of race-setup operation, in the order race setup performs them.
+const (
+	opBriefingDoc    = "briefing_doc"
+	opBriefingPDF    = "briefing_pdf"
+	opPenaltyTracker = "penalty_tracker"
+	opNextRoundFile  = "next_round_file"
+	opMessage        = "message"
+	opUnpin          = "unpin"
+	opScheduledEvent = "scheduled_event"
+	opRoundThread    = "round_thread"
+	opArchiveThreads = "archive_threads"
+	opStoredRound    = "stored_round"
+)
+
+// setupOperation is one side effect of a race-setup run, with what it takes
+// to undo it.
+type setupOperation struct {
+	Kind      string       `yaml:"kind"`
+	FileID    string       `yaml:"file_id,omitempty"`
+	Path      string       `yaml:"path,omitempty"`
+	ChannelID snowflake.ID `yaml:"channel_id,omitempty"`
+	MessageID snowflake.ID `yaml:"message_id,omitempty"`
+	EventID   snowflake.ID `yaml:"event_id,omitempty"`
+	// RoundKey is the roundKey of the threads an operation opened or
+	// archived.
+	RoundKey string       `yaml:"round_key,omitempty"`
+	Previous *storedRound `yaml:"previous,omitempty"`
+	At       time.Time    `yaml:"at"`
+}
+
+// raceSetupJournal records the side effects of the latest race-setup run for
+// a round, in the order they happened, so the run can be rolled back.
+type raceSetupJournal struct {
+	Season        string           `yaml:"season"`
+	Round         config.Round     `yaml:"round"`
+	PreviousRound config.Round     `yaml:"previous_round"`
+	StartedAt     time.Time        `yaml:"started_at"`
+	Completed     bool             `yaml:"completed,omitempty"`
+	RolledBack    bool             `yaml:"rolled_back,omitempty"`
+	Operations    []setupOperation `yaml:"operations,omitempty"`
+}
+
+// raceSetupDocument names the journal of the latest race-setup run for round.
+func raceSetupDocument(season string, round config.Round) string {
+	return roundDocument("race-setup", season, round)
+}
+
+// stoppedPartway reports whether the run left side effects behind without
+// finishing or being rolled back.
+func (j *raceSetupJournal) stoppedPartway() bool {
+	return j != nil && !j.Completed && !j.RolledBack && len(j.Operations) > 0
+}
+
+// setupJournal writes a race-setup run's journal as the run goes, so it
+// survives the run failing or the bot dying partway. Without a state dir it
+// records nothing.
+type setupJournal struct {
+	d       *DiscordClient
+	doc     string
+	journal raceSetupJournal
+	err     error
+}
+
+// startRaceSetupJournal starts the journal for a race-setup run. It refuses
+// to start while an earlier run for the round stopped partway, since running
+// again would duplicate what that run left behind.
+func (d *DiscordClient) startRaceSetupJournal(roundConfig *config.RoundConfig, now time.Time) (*setupJournal, error) {
+	j := &setupJournal{d: d}
+	if d.state.Dir() == "" {
+		return j, nil
+	}
+	conf := d.snapshotConfig()
+	j.doc = raceSetupDocument(conf.Season, roundConfig.NextRound)
+
+	var previous *raceSetupJournal
+	if err := d.state.Load(j.doc, &previous); err != nil {
+		return nil, err
+	}
+	if previous.stoppedPartway() {
+		return nil, fmt.Errorf("race setup for %s stopped partway on %s. Run `!rollback-race-setup %d` before running it again",
+			roundConfig.NextRound, previous.StartedAt.Format(time.RFC1123), roundConfig.NextRound.Number)
+	}
+
+	j.journal = raceSetupJournal{
+		Season:        conf.Season,
+		Round:         roundConfig.NextRound,
+		PreviousRound: roundConfig.PreviousRound,
+		StartedAt:     now,
+	}
+	if err := d.state.Save(j.doc, &j.journal); err != nil {
+		return nil, fmt.Errorf("could not start the race setup journal: %w", err)
+	}
+	return j, nil
+}
+
+// record appends op to the journal. Once a write fails the journal stops
+// recording; complete reports it.
+func (j *setupJournal) record(op setupOperation) {
+	if j == nil || j.doc == "" || j.err != nil {
+		return
+	}
+	op.At = time.Now()
+	j.journal.Operations = append(j.journal.Operations, op)
+	j.err = j.d.state.Save(j.doc, &j.journal)
+}
+
+// fail adds how to undo the run's side effects to the error that stopped it.
+func (j *setupJournal) fail(err error) error {
+	if len(j.journal.Operations) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w\n\nRun `!rollback-race-setup %d` to undo what race setup already did.", err, j.journal.Round.Number)
+}
+
+// complete marks the run finished, returning a note for the admin response
+// when the journal could not be kept.
+func (j *setupJournal) complete() string {
+	if j.doc == "" {
+		return ""
+	}
+	if j.err == nil {
+		j.journal.Completed = true
+		j.err = j.d.state.Save(j.doc, &j.journal)
+	}
+	if j.err != nil {
+		return fmt.Sprintf("\n\nCould not journal race setup for `!rollback-race-setup`: %s", j.err)
+	}
+	return ""
+}
+
+func (d *DiscordClient) rollbackRaceSetup(event *events.MessageCreate, args []string) {
+	var msg string
+	defer func() { sendBotResponse(event, msg, "") }()
+	if len(args) != 1 {
+		msg = "usage: `!rollback-race-setup <round number>`"
+		return
+	}
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		msg = fmt.Sprintf("invalid round number %q", args[0])
+		return
+	}
+	gcClient, err := d.newGCloudClient()
+	if err != nil {
+		msg = err.Error()
+		return
+	}
+	msg, err = d.runRollbackRaceSetup(number, gcClient)
+	if err != nil {
+		msg = err.Error()
+	}
+}
+
+// runRollbackRaceSetup undoes the journaled side effects of the latest
+// race-setup run for a round of the current season, newest first, then drops
+// the round's queued reminders and tracked briefing. Operations that fail
+// stay in the journal so running the command again retries just those.
+func (d *DiscordClient) runRollbackRaceSetup(number int, gcClient *gcloud.Client) (string, error) {
+	if d.state.Dir() == "" {
+		return "", fmt.Errorf("race setup is only journaled when the bot has a state_dir, so there is nothing to roll back")
+	}
+	conf := d.snapshotConfig()
+	doc := raceSetupDocument(conf.Season, config.Round{Number: number})
+
+	var journal *raceSetupJournal
+	if err := d.state.Load(doc, &journal); err != nil {
+		return "", err
+	}
+	if journal == nil {
+		return "", fmt.Errorf("no race setup for round %d was recorded, so there is nothing to roll back", number)
+	}
+	if journal.RolledBack {
+		return "", fmt.Errorf("race setup for %s was already rolled back", journal.Round)
+	}
+
+	var done, failed []string
+	var kept []setupOperation
+	for i := len(journal.Operations) - 1; i >= 0; i-- {
+		op := journal.Operations[i]
+		note, err := d.undoSetupOperation(journal, op, gcClient)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("- Could not undo the %s: %s", strings.ReplaceAll(op.Kind, "_", " "), err))
+			kept = append([]setupOperation{op}, kept...)
+			continue
+		}
+		if note != "" {
+			done = append(done, "- "+note)
+		}
+	}
+
+	key := roundKey(journal.Season, journal.Round)
+	if dropped, err := d.cancelReminders(key); err != nil {
+		failed = append(failed, fmt.Sprintf("- Could not cancel the round's reminders: %s", err))
+	} else if dropped > 0 {
+		done = append(done, fmt.Sprintf("- Cancelled %d queued reminder(s)", dropped))
+	}
+	if stopped, err := d.stopTrackingAttendance(journal.Season, journal.Round); err != nil {
+		failed = append(failed, fmt.Sprintf("- Could not stop tracking briefing attendance: %s", err))
+	} else if stopped {
+		done = append(done, "- Stopped tracking briefing attendance")
+	}
+	if err := d.clearRun(jobRaceSetup, journal.Round); err != nil {
+		failed = append(failed, fmt.Sprintf("- Could not reset the scheduled race-setup job: %s", err))
+	}
+	if err := d.forgetRuns(journal.Season, "!race-setup", journal.Round); err != nil {
+		failed = append(failed, fmt.Sprintf("- Could not forget the earlier `!race-setup` runs: %s", err))
+	}
+
+	journal.Operations = kept
+	journal.RolledBack = len(failed) == 0
+	if err := d.state.Save(doc, journal); err != nil {
+		failed = append(failed, fmt.Sprintf("- Could not save the race setup journal: %s", err))
+	}
+
+	msg := fmt.Sprintf("Rolled back race setup for %s.", journal.Round)
+	if len(failed) > 0 {
+		msg = fmt.Sprintf("Rolled back race setup for %s partway. Run the command again to retry what failed.", journal.Round)
+	}
+	if lines := append(done, failed...); len(lines) > 0 {
+		msg = fmt.Sprintf("%s\n%s", msg, strings.Join(lines, "\n"))
+	}
+	return msg, nil
+}
+
+// undoSetupOperation reverses one journaled operation, returning what it did.
+func (d *DiscordClient) undoSetupOperation(journal *raceSetupJournal, op setupOperation, gcClient *gcloud.Client) (string, error) {
+	switch op.Kind {
+	case opBriefingDoc:
+		return "Trashed the briefing doc", gcClient.TrashFile(op.FileID)
+	case opBriefingPDF:
+		return "Removed the stored briefing PDF", d.state.Delete(op.Path)
+	case opPenaltyTracker:
+		return "Trashed the penalty tracker", gcClient.TrashFile(op.FileID)
+	case opNextRoundFile:
+		// The file is normally removed once it's attached to the response.
+		err := os.Remove(op.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "Removed the next round config file", err
+	case opMessage:
+		if err := d.rest.DeleteMessage(op.ChannelID, op.MessageID); err != nil {
+			return "", err
+		}
+		return "Deleted the race-day announcement", d.forgetRaceDayAnnouncement(journal, op.MessageID)
+	case opUnpin:
+		return "Re-pinned the previous announcement", d.rest.PinMessage(op.ChannelID, op.MessageID)
+	case opScheduledEvent:
+		guild, err := d.getGuild()
+		if err != nil {
+			return "", err
+		}
+		return "Cancelled the briefing event", d.rest.DeleteGuildScheduledEvent(guild, op.EventID)
+	case opRoundThread:
+		if err := d.rest.DeleteChannel(op.ChannelID); err != nil {
+			return "", err
+		}
+		return "Deleted a round thread", d.forgetRoundThread(op.RoundKey, op.ChannelID)
+	case opArchiveThreads:
+		return d.reopenRoundThreads(op.RoundKey)
+	case opStoredRound:
+		if op.Previous == nil {
+			return "Removed the stored round config", d.state.Delete(currentRoundDocument)
+		}
+		return fmt.Sprintf("Restored the stored round config for %s", op.Previous.NextRound), d.state.Save(currentRoundDocument, op.Previous)
+	}
+	return "", fmt.Errorf("unknown operation %q", op.Kind)
+}
+
+// forgetRaceDayAnnouncement drops a deleted race-day announcement from the
+// record `!amend-penalties` edits.
+func (d *DiscordClient) forgetRaceDayAnnouncement(journal *raceSetupJournal, messageID snowflake.ID) error {
+	doc := announcementDocument(journal.Season, journal.PreviousRound)
+	var posted *postedAnnouncement
+	if err := d.state.Load(doc, &posted); err != nil {
+		return err
+	}
+	if posted == nil || posted.RaceDay == nil || posted.RaceDay.MessageID != messageID {
+		return nil
+	}
+	posted.RaceDay = nil
+	posted.BriefingDocURL = ""
+	posted.BriefingTime = time.Time{}
+	return d.state.Save(doc, posted)
+}
@@ -0,0 +1,271 @@
+package discord
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	"github.com/geofffranks/rookies-bot/gcloud/fakes"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/drive/v3"
+)
+
+var _ = Describe("rolling back race setup", func() {
+	var (
+		client      *DiscordClient
+		stub        *stubRest
+		league      *testLeague
+		sgClient    *simgrid.SimGridClient
+		gcClient    *gcloud.Client
+		fakeDrive   *fakes.FakeDriveServicer
+		roundConfig *config.RoundConfig
+		stored      *config.RoundConfig
+		pinned      []snowflake.ID
+		deleted     []snowflake.ID
+		events      []snowflake.ID
+		threads     []snowflake.ID
+		reopened    []snowflake.ID
+		files       []string
+	)
+
+	thread := func(id uint64) *dgo.GuildThread {
+		channel := dgo.GuildThread{}
+		Expect(channel.UnmarshalJSON([]byte(`{"id":"` + snowflakeID(id).String() + `","type":11}`))).To(Succeed())
+		return &channel
+	}
+
+	BeforeEach(func() {
+		pinned, deleted, events, threads, reopened, files = nil, nil, nil, nil, nil, nil
+
+		fakeDrive = &fakes.FakeDriveServicer{}
+		fakeDrive.CopyFileReturnsOnCall(0, &drive.File{Id: "briefing-id"}, nil)
+		fakeDrive.CopyFileReturnsOnCall(1, &drive.File{Id: "tracker-id"}, nil)
+		fakeDrive.ExportFileReturns([]byte("%PDF-1.7"), nil)
+		fakeDocs := &fakes.FakeDocsServicer{}
+		fakeDocs.GetDocumentReturns(makeBriefingDoc(), nil)
+		gcClient = &gcloud.Client{Docs: fakeDocs, Drive: fakeDrive}
+
+		league = newTestLeague("rollback", config.BotConfig{
+			DiscordChannelId:         snowflakeID(111),
+			DiscordBriefingChannelId: snowflakeID(222),
+			DiscordRoleName:          "Rookies",
+		}, gcClient)
+		client, stub, sgClient = league.client, league.stub, league.sgClient
+		stub.getRolesFn = func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+			return []dgo.Role{{Name: "Rookies", ID: snowflakeID(777)}}, nil
+		}
+		stub.createMessageFn = func(channelID snowflake.ID, m dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+			for _, f := range m.Files {
+				files = append(files, f.Name)
+			}
+			return &dgo.Message{ID: snowflakeID(700)}, nil
+		}
+		stub.getChannelPinsFn = func(channelID snowflake.ID, before time.Time, limit int, opts ...rest.RequestOpt) (*dgo.ChannelPins, error) {
+			return &dgo.ChannelPins{Items: []dgo.MessagePin{
+				{Message: dgo.Message{ID: snowflakeID(601), Author: dgo.User{ID: snowflakeID(1)}}},
+				{Message: dgo.Message{ID: snowflakeID(602), Author: dgo.User{ID: snowflakeID(99)}}},
+			}}, nil
+		}
+		stub.pinMessageFn = func(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error {
+			pinned = append(pinned, messageID)
+			return nil
+		}
+		stub.createGuildScheduledEventFn = func(guildID snowflake.ID, e dgo.GuildScheduledEventCreate, opts ...rest.RequestOpt) (*dgo.GuildScheduledEvent, error) {
+			return &dgo.GuildScheduledEvent{ID: snowflakeID(800)}, nil
+		}
+		stub.deleteMessageFn = func(channelID snowflake.ID, messageID snowflake.ID, opts ...rest.RequestOpt) error {
+			Expect(channelID).To(Equal(snowflakeID(111)))
+			deleted = append(deleted, messageID)
+			return nil
+		}
+		stub.deleteGuildScheduledEventFn = func(guildID snowflake.ID, eventID snowflake.ID, opts ...rest.RequestOpt) error {
+			events = append(events, eventID)
+			return nil
+		}
+		stub.createThreadFromMessageFn = func(channelID snowflake.ID, messageID snowflake.ID, t dgo.ThreadCreateFromMessage, opts ...rest.RequestOpt) (*dgo.GuildThread, error) {
+			return thread(901), nil
+		}
+		stub.createThreadFn = func(channelID snowflake.ID, t dgo.ThreadCreate, opts ...rest.RequestOpt) (*dgo.GuildThread, error) {
+			return thread(902), nil
+		}
+		stub.deleteChannelFn = func(channelID snowflake.ID, opts ...rest.RequestOpt) error {
+			threads = append(threads, channelID)
+			return nil
+		}
+		stub.updateChannelFn = func(channelID snowflake.ID, u dgo.ChannelUpdate, opts ...rest.RequestOpt) (dgo.Channel, error) {
+			if !*u.(dgo.GuildThreadUpdate).Archived {
+				reopened = append(reopened, channelID)
+			}
+			return dgo.GuildThread{}, nil
+		}
+
+		roundConfig = &config.RoundConfig{
+			PreviousRound: config.Round{Number: 1, Track: "Monza"},
+			NextRound:     config.Round{Number: 2, Track: "Imola"},
+		}
+		stored = &config.RoundConfig{PreviousRound: config.Round{Number: 1, Track: "Monza"}, NextRound: config.Round{Number: 2, Track: "Imola"}}
+		Expect(client.saveCurrentRound(stored)).To(Succeed())
+		Expect(client.state.Save(threadsDocument, &threadIndex{Rounds: map[string]*roundThreads{
+			roundKey("2026 Fall", roundConfig.PreviousRound): {
+				Season:       "2026 Fall",
+				Round:        roundConfig.PreviousRound,
+				DiscussionID: snowflakeID(801),
+				StewardsID:   snowflakeID(802),
+			},
+		}})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		league.close()
+	})
+
+	raceSetup := func() error {
+		_, attachment, err := client.runRaceSetup(roundConfig, sgClient, gcClient)
+		if attachment != "" {
+			Expect(os.Remove(attachment)).To(Succeed())
+		}
+		return err
+	}
+
+	loadJournal := func() *raceSetupJournal {
+		var journal *raceSetupJournal
+		Expect(client.state.Load(raceSetupDocument("2026 Fall", roundConfig.NextRound), &journal)).To(Succeed())
+		return journal
+	}
+
+	It("journals each side effect of the run", func() {
+		Expect(raceSetup()).To(Succeed())
+
+		journal := loadJournal()
+		Expect(journal.Completed).To(BeTrue())
+		var kinds []string
+		for _, op := range journal.Operations {
+			kinds = append(kinds, op.Kind)
+		}
+		Expect(kinds).To(Equal([]string{opBriefingDoc, opBriefingPDF, opPenaltyTracker, opNextRoundFile, opMessage, opUnpin, opScheduledEvent, opRoundThread, opRoundThread, opArchiveThreads, opStoredRound}))
+		Expect(journal.Operations[0].FileID).To(Equal("briefing-id"))
+		Expect(journal.Operations[1].Path).To(Equal("briefings/2026-fall/round-2.pdf"))
+		Expect(files).To(Equal([]string{"Drivers Briefing Round 2.pdf"}))
+		Expect(journal.Operations[2].FileID).To(Equal("tracker-id"))
+		Expect(journal.Operations[5].MessageID).To(Equal(snowflakeID(601)))
+		Expect(journal.Operations[7].ChannelID).To(Equal(snowflakeID(901)))
+		Expect(journal.Operations[8].ChannelID).To(Equal(snowflakeID(902)))
+		Expect(journal.Operations[9].RoundKey).To(Equal(roundKey("2026 Fall", roundConfig.PreviousRound)))
+		Expect(journal.Operations[10].Previous.NextRound).To(Equal(stored.NextRound))
+	})
+
+	It("undoes a completed run", func() {
+		Expect(raceSetup()).To(Succeed())
+		key := idempotencyKey("2026 Fall", "!race-setup", roundConfig.NextRound, "abc123")
+		Expect(client.recordRun(key, commandRun{Command: "!race-setup", Round: roundConfig.NextRound, Response: "done"})).To(BeEmpty())
+		Expect(client.state.Save(remindersDocument, &reminderQueue{Reminders: []reminder{
+			{Key: roundKey("2026 Fall", roundConfig.NextRound)},
+			{Key: roundKey("2026 Fall", config.Round{Number: 3})},
+		}})).To(Succeed())
+		pinned = nil
+
+		msg, err := client.runRollbackRaceSetup(2, gcClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal("Rolled back race setup for Round 2 - Imola.\n" +
+			"- Restored the stored round config for Round 2 - Imola\n" +
+			"- Reopened the Round 1 - Monza threads\n" +
+			"- Deleted a round thread\n" +
+			"- Deleted a round thread\n" +
+			"- Cancelled the briefing event\n" +
+			"- Re-pinned the previous announcement\n" +
+			"- Deleted the race-day announcement\n" +
+			"- Trashed the penalty tracker\n" +
+			"- Removed the stored briefing PDF\n" +
+			"- Trashed the briefing doc\n" +
+			"- Cancelled 1 queued reminder(s)\n" +
+			"- Stopped tracking briefing attendance"))
+
+		Expect(filepath.Join(league.stateDir, "briefings", "2026-fall", "round-2.pdf")).NotTo(BeAnExistingFile())
+		Expect(fakeDrive.TrashFileCallCount()).To(Equal(2))
+		_, first := fakeDrive.TrashFileArgsForCall(0)
+		_, second := fakeDrive.TrashFileArgsForCall(1)
+		Expect([]string{first, second}).To(Equal([]string{"tracker-id", "briefing-id"}))
+		Expect(deleted).To(Equal([]snowflake.ID{snowflakeID(700)}))
+		Expect(pinned).To(Equal([]snowflake.ID{snowflakeID(601)}))
+		Expect(events).To(Equal([]snowflake.ID{snowflakeID(800)}))
+		Expect(threads).To(Equal([]snowflake.ID{snowflakeID(902), snowflakeID(901)}))
+		Expect(reopened).To(Equal([]snowflake.ID{snowflakeID(801), snowflakeID(802)}))
+
+		var index threadIndex
+		Expect(client.state.Load(threadsDocument, &index)).To(Succeed())
+		Expect(index.Rounds).To(HaveLen(1))
+		Expect(index.Rounds[roundKey("2026 Fall", roundConfig.PreviousRound)].Archived).To(BeFalse())
+
+		current, err := client.loadCurrentRound()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current.NextRound).To(Equal(stored.NextRound))
+
+		var posted *postedAnnouncement
+		Expect(client.state.Load(announcementDocument("2026 Fall", roundConfig.PreviousRound), &posted)).To(Succeed())
+		Expect(posted.RaceDay).To(BeNil())
+
+		Expect(client.repeatedRun(key)).To(BeEmpty())
+
+		Expect(loadJournal().RolledBack).To(BeTrue())
+		_, err = client.runRollbackRaceSetup(2, gcClient)
+		Expect(err).To(MatchError("race setup for Round 2 - Imola was already rolled back"))
+	})
+
+	It("refuses to re-run a round whose last run stopped partway until it is rolled back", func() {
+		stub.createGuildScheduledEventFn = func(guildID snowflake.ID, e dgo.GuildScheduledEventCreate, opts ...rest.RequestOpt) (*dgo.GuildScheduledEvent, error) {
+			return nil, errors.New("missing permissions")
+		}
+		err := raceSetup()
+		Expect(err).To(MatchError(ContainSubstring("failed to create briefing event: missing permissions")))
+		Expect(err).To(MatchError(ContainSubstring("Run `!rollback-race-setup 2` to undo what race setup already did.")))
+
+		err = raceSetup()
+		Expect(err).To(MatchError(ContainSubstring("race setup for Round 2 - Imola stopped partway on")))
+		Expect(fakeDrive.CopyFileCallCount()).To(Equal(2))
+
+		msg, err := client.runRollbackRaceSetup(2, gcClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).NotTo(ContainSubstring("briefing event"))
+		Expect(msg).To(ContainSubstring("Deleted the race-day announcement"))
+
+		fakeDrive.CopyFileReturns(&drive.File{Id: "retry-id"}, nil)
+		stub.createGuildScheduledEventFn = nil
+		Expect(raceSetup()).To(Succeed())
+	})
+
+	It("keeps the steps it could not undo for a retry", func() {
+		Expect(raceSetup()).To(Succeed())
+		fakeDrive.TrashFileReturnsOnCall(0, errors.New("forbidden"))
+
+		msg, err := client.runRollbackRaceSetup(2, gcClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(HavePrefix("Rolled back race setup for Round 2 - Imola partway. Run the command again to retry what failed."))
+		Expect(msg).To(ContainSubstring("- Could not undo the penalty tracker: forbidden"))
+
+		journal := loadJournal()
+		Expect(journal.RolledBack).To(BeFalse())
+		Expect(journal.Operations).To(HaveLen(1))
+
+		msg, err = client.runRollbackRaceSetup(2, gcClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal("Rolled back race setup for Round 2 - Imola.\n- Trashed the penalty tracker"))
+		Expect(deleted).To(HaveLen(1))
+	})
+
+	It("has nothing to roll back without a journal", func() {
+		_, err := client.runRollbackRaceSetup(4, gcClient)
+		Expect(err).To(MatchError("no race setup for round 4 was recorded, so there is nothing to roll back"))
+
+		stateless := NewTestDiscordClient(stub, snowflakeID(1), &config.Config{}, gcClient)
+		_, err = stateless.runRollbackRaceSetup(2, gcClient)
+		Expect(err).To(MatchError(ContainSubstring("only journaled when the bot has a state_dir")))
+	})
+})
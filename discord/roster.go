@@ -0,0 +1,156 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/simgrid"
+)
+
+func (d *DiscordClient) roster(event *events.MessageCreate) {
+	var msg string
+	defer func() { sendBotResponse(event, msg, "") }()
+	sgClient := d.newSimGridClient()
+	var err error
+	msg, err = d.runRoster(sgClient)
+	if err != nil {
+		msg = err.Error()
+	}
+}
+
+// runRoster reconciles the championship's entry list with the Discord server.
+func (d *DiscordClient) runRoster(sgClient *simgrid.SimGridClient) (string, error) {
+	issues, err := d.rosterIssues(sgClient)
+	if err != nil {
+		return "", err
+	}
+	if len(issues) == 0 {
+		return "The roster checks out: every entry has a Discord member, every role holder is entered, and car numbers and names are all set.", nil
+	}
+	return strings.Join(issues, "\n\n"), nil
+}
+
+// reportRosterBeforeRaceSetup posts the roster issues to the admin channel
+// ahead of race setup for round, so they can be fixed before race day. It
+// stays quiet when the roster checks out.
+func (d *DiscordClient) reportRosterBeforeRaceSetup(sgClient *simgrid.SimGridClient, round config.Round) {
+	issues, err := d.rosterIssues(sgClient)
+	if err != nil {
+		d.reportToAdmins(fmt.Sprintf("Could not check the roster before race setup for %s: %s", round, err))
+		return
+	}
+	if len(issues) == 0 {
+		return
+	}
+	d.reportToAdmins(fmt.Sprintf("**Roster check before race setup for %s**\n%s", round, strings.Join(issues, "\n\n")))
+}
+
+// rosterIssues cross-references the championship's entries and participating
+// users with the guild's members and the holders of the season role. It
+// returns one section per kind of problem found: entries with no Discord
+// member, role holders who are not entered, duplicate car numbers and
+// drivers with empty names.
+func (d *DiscordClient) rosterIssues(sgClient *simgrid.SimGridClient) ([]string, error) {
+	conf := d.snapshotConfig()
+	entries, err := sgClient.GetEntriesForChampionship(conf.ChampionshipId)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting the entry list: %w", err)
+	}
+	users, err := sgClient.UsersForChampionship(conf.ChampionshipId)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting registered drivers: %w", err)
+	}
+	role, err := d.lookupRole(conf.DiscordRoleName)
+	if err != nil {
+		return nil, err
+	}
+	members, err := d.loadMembers()
+	if err != nil {
+		return nil, err
+	}
+
+	usersByPlayer := map[string]simgrid.User{}
+	for _, user := range users {
+		usersByPlayer["S"+user.SteamID] = user
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].CarNumber < entries[j].CarNumber })
+
+	entered := map[snowflake.ID]bool{}
+	carEntries := map[int]int{}
+	carDrivers := map[int][]string{}
+	var cars []int
+	var missing, unnamed []string
+	for _, entry := range entries {
+		if carEntries[entry.CarNumber] == 0 {
+			cars = append(cars, entry.CarNumber)
+		}
+		carEntries[entry.CarNumber]++
+		for _, entrant := range entry.Drivers {
+			user := usersByPlayer[entrant.PlayerID]
+			driver := models.Driver{
+				FirstName:     entrant.FirstName,
+				LastName:      entrant.LastName,
+				DiscordHandle: user.DiscordHandle,
+				CarNumber:     entry.CarNumber,
+				SteamID:       strings.TrimPrefix(entrant.PlayerID, "S"),
+			}
+			label := fmt.Sprintf("#%d %s", entry.CarNumber, driver.Name())
+			carDrivers[entry.CarNumber] = append(carDrivers[entry.CarNumber], driver.Name())
+
+			if strings.TrimSpace(driver.FirstName) == "" || strings.TrimSpace(driver.LastName) == "" {
+				label = fmt.Sprintf("#%d player `%s`", entry.CarNumber, entrant.PlayerID)
+				unnamed = append(unnamed, fmt.Sprintf("- %s: first name %q, last name %q", label, driver.FirstName, driver.LastName))
+			}
+
+			id, err := d.getDriverId(driver)
+			if errors.Is(err, DiscordHandleNotFoundError{}) {
+				missing = append(missing, fmt.Sprintf("- %s (SimGrid `%s`)", label, driver.DiscordHandle))
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := members.get(id); !ok {
+				missing = append(missing, fmt.Sprintf("- %s (linked to <@%s>, who is not in the server)", label, id))
+				continue
+			}
+			entered[id] = true
+		}
+	}
+
+	var unentered []string
+	for _, member := range members.withRole(role.ID) {
+		if !entered[member.User.ID] && !member.User.Bot {
+			unentered = append(unentered, fmt.Sprintf("- <@%s>", member.User.ID))
+		}
+	}
+
+	var duplicates []string
+	for _, car := range cars {
+		if carEntries[car] > 1 {
+			duplicates = append(duplicates, fmt.Sprintf("- #%d: %s", car, strings.Join(carDrivers[car], ", ")))
+		}
+	}
+
+	var issues []string
+	if len(missing) > 0 {
+		issues = append(issues, fmt.Sprintf("⚠️ %d entered driver(s) with no Discord member. Link them with `!link-driver` or ask them to join the server:\n%s", len(missing), strings.Join(missing, "\n")))
+	}
+	if len(unentered) > 0 {
+		issues = append(issues, fmt.Sprintf("⚠️ %d member(s) hold `%s` but are not entered. `!sync-roles %s` takes it from them:\n%s", len(unentered), role.Name, removeWithdrawnFlag, strings.Join(unentered, "\n")))
+	}
+	if len(duplicates) > 0 {
+		issues = append(issues, fmt.Sprintf("⚠️ %d car number(s) used by more than one entry:\n%s", len(duplicates), strings.Join(duplicates, "\n")))
+	}
+	if len(unnamed) > 0 {
+		issues = append(issues, fmt.Sprintf("⚠️ %d driver(s) with an empty name:\n%s", len(unnamed), strings.Join(unnamed, "\n")))
+	}
+	return issues, nil
+}
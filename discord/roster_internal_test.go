@@ -0,0 +1,104 @@
+package discord
+
+import (
+	"time"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("reconciling the roster", func() {
+	var (
+		league   *testLeague
+		client   *DiscordClient
+		stub     *stubRest
+		sgClient *simgrid.SimGridClient
+		reported []string
+	)
+
+	role := snowflakeID(777)
+
+	BeforeEach(func() {
+		league = newTestLeague("roster", config.BotConfig{
+			DiscordRoleName:       "Rookies",
+			DiscordAdminChannelId: snowflakeID(333),
+		}, nil)
+		client, stub, sgClient = league.client, league.stub, league.sgClient
+
+		reported = nil
+		league.members = []dgo.Member{
+			{User: dgo.User{ID: snowflakeID(42), Username: "max.verstappen"}, RoleIDs: []snowflake.ID{role}},
+			{User: dgo.User{ID: snowflakeID(43), Username: "lando.norris"}, RoleIDs: []snowflake.ID{role}},
+			{User: dgo.User{ID: snowflakeID(45), Username: "rookies-bot", Bot: true}, RoleIDs: []snowflake.ID{role}},
+		}
+		stub.getRolesFn = func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+			return []dgo.Role{{Name: "Rookies", ID: role}}, nil
+		}
+		stub.createMessageFn = func(channelID snowflake.ID, m dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+			Expect(channelID).To(Equal(snowflakeID(333)))
+			reported = append(reported, m.Content)
+			return &dgo.Message{}, nil
+		}
+	})
+	AfterEach(func() {
+		league.close()
+	})
+
+	It("says so when the roster checks out", func() {
+		msg, err := client.runRoster(sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(HavePrefix("The roster checks out"))
+
+		client.reportRosterBeforeRaceSetup(sgClient, config.Round{Number: 2, Track: "Imola"})
+		Expect(reported).To(BeEmpty())
+	})
+
+	It("flags missing members, unentered role holders, duplicate car numbers and empty names", func() {
+		Expect(client.saveIdentity("333", identityLink{DiscordID: snowflakeID(99), Source: linkSourceAdmin, LinkedAt: time.Now()})).To(Succeed())
+		league.entries = `{"entries":[
+			{"drivers":[{"firstName":"Max","lastName":"Verstappen","playerId":"S111"}],"raceNumber":1},
+			{"drivers":[{"firstName":"Charles","lastName":"Leclerc","playerId":"S333"}],"raceNumber":1},
+			{"drivers":[{"firstName":"","lastName":"Piastri","playerId":"S444"}],"raceNumber":81}
+		]}`
+		league.users = `[
+			{"steam64_id":"111","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"},
+			{"steam64_id":"333","username":"charles","first_name":"Charles","last_name":"Leclerc"},
+			{"steam64_id":"444","username":"oscar","first_name":"","last_name":"Piastri"}
+		]`
+
+		msg, err := client.runRoster(sgClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal("⚠️ 2 entered driver(s) with no Discord member. Link them with `!link-driver` or ask them to join the server:\n" +
+			"- #1 Charles Leclerc (linked to <@99>, who is not in the server)\n" +
+			"- #81 player `S444` (SimGrid `oscar`)\n\n" +
+			"⚠️ 1 member(s) hold `Rookies` but are not entered. `!sync-roles --remove-withdrawn` takes it from them:\n" +
+			"- <@43>\n\n" +
+			"⚠️ 1 car number(s) used by more than one entry:\n" +
+			"- #1: Max Verstappen, Charles Leclerc\n\n" +
+			"⚠️ 1 driver(s) with an empty name:\n" +
+			"- #81 player `S444`: first name \"\", last name \"Piastri\""))
+	})
+
+	It("posts issues to the admin channel before race setup", func() {
+		league.users = `[{"steam64_id":"111","username":"max.verstappen","first_name":"Max","last_name":"Verstappen"}]`
+		league.entries = `{"entries":[{"drivers":[{"firstName":"Max","lastName":"Verstappen","playerId":"S111"}],"raceNumber":1}]}`
+
+		client.reportRosterBeforeRaceSetup(sgClient, config.Round{Number: 2, Track: "Imola"})
+		Expect(reported).To(Equal([]string{"**Roster check before race setup for Round 2 - Imola**\n" +
+			"⚠️ 1 member(s) hold `Rookies` but are not entered. `!sync-roles --remove-withdrawn` takes it from them:\n- <@43>"}))
+	})
+
+	It("reports when it cannot check the roster before race setup", func() {
+		stub.getRolesFn = func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+			return nil, nil
+		}
+		client.reportRosterBeforeRaceSetup(sgClient, config.Round{Number: 2, Track: "Imola"})
+		Expect(reported).To(HaveLen(1))
+		Expect(reported[0]).To(HavePrefix("Could not check the roster before race setup for Round 2 - Imola:"))
+	})
+})
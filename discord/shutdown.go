@@ -0,0 +1,108 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	"github.com/geofffranks/rookies-bot/simgrid"
+)
+
+// commandTracker counts the command handlers and scheduled jobs in flight so
+// shutdown can wait for them, and turns new ones away once shutdown starts.
+type commandTracker struct {
+	mu       sync.Mutex
+	draining bool
+	running  sync.WaitGroup
+}
+
+// start records a command starting, or reports false if shutdown has begun
+// and the command should not run.
+func (t *commandTracker) start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return false
+	}
+	t.running.Add(1)
+	return true
+}
+
+// finish records a command started with start finishing.
+func (t *commandTracker) finish() {
+	t.running.Done()
+}
+
+// drain stops new commands from starting and waits for those in flight to
+// finish, for at most timeout or until ctx is done. It reports whether they
+// all finished.
+func (t *commandTracker) drain(ctx context.Context, timeout time.Duration) bool {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.running.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return false
+}
+
+// shutdownMessage answers commands sent while the bot is shutting down.
+const shutdownMessage = "The bot is shutting down. Try again once it's back."
+
+// errShuttingDown fails scheduled jobs that come due during shutdown, leaving
+// the round to run once the bot is back.
+var errShuttingDown = errors.New("the bot is shutting down")
+
+// newSimGridClient returns a SimGrid client for the league whose requests are
+// cancelled when shutdown stops waiting on in-flight commands.
+func (d *DiscordClient) newSimGridClient() *simgrid.SimGridClient {
+	return simgrid.NewClient(d.snapshotConfig().SimGridApiToken).WithContext(d.ctx)
+}
+
+// newGCloudClient connects to Google APIs with calls that are cancelled when
+// shutdown stops waiting on in-flight commands.
+func (d *DiscordClient) newGCloudClient() (*gcloud.Client, error) {
+	return d.newGCloudClientContext(d.ctx)
+}
+
+// newGCloudClientContext connects to Google APIs with calls that are
+// cancelled when ctx is, e.g. a scheduled job's.
+func (d *DiscordClient) newGCloudClientContext(ctx context.Context) (*gcloud.Client, error) {
+	return d.connectGCloud(ctx)
+}
+
+// shutdown stops accepting commands and scheduled jobs and waits for those in
+// flight, up to the configured shutdown timeout or until ctx is done. Whatever
+// is still running then has its SimGrid and Google calls cancelled, as does
+// the rest of the background work.
+func (d *DiscordClient) shutdown(ctx context.Context) {
+	timeout := d.snapshotConfig().ShutdownTimeout
+	if timeout <= 0 {
+		timeout = config.DefaultShutdownTimeout
+	}
+	if !d.commands.drain(ctx, timeout) {
+		fmt.Printf("Commands still running after %s; cancelling them.\n", timeout)
+	}
+	for _, league := range d.leagueClients() {
+		if league.stopBackground != nil {
+			league.stopBackground()
+		}
+	}
+	d.cancel()
+}
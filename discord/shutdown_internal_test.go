@@ -0,0 +1,106 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/geofffranks/rookies-bot/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("shutting down", func() {
+	var tracker *commandTracker
+
+	BeforeEach(func() {
+		tracker = &commandTracker{}
+	})
+
+	It("waits for commands in flight to finish", func() {
+		Expect(tracker.start()).To(BeTrue())
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			tracker.finish()
+		}()
+		Expect(tracker.drain(context.Background(), time.Second)).To(BeTrue())
+	})
+
+	It("turns new commands away once draining starts", func() {
+		Expect(tracker.drain(context.Background(), time.Second)).To(BeTrue())
+		Expect(tracker.start()).To(BeFalse())
+	})
+
+	It("gives up after the timeout or when ctx is done", func() {
+		Expect(tracker.start()).To(BeTrue())
+		Expect(tracker.drain(context.Background(), 10*time.Millisecond)).To(BeFalse())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(tracker.drain(ctx, time.Minute)).To(BeFalse())
+	})
+
+	It("cancels SimGrid calls still running once it stops waiting", func() {
+		client := NewTestDiscordClient(&stubRest{}, snowflakeID(1), &config.Config{BotConfig: config.BotConfig{
+			ShutdownTimeout: 10 * time.Millisecond,
+		}}, nil)
+
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		Expect(client.commands.start()).To(BeTrue())
+		errs := make(chan error, 1)
+		go func() {
+			defer client.commands.finish()
+			sgClient := client.newSimGridClient()
+			sgClient.BaseURL = server.URL
+			_, err := sgClient.GetChampionship("1234")
+			errs <- err
+		}()
+
+		client.shutdown(context.Background())
+		Eventually(errs).Should(Receive(MatchError(ContainSubstring("context canceled"))))
+		Expect(client.commands.start()).To(BeFalse())
+	})
+
+	It("waits for a scheduled job in flight before stopping the background work", func() {
+		client := NewTestDiscordClient(&stubRest{}, snowflakeID(1), &config.Config{BotConfig: config.BotConfig{
+			ShutdownTimeout: time.Second,
+		}}, nil)
+		ctx, cancel := context.WithCancel(client.ctx)
+		client.stopBackground = cancel
+
+		started := make(chan struct{})
+		results := make(chan error, 1)
+		job := client.lockedJob(jobRaceSetup, func(ctx context.Context) (string, error) {
+			close(started)
+			time.Sleep(20 * time.Millisecond)
+			return "", ctx.Err()
+		})
+		go func() {
+			_, err := job(ctx)
+			results <- err
+		}()
+		Eventually(started).Should(BeClosed())
+
+		client.shutdown(context.Background())
+		Expect(results).To(Receive(BeNil()))
+
+		_, err := job(context.Background())
+		Expect(err).To(MatchError(errShuttingDown))
+	})
+
+	It("stops each league's background work", func() {
+		client := NewTestDiscordClient(&stubRest{}, snowflakeID(1), &config.Config{}, nil)
+		stopped := false
+		client.stopBackground = func() { stopped = true }
+		client.shutdown(context.Background())
+		Expect(stopped).To(BeTrue())
+		Expect(client.ctx.Err()).To(MatchError(context.Canceled))
+	})
+})
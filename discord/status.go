@@ -0,0 +1,339 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	"google.golang.org/api/drive/v3"
+)
+
+// statusCheck is the outcome of checking one dependency. A failed check
+// carries a hint on how to fix it.
+type statusCheck struct {
+	name   string
+	detail string
+	err    error
+	hint   string
+}
+
+func (c statusCheck) String() string {
+	if c.err == nil {
+		return fmt.Sprintf("✅ %s: %s", c.name, c.detail)
+	}
+	return fmt.Sprintf("❌ %s: %s\n    ↳ %s", c.name, c.err, c.hint)
+}
+
+func (d *DiscordClient) status(event *events.MessageCreate) {
+	sgClient := d.newSimGridClient()
+	msg, _ := d.runStatus(d.ctx, sgClient)
+	sendBotResponse(event, msg, "")
+}
+
+// Doctor runs the status checks for every league, for the `doctor` CLI
+// subcommand. It reports whether every check passed.
+func (d *DiscordClient) Doctor(ctx context.Context) (string, bool) {
+	var reports []string
+	healthy := true
+	for _, league := range d.leagueClients() {
+		sgClient := league.newSimGridClient().WithContext(ctx)
+		report, ok := league.runStatus(ctx, sgClient)
+		reports = append(reports, report)
+		healthy = healthy && ok
+	}
+	return strings.Join(reports, "\n\n"), healthy
+}
+
+// runStatus describes what the bot is configured to run, then checks that it
+// can reach SimGrid, the Drive templates and folders, and has the Discord
+// permissions race week needs. It never includes tokens or other secrets. It
+// reports whether every check passed.
+func (d *DiscordClient) runStatus(ctx context.Context, sgClient *simgrid.SimGridClient) (string, bool) {
+	conf := d.snapshotConfig()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**rookies-bot status**\n")
+	if conf.League != "" {
+		fmt.Fprintf(&b, "League: %s\n", conf.League)
+	}
+	fmt.Fprintf(&b, "Season: %s\n", conf.Season)
+	fmt.Fprintf(&b, "Championship: %s\n", conf.ChampionshipId)
+	if rc, err := d.loadCurrentRound(); err == nil {
+		fmt.Fprintf(&b, "Next round: %s\n", rc.NextRound)
+	} else {
+		fmt.Fprintf(&b, "Next round: none stored\n")
+	}
+	fmt.Fprintf(&b, "Config: %s\n", d.configPath)
+
+	checks := []statusCheck{d.checkSimGrid(sgClient)}
+	checks = append(checks, d.checkDrive(ctx)...)
+	checks = append(checks, d.checkSharing(ctx)...)
+	checks = append(checks, d.checkDiscord()...)
+
+	healthy := true
+	b.WriteString("\n")
+	for _, check := range checks {
+		b.WriteString(check.String() + "\n")
+		healthy = healthy && check.err == nil
+	}
+	return strings.TrimSuffix(b.String(), "\n"), healthy
+}
+
+func (d *DiscordClient) checkSimGrid(sgClient *simgrid.SimGridClient) statusCheck {
+	check := statusCheck{
+		name: "SimGrid championship",
+		hint: "Check `simgrid_api_token` and `championship_id`, and that SimGrid is up.",
+	}
+	championship, err := sgClient.GetChampionship(d.snapshotConfig().ChampionshipId)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	check.detail = fmt.Sprintf("%q with %d race(s)", championship.Name, len(championship.Races))
+	return check
+}
+
+func (d *DiscordClient) checkDrive(ctx context.Context) []statusCheck {
+	if d.gcloud == nil {
+		return []statusCheck{{
+			name: "Google Drive",
+			err:  fmt.Errorf("not connected"),
+			hint: "Point GOOGLE_APPLICATION_CREDENTIALS at the service account key and restart the bot.",
+		}}
+	}
+	conf := d.snapshotConfig()
+	files := []struct {
+		name  string
+		key   string
+		id    string
+		hint  string
+		check func(context.Context, string) (*drive.File, error)
+	}{
+		{"Briefing template", "briefing_template_doc_id", conf.BriefingTemplateDocID, "Share it with the bot's service account", d.gcloud.CheckTemplate},
+		{"Penalty tracker template", "tracker_template_doc_id", conf.TrackerTemplateDocID, "Share it with the bot's service account", d.gcloud.CheckTemplate},
+		{"Briefing folder", "briefing_folder_id", conf.BriefingFolderID, "Share it with the bot's service account as an editor", d.gcloud.CheckFolder},
+		{"Penalty tracker folder", "tracker_folder_id", conf.TrackerFolderID, "Share it with the bot's service account as an editor", d.gcloud.CheckFolder},
+	}
+
+	var checks []statusCheck
+	for _, file := range files {
+		check := statusCheck{
+			name: file.name,
+			hint: fmt.Sprintf("%s and check `%s`.", file.hint, file.key),
+		}
+		f, err := file.check(ctx, file.id)
+		if err != nil {
+			check.err = err
+		} else {
+			check.detail = fmt.Sprintf("%q", f.Name)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkSharing confirms the briefing doc and penalty tracker the latest race
+// setup generated are still shared as the config's sharing section asks.
+// Nothing is checked for what sharing leaves unset.
+func (d *DiscordClient) checkSharing(ctx context.Context) []statusCheck {
+	if d.gcloud == nil {
+		return nil
+	}
+	conf := d.snapshotConfig()
+	files := []struct {
+		name        string
+		kind        string
+		key         string
+		permissions []*drive.Permission
+	}{
+		{"Briefing sharing", opBriefingDoc, "sharing.briefing_link", gcloud.BriefingPermissions(conf.Sharing)},
+		{"Penalty tracker sharing", opPenaltyTracker, "sharing.tracker_editors", gcloud.TrackerPermissions(conf.Sharing)},
+	}
+
+	var journal *raceSetupJournal
+	var journalErr error
+	if rc, err := d.loadCurrentRound(); err == nil {
+		journalErr = d.state.Load(raceSetupDocument(conf.Season, rc.PreviousRound), &journal)
+	}
+
+	var checks []statusCheck
+	for _, file := range files {
+		if len(file.permissions) == 0 {
+			continue
+		}
+		check := statusCheck{
+			name:   file.name,
+			detail: "nothing generated yet",
+			err:    journalErr,
+			hint:   fmt.Sprintf("Share it in Drive, and check `%s` and that the bot's service account can share files in its folder.", file.key),
+		}
+		if journal != nil && !journal.RolledBack {
+			for _, op := range journal.Operations {
+				if op.Kind != file.kind || op.FileID == "" {
+					continue
+				}
+				check.err = d.gcloud.CheckSharing(ctx, op.FileID, file.permissions)
+				check.detail = fmt.Sprintf("%s shared as configured", journal.Round)
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// permissionNames names the permissions the status checks look for, as
+// Discord's settings show them. disgo doesn't name every permission.
+var permissionNames = map[discord.Permissions]string{
+	discord.PermissionViewChannel:     "View Channel",
+	discord.PermissionSendMessages:    "Send Messages",
+	discord.PermissionPinMessages:     "Pin Messages",
+	discord.PermissionMentionEveryone: "Mention @everyone, @here, and All Roles",
+	discord.PermissionCreateEvents:    "Create Events",
+}
+
+// channelRequirement is a channel the bot posts to and the permissions it
+// needs there.
+type channelRequirement struct {
+	name        string
+	key         string
+	id          snowflake.ID
+	permissions []discord.Permissions
+}
+
+func (d *DiscordClient) checkDiscord() []statusCheck {
+	conf := d.snapshotConfig()
+	setup := statusCheck{
+		name: "Discord server",
+		hint: "Check `discord_channel_id` and that the bot is still in the server.",
+	}
+	guild, err := d.getGuild()
+	if err != nil {
+		setup.err = err
+		return []statusCheck{setup}
+	}
+	self, err := d.rest.GetMember(guild, d.applicationID)
+	if err != nil {
+		setup.err = fmt.Errorf("could not look up the bot's membership: %w", err)
+		return []statusCheck{setup}
+	}
+	roles, err := d.rest.GetRoles(guild)
+	if err != nil {
+		setup.err = fmt.Errorf("could not list roles: %w", err)
+		return []statusCheck{setup}
+	}
+
+	role := statusCheck{
+		name: "Season role",
+		hint: "Check `discord_role_name` matches the role's name exactly.",
+	}
+	var seasonRole *discord.Role
+	for i := range roles {
+		if roles[i].Name == conf.DiscordRoleName {
+			seasonRole = &roles[i]
+		}
+	}
+	if seasonRole == nil {
+		role.err = fmt.Errorf("role %s not found", conf.DiscordRoleName)
+	} else {
+		role.detail = fmt.Sprintf("`%s`", seasonRole.Name)
+	}
+	checks := []statusCheck{role}
+
+	announcement := []discord.Permissions{discord.PermissionViewChannel, discord.PermissionSendMessages, discord.PermissionPinMessages}
+	if seasonRole != nil && !seasonRole.Mentionable {
+		// Only roles anyone can mention can be pinged without this.
+		announcement = append(announcement, discord.PermissionMentionEveryone)
+	}
+	requirements := []channelRequirement{
+		{"Announcement channel", "discord_channel_id", conf.DiscordChannelId, announcement},
+		{"Briefing channel", "discord_briefing_channel_id", conf.DiscordBriefingChannelId,
+			[]discord.Permissions{discord.PermissionViewChannel, discord.PermissionCreateEvents}},
+	}
+	if conf.DiscordAdminChannelId != 0 {
+		requirements = append(requirements, channelRequirement{"Admin channel", "discord_admin_channel_id", conf.DiscordAdminChannelId,
+			[]discord.Permissions{discord.PermissionViewChannel, discord.PermissionSendMessages}})
+	}
+	for _, requirement := range requirements {
+		checks = append(checks, d.checkChannel(guild, *self, roles, requirement))
+	}
+	return checks
+}
+
+// checkChannel checks the bot has every permission requirement lists in its
+// channel.
+func (d *DiscordClient) checkChannel(guild snowflake.ID, self discord.Member, roles []discord.Role, requirement channelRequirement) statusCheck {
+	check := statusCheck{
+		name: requirement.name,
+		hint: fmt.Sprintf("Check `%s`.", requirement.key),
+	}
+	if requirement.id == 0 {
+		check.err = fmt.Errorf("not configured")
+		return check
+	}
+	channel, err := d.rest.GetChannel(requirement.id)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	guildChannel, ok := channel.(discord.GuildChannel)
+	if !ok || guildChannel.GuildID() != guild {
+		check.err = fmt.Errorf("<#%s> is not in the league's server", requirement.id)
+		return check
+	}
+
+	granted := channelPermissions(guild, self, roles, guildChannel)
+	var missing []string
+	for _, permission := range requirement.permissions {
+		if !granted.Has(permission) {
+			missing = append(missing, permissionNames[permission])
+		}
+	}
+	if len(missing) > 0 {
+		check.err = fmt.Errorf("missing %s in <#%s>", strings.Join(missing, ", "), requirement.id)
+		check.hint = fmt.Sprintf("Give the bot's role %s in <#%s>, or check `%s`.", strings.Join(missing, ", "), requirement.id, requirement.key)
+		return check
+	}
+	check.detail = fmt.Sprintf("<#%s>", requirement.id)
+	return check
+}
+
+// channelPermissions works out member's permissions in channel from its
+// roles and the channel's overwrites, the way Discord does.
+func channelPermissions(guild snowflake.ID, member discord.Member, roles []discord.Role, channel discord.GuildChannel) discord.Permissions {
+	var permissions discord.Permissions
+	for _, role := range roles {
+		// The @everyone role shares the guild's ID.
+		if role.ID == guild || slices.Contains(member.RoleIDs, role.ID) {
+			permissions = permissions.Add(role.Permissions)
+		}
+	}
+	if permissions.Has(discord.PermissionAdministrator) {
+		return discord.PermissionsAll
+	}
+
+	// Each layer's denies go before its allows, and later layers win: the
+	// @everyone overwrite, then the member's role overwrites together, then
+	// the member's own overwrite.
+	overwrites := channel.PermissionOverwrites()
+	if overwrite, ok := overwrites.Role(guild); ok {
+		permissions = permissions.Remove(overwrite.Deny).Add(overwrite.Allow)
+	}
+	var allow, deny discord.Permissions
+	for _, roleID := range member.RoleIDs {
+		if overwrite, ok := overwrites.Role(roleID); ok {
+			allow = allow.Add(overwrite.Allow)
+			deny = deny.Add(overwrite.Deny)
+		}
+	}
+	permissions = permissions.Remove(deny).Add(allow)
+	if overwrite, ok := overwrites.Member(member.User.ID); ok {
+		permissions = permissions.Remove(overwrite.Deny).Add(overwrite.Allow)
+	}
+	return permissions
+}
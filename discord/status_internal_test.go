@@ -0,0 +1,217 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	"github.com/geofffranks/rookies-bot/gcloud/fakes"
+	"github.com/geofffranks/rookies-bot/simgrid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/drive/v3"
+)
+
+// guildTextChannel builds a text channel in guild with the given permission
+// overwrites, as Discord would return it.
+func guildTextChannel(id, guild snowflake.ID, overwrites string) dgo.GuildTextChannel {
+	var channel dgo.GuildTextChannel
+	Expect(json.Unmarshal([]byte(fmt.Sprintf(`{"id":"%s","type":0,"guild_id":"%s","permission_overwrites":%s}`, id, guild, overwrites)), &channel)).To(Succeed())
+	return channel
+}
+
+var _ = Describe("status checks", func() {
+	var (
+		league     *testLeague
+		client     *DiscordClient
+		stub       *stubRest
+		sgClient   *simgrid.SimGridClient
+		fakeDrive  *fakes.FakeDriveServicer
+		overwrites map[snowflake.ID]string
+	)
+
+	guild := snowflakeID(10)
+	botRole := snowflakeID(900)
+
+	BeforeEach(func() {
+		fakeDrive = &fakes.FakeDriveServicer{}
+		fakeDrive.GetFileCalls(func(ctx context.Context, id string) (*drive.File, error) {
+			if id == "briefing-folder" || id == "tracker-folder" {
+				return &drive.File{Name: id, MimeType: "application/vnd.google-apps.folder", Capabilities: &drive.FileCapabilities{CanAddChildren: true}}, nil
+			}
+			return &drive.File{Name: id, Capabilities: &drive.FileCapabilities{CanCopy: true}}, nil
+		})
+
+		league = newTestLeague("status", config.BotConfig{
+			SimGridApiToken:          "sg-secret",
+			ChampionshipId:           "1234",
+			BriefingTemplateDocID:    "briefing-template",
+			BriefingFolderID:         "briefing-folder",
+			TrackerTemplateDocID:     "tracker-template",
+			TrackerFolderID:          "tracker-folder",
+			DiscordToken:             "discord-secret",
+			DiscordChannelId:         snowflakeID(111),
+			DiscordBriefingChannelId: snowflakeID(222),
+			DiscordRoleName:          "Rookies",
+		}, &gcloud.Client{Drive: fakeDrive})
+		client, stub, sgClient = league.client, league.stub, league.sgClient
+		client.configPath = "/etc/rookies-bot/config.yml"
+		league.championship = `{"name":"Rookies Fall","races":[{"track":{"name":"Monza"}},{"track":{"name":"Imola"}}]}`
+
+		overwrites = map[snowflake.ID]string{}
+		stub.getChannelFn = func(channelID snowflake.ID, opts ...rest.RequestOpt) (dgo.Channel, error) {
+			o, ok := overwrites[channelID]
+			if !ok {
+				o = "[]"
+			}
+			return guildTextChannel(channelID, guild, o), nil
+		}
+		stub.getMemberFn = func(guildID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) (*dgo.Member, error) {
+			Expect(userID).To(Equal(snowflakeID(1)))
+			return &dgo.Member{User: dgo.User{ID: userID}, RoleIDs: []snowflake.ID{botRole}}, nil
+		}
+		stub.getRolesFn = func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+			return []dgo.Role{
+				{ID: guild, Name: "@everyone", Permissions: dgo.PermissionViewChannel | dgo.PermissionSendMessages},
+				{ID: botRole, Name: "Rookies Bot", Permissions: dgo.PermissionPinMessages | dgo.PermissionMentionEveryone | dgo.PermissionCreateEvents},
+				{ID: snowflakeID(777), Name: "Rookies"},
+			}, nil
+		}
+	})
+	AfterEach(func() {
+		league.close()
+	})
+
+	It("describes the configuration and passes every check", func() {
+		Expect(client.saveCurrentRound(&config.RoundConfig{NextRound: config.Round{Number: 2, Track: "Imola"}})).To(Succeed())
+
+		report, ok := client.runStatus(context.Background(), sgClient)
+		Expect(report).To(Equal("**rookies-bot status**\n" +
+			"Season: 2026 Fall\n" +
+			"Championship: 1234\n" +
+			"Next round: Round 2 - Imola\n" +
+			"Config: /etc/rookies-bot/config.yml\n\n" +
+			"✅ SimGrid championship: \"Rookies Fall\" with 2 race(s)\n" +
+			"✅ Briefing template: \"briefing-template\"\n" +
+			"✅ Penalty tracker template: \"tracker-template\"\n" +
+			"✅ Briefing folder: \"briefing-folder\"\n" +
+			"✅ Penalty tracker folder: \"tracker-folder\"\n" +
+			"✅ Season role: `Rookies`\n" +
+			"✅ Announcement channel: <#111>\n" +
+			"✅ Briefing channel: <#222>"))
+		Expect(ok).To(BeTrue())
+		Expect(report).NotTo(ContainSubstring("secret"))
+	})
+
+	It("reports missing channel permissions with a hint", func() {
+		overwrites[snowflakeID(111)] = fmt.Sprintf(`[{"id":"%s","type":0,"allow":"0","deny":"%d"}]`, botRole, dgo.PermissionPinMessages)
+		overwrites[snowflakeID(222)] = fmt.Sprintf(`[{"id":"%s","type":0,"allow":"0","deny":"%d"}]`, guild, dgo.PermissionViewChannel)
+
+		report, ok := client.runStatus(context.Background(), sgClient)
+		Expect(ok).To(BeFalse())
+		Expect(report).To(ContainSubstring("❌ Announcement channel: missing Pin Messages in <#111>\n" +
+			"    ↳ Give the bot's role Pin Messages in <#111>, or check `discord_channel_id`."))
+		Expect(report).To(ContainSubstring("❌ Briefing channel: missing View Channel in <#222>"))
+	})
+
+	It("reports SimGrid, Drive and role failures with hints", func() {
+		league.sgServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		fakeDrive.GetFileCalls(func(ctx context.Context, id string) (*drive.File, error) {
+			return nil, errors.New("404 file not found")
+		})
+		stub.getRolesFn = func(guildID snowflake.ID, opts ...rest.RequestOpt) ([]dgo.Role, error) {
+			return nil, nil
+		}
+
+		report, ok := client.runStatus(context.Background(), sgClient)
+		Expect(ok).To(BeFalse())
+		Expect(report).To(ContainSubstring("Next round: none stored"))
+		Expect(report).To(ContainSubstring("❌ SimGrid championship: "))
+		Expect(report).To(ContainSubstring("↳ Check `simgrid_api_token` and `championship_id`, and that SimGrid is up."))
+		Expect(report).To(ContainSubstring("❌ Briefing template: 404 file not found\n    ↳ Share it with the bot's service account and check `briefing_template_doc_id`."))
+		Expect(report).To(ContainSubstring("❌ Penalty tracker folder: 404 file not found\n    ↳ Share it with the bot's service account as an editor and check `tracker_folder_id`."))
+		Expect(report).To(ContainSubstring("❌ Season role: role Rookies not found"))
+		Expect(report).NotTo(ContainSubstring("secret"))
+	})
+
+	It("reports when Google APIs are not connected", func() {
+		client.gcloud = nil
+		report, ok := client.runStatus(context.Background(), sgClient)
+		Expect(ok).To(BeFalse())
+		Expect(report).To(ContainSubstring("❌ Google Drive: not connected"))
+	})
+
+	It("checks the latest race setup's files are shared as configured", func() {
+		client.conf.Sharing = config.Sharing{BriefingLink: config.LinkRoleReader, TrackerEditors: []string{"steward@example.com"}}
+
+		report, ok := client.runStatus(context.Background(), sgClient)
+		Expect(ok).To(BeTrue())
+		Expect(report).To(ContainSubstring("✅ Briefing sharing: nothing generated yet\n" +
+			"✅ Penalty tracker sharing: nothing generated yet\n"))
+
+		round := config.Round{Number: 1, Track: "Monza"}
+		Expect(client.saveCurrentRound(&config.RoundConfig{PreviousRound: round, NextRound: config.Round{Number: 2, Track: "Imola"}})).To(Succeed())
+		Expect(client.state.Save(raceSetupDocument("2026 Fall", round), &raceSetupJournal{
+			Round: round,
+			Operations: []setupOperation{
+				{Kind: opBriefingDoc, FileID: "briefing-doc"},
+				{Kind: opPenaltyTracker, FileID: "tracker"},
+			},
+		})).To(Succeed())
+		fakeDrive.ListPermissionsCalls(func(ctx context.Context, id string) ([]*drive.Permission, error) {
+			if id == "briefing-doc" {
+				return []*drive.Permission{{Type: "anyone", Role: "reader"}}, nil
+			}
+			return []*drive.Permission{{Type: "user", Role: "reader", EmailAddress: "Steward@example.com"}}, nil
+		})
+
+		report, ok = client.runStatus(context.Background(), sgClient)
+		Expect(ok).To(BeFalse())
+		Expect(report).To(ContainSubstring("✅ Briefing sharing: Round 1 - Monza shared as configured\n"))
+		Expect(report).To(ContainSubstring("❌ Penalty tracker sharing: not shared with steward@example.com as editor\n" +
+			"    ↳ Share it in Drive, and check `sharing.tracker_editors` and that the bot's service account can share files in its folder."))
+	})
+
+	It("checks the admin channel when one is configured", func() {
+		client.conf.DiscordAdminChannelId = snowflakeID(333)
+		overwrites[snowflakeID(333)] = fmt.Sprintf(`[{"id":"%s","type":1,"allow":"0","deny":"%d"}]`, snowflakeID(1), dgo.PermissionSendMessages)
+
+		report, _ := client.runStatus(context.Background(), sgClient)
+		Expect(report).To(ContainSubstring("❌ Admin channel: missing Send Messages in <#333>"))
+	})
+
+	It("runs the checks for every league from the doctor", func() {
+		report, ok := client.Doctor(context.Background())
+		Expect(ok).To(BeFalse())
+		Expect(report).To(HavePrefix("**rookies-bot status**\nSeason: 2026 Fall"))
+	})
+
+	It("grants administrators everything", func() {
+		member := dgo.Member{User: dgo.User{ID: snowflakeID(1)}, RoleIDs: []snowflake.ID{botRole}}
+		roles := []dgo.Role{{ID: botRole, Permissions: dgo.PermissionAdministrator}}
+		channel := guildTextChannel(snowflakeID(111), guild, fmt.Sprintf(`[{"id":"%s","type":0,"allow":"0","deny":"%d"}]`, guild, dgo.PermissionViewChannel))
+		Expect(channelPermissions(guild, member, roles, channel)).To(Equal(dgo.PermissionsAll))
+	})
+
+	It("lets the member's own overwrites beat their roles' overwrites", func() {
+		member := dgo.Member{User: dgo.User{ID: snowflakeID(1)}, RoleIDs: []snowflake.ID{botRole}}
+		roles := []dgo.Role{{ID: guild, Permissions: dgo.PermissionViewChannel}}
+
+		channel := guildTextChannel(snowflakeID(111), guild, fmt.Sprintf(`[{"id":"%s","type":0,"allow":"%d","deny":"0"},{"id":"%s","type":1,"allow":"0","deny":"%d"}]`,
+			botRole, dgo.PermissionSendMessages, snowflakeID(1), dgo.PermissionSendMessages))
+		Expect(channelPermissions(guild, member, roles, channel).Has(dgo.PermissionSendMessages)).To(BeFalse())
+
+		channel = guildTextChannel(snowflakeID(111), guild, fmt.Sprintf(`[{"id":"%s","type":0,"allow":"0","deny":"%d"},{"id":"%s","type":1,"allow":"%d","deny":"0"}]`,
+			botRole, dgo.PermissionViewChannel, snowflakeID(1), dgo.PermissionViewChannel))
+		Expect(channelPermissions(guild, member, roles, channel).Has(dgo.PermissionViewChannel)).To(BeTrue())
+	})
+})
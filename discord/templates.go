@@ -0,0 +1,213 @@
+package discord
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/models"
+	"github.com/geofffranks/rookies-bot/simgrid"
+)
+
+// defaultTemplates are the built-in announcement templates. Any of them can be
+// overridden by a file of the same name in BotConfig.TemplatesDir.
+//
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Template names, matching the file names under templates/.
+const (
+	templatePenaltyList      = "penalty_list.tmpl"
+	templatePenalties        = "penalties.tmpl"
+	templateRaceDay          = "race_day.tmpl"
+	templateHelp             = "help.tmpl"
+	templateNewSeasonApplied = "new_season_applied.tmpl"
+	templatePenaltyDM        = "penalty_dm.tmpl"
+	templateReminder         = "reminder.tmpl"
+	templatePenaltyAmendedDM = "penalty_amended_dm.tmpl"
+)
+
+// AnnouncementData is the data model for penalties.tmpl, race_day.tmpl and
+// penalty_list.tmpl.
+type AnnouncementData struct {
+	// Round is the upcoming round being raced.
+	Round config.Round
+	// PreviousRound is the round whose stewarding produced the penalties.
+	PreviousRound config.Round
+	// Season is the configured season, e.g. "2026 Fall".
+	Season string
+	// RoleMention mentions the season role, e.g. "<@&123>". Race day only.
+	RoleMention string
+	// BriefingTime is when the drivers' briefing starts. Race day only.
+	BriefingTime time.Time
+	// BriefingTimestamp is BriefingTime as a Discord timestamp, e.g. "<t:1700000000>".
+	BriefingTimestamp string
+	// BriefingDocURL links the generated briefing doc. Race day only.
+	BriefingDocURL string
+	// TrackerURL links the previous round's penalty tracker.
+	TrackerURL string
+	// Penalties lists each penalty category in announcement order. Each has a
+	// Title and Lines, one per driver (a mention, or "#42 First Last" when
+	// the driver is not in the guild). Lines is empty when nobody is serving
+	// that penalty.
+	Penalties []penaltySection
+}
+
+// helpTopics are the topics `!help <topic>` shows, each short enough for
+// one Discord message.
+var helpTopics = []string{"checks", "race", "schedule", "drivers", "season"}
+
+// HelpData is the data model for help.tmpl.
+type HelpData struct {
+	// Topic is one of helpTopics, or "" for the overview listing them.
+	Topic string
+}
+
+// NewSeasonData is the data model for new_season_applied.tmpl.
+type NewSeasonData struct {
+	Season           string
+	Championship     *simgrid.Championship
+	RoleName         string
+	Round1Track      string
+	BriefingFolderID string
+	TrackerFolderID  string
+}
+
+// messages renders bot announcements from text/template files.
+type messages struct {
+	tmpl *template.Template
+}
+
+// defaultMessages returns the built-in templates.
+func defaultMessages() *messages {
+	return &messages{tmpl: template.Must(template.New("").ParseFS(defaultTemplates, "templates/*.tmpl"))}
+}
+
+// loadMessages starts from the built-in templates and overlays each template
+// found in dir. An override that fails to parse or to render sample data is
+// reported and the built-in template is kept in its place.
+func loadMessages(dir string) (*messages, []error) {
+	m := defaultMessages()
+	if dir == "" {
+		return m, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return m, []error{fmt.Errorf("failed reading templates directory %s, using built-in templates: %w", dir, err)}
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".tmpl" {
+			continue
+		}
+		if m.tmpl.Lookup(name) == nil {
+			errs = append(errs, fmt.Errorf("ignoring unknown template %s", filepath.Join(dir, name)))
+			continue
+		}
+
+		candidate, err := m.tmpl.Clone()
+		if err == nil {
+			_, err = candidate.ParseFiles(filepath.Join(dir, name))
+		}
+		if err == nil {
+			err = validateTemplates(candidate)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("using built-in %s: %w", name, err))
+			continue
+		}
+		m.tmpl = candidate
+	}
+	return m, errs
+}
+
+// validateTemplates renders every known template against sample data so that
+// references to missing fields are caught at startup rather than race day.
+func validateTemplates(tmpl *template.Template) error {
+	briefing := time.Date(2026, time.January, 5, 19, 30, 0, 0, time.UTC)
+	announcement := AnnouncementData{
+		Round:             config.Round{Number: 5, Track: "Monza"},
+		PreviousRound:     config.Round{Number: 4, Track: "Spa", PenaltyTrackerLink: "https://docs.google.com/spreadsheets/d/tracker"},
+		Season:            "2026 Winter",
+		RoleMention:       "<@&1>",
+		BriefingTime:      briefing,
+		BriefingTimestamp: fmt.Sprintf("<t:%d>", briefing.Unix()),
+		BriefingDocURL:    "https://docs.google.com/document/d/briefing",
+		TrackerURL:        "https://docs.google.com/spreadsheets/d/tracker",
+		Penalties: []penaltySection{
+			{Title: "Quali Bans R1", Lines: []string{"<@2> (carried over)", "#42 First Last"}},
+			{Title: "Pit Starts R1"},
+		},
+	}
+	newSeason := NewSeasonData{
+		Season:           "2026 Winter",
+		Championship:     &simgrid.Championship{ID: 1, Name: "GT4 Rookies - Winter"},
+		RoleName:         "GT4 Rookies Winter",
+		Round1Track:      "Bathurst",
+		BriefingFolderID: "briefing-folder",
+		TrackerFolderID:  "tracker-folder",
+	}
+
+	penaltyDM := PenaltyDMData{
+		Round:         announcement.Round,
+		PreviousRound: announcement.PreviousRound,
+		Driver:        models.Driver{FirstName: "First", LastName: "Last", CarNumber: 42},
+		Penalties:     []driverPenalty{{Title: "Quali Bans R1", CarriedOver: true}, {Title: "Pit Starts R2"}},
+		TrackerURL:    announcement.TrackerURL,
+	}
+
+	amendedDM := PenaltyAmendedDMData{
+		Round:         announcement.Round,
+		PreviousRound: announcement.PreviousRound,
+		Driver:        penaltyDM.Driver,
+		Reason:        "contact in turn 1 was a racing incident",
+		Added:         []driverPenalty{{Title: "Pit Starts R1"}},
+		Removed:       []driverPenalty{{Title: "Quali Bans R1"}},
+		TrackerURL:    announcement.TrackerURL,
+	}
+
+	reminderData := ReminderData{
+		Round:          announcement.Round,
+		Season:         announcement.Season,
+		RoleMention:    announcement.RoleMention,
+		Event:          reminderBriefing,
+		EventTime:      briefing,
+		EventTimestamp: fmt.Sprintf("<t:%d:R>", briefing.Unix()),
+		BriefingDocURL: announcement.BriefingDocURL,
+		Penalties:      announcement.Penalties[:1],
+	}
+
+	samples := map[string]any{
+		templatePenaltyList:      announcement,
+		templatePenalties:        announcement,
+		templateRaceDay:          announcement,
+		templateHelp:             HelpData{},
+		templateNewSeasonApplied: newSeason,
+		templatePenaltyDM:        penaltyDM,
+		templateReminder:         reminderData,
+		templatePenaltyAmendedDM: amendedDM,
+	}
+	for name, data := range samples {
+		if err := tmpl.ExecuteTemplate(io.Discard, name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *messages) render(name string, data any) (string, error) {
+	var b strings.Builder
+	if err := m.tmpl.ExecuteTemplate(&b, name, data); err != nil {
+		return "", fmt.Errorf("failed rendering %s: %w", name, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,74 @@
+package discord
+
+import (
+	"os"
+
+	"github.com/geofffranks/rookies-bot/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("loadMessages", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "rookies-bot-templates-test")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("uses the built-in templates when no directory is configured", func() {
+		m, errs := loadMessages("")
+		Expect(errs).To(BeEmpty())
+		Expect(validateTemplates(m.tmpl)).To(Succeed())
+	})
+
+	It("overrides a built-in template with a valid file of the same name", func() {
+		Expect(os.WriteFile(tmpDir+"/penalties.tmpl", []byte(`Round {{.PreviousRound.Number}} verdicts:{{template "penalty_list.tmpl" .}}`), 0600)).To(Succeed())
+		m, errs := loadMessages(tmpDir)
+		Expect(errs).To(BeEmpty())
+
+		msg, err := m.render(templatePenalties, AnnouncementData{PreviousRound: config.Round{Number: 7}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(HavePrefix("Round 7 verdicts:"))
+	})
+
+	It("falls back to the built-in template when an override does not parse", func() {
+		Expect(os.WriteFile(tmpDir+"/help.tmpl", []byte(`{{ .Broken `), 0600)).To(Succeed())
+		m, errs := loadMessages(tmpDir)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Error()).To(ContainSubstring("help.tmpl"))
+
+		msg, err := m.render(templateHelp, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("!race-setup"))
+	})
+
+	It("falls back to the built-in template when an override references an unknown field", func() {
+		Expect(os.WriteFile(tmpDir+"/race_day.tmpl", []byte(`{{ .NoSuchField }}`), 0600)).To(Succeed())
+		m, errs := loadMessages(tmpDir)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Error()).To(ContainSubstring("race_day.tmpl"))
+
+		msg, err := m.render(templateRaceDay, AnnouncementData{RoleMention: "<@&9>"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(ContainSubstring("It's Race Day"))
+	})
+
+	It("reports unknown template files", func() {
+		Expect(os.WriteFile(tmpDir+"/bogus.tmpl", []byte(`hi`), 0600)).To(Succeed())
+		_, errs := loadMessages(tmpDir)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Error()).To(ContainSubstring("unknown template"))
+	})
+
+	It("reports a missing templates directory and keeps the defaults", func() {
+		m, errs := loadMessages(tmpDir + "/missing")
+		Expect(errs).To(HaveLen(1))
+		Expect(m.tmpl.Lookup(templateRaceDay)).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,254 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+)
+
+// threadsDocument is the state document holding each round's discussion and
+// stewards threads.
+const threadsDocument = "threads.yml"
+
+// Kinds of round thread.
+const (
+	threadDiscussion = "discussion"
+	threadStewards   = "stewards"
+)
+
+// roundThreads are the threads race setup opened for one round: a public
+// discussion thread on the race-day announcement, and a private thread for
+// the stewards, where amendment reasons, absence penalties and drivers the
+// bot can't mention are posted.
+type roundThreads struct {
+	Season       string       `yaml:"season"`
+	Round        config.Round `yaml:"round"`
+	DiscussionID snowflake.ID `yaml:"discussion_id,omitempty"`
+	StewardsID   snowflake.ID `yaml:"stewards_id,omitempty"`
+	Archived     bool         `yaml:"archived,omitempty"`
+}
+
+// threadIndex holds every round's threads, keyed by roundKey.
+type threadIndex struct {
+	Rounds map[string]*roundThreads `yaml:"rounds"`
+}
+
+// id returns the thread of the given kind, or zero if there is none.
+func (t *roundThreads) id(kind string) snowflake.ID {
+	if t == nil {
+		return 0
+	}
+	if kind == threadStewards {
+		return t.StewardsID
+	}
+	return t.DiscussionID
+}
+
+// createRoundThreads opens the discussion thread on announcement and the
+// private stewards thread for the round being set up, then archives the
+// threads of earlier rounds, journaling both for rollback. Threads already
+// opened for the round (e.g. when race setup is re-run) are reused. It returns
+// a note for the admin response; the announcement is already out, so failures
+// don't fail race setup.
+func (d *DiscordClient) createRoundThreads(roundConfig *config.RoundConfig, announcement *discord.Message, journal *setupJournal) string {
+	if d.state.Dir() == "" {
+		// Threads nobody can find again are just clutter.
+		return ""
+	}
+	conf := d.snapshotConfig()
+	key := roundKey(conf.Season, roundConfig.NextRound)
+
+	// The Discord calls happen outside the store lock; only their results
+	// are saved under it.
+	var index threadIndex
+	if err := d.state.Load(threadsDocument, &index); err != nil {
+		return fmt.Sprintf("\n\nCould not load the round threads: %s", err)
+	}
+	threads := roundThreads{Season: conf.Season, Round: roundConfig.NextRound}
+	if existing := index.Rounds[key]; existing != nil {
+		threads = *existing
+	}
+
+	var notes []string
+	if threads.DiscussionID == 0 {
+		thread, err := d.rest.CreateThreadFromMessage(conf.DiscordChannelId, announcement.ID, discord.ThreadCreateFromMessage{
+			Name:                roundConfig.NextRound.String(),
+			AutoArchiveDuration: discord.AutoArchiveDuration1w,
+		})
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("Could not create the discussion thread: %s", err))
+		} else {
+			threads.DiscussionID = thread.ID()
+			journal.record(setupOperation{Kind: opRoundThread, ChannelID: thread.ID(), RoundKey: key})
+		}
+	}
+	if threads.StewardsID == 0 {
+		id, err := d.createStewardsThread(conf, roundConfig.NextRound)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("Could not create the stewards thread: %s", err))
+		}
+		if id != 0 {
+			threads.StewardsID = id
+			journal.record(setupOperation{Kind: opRoundThread, ChannelID: id, RoundKey: key})
+		}
+	}
+	threads.Archived = false
+
+	var archived []string
+	for other, old := range index.Rounds {
+		if other == key || old.Archived {
+			continue
+		}
+		if err := d.archiveRoundThreads(old); err != nil {
+			notes = append(notes, fmt.Sprintf("Could not archive the %s threads: %s", old.Round, err))
+			continue
+		}
+		archived = append(archived, other)
+		journal.record(setupOperation{Kind: opArchiveThreads, RoundKey: other})
+	}
+
+	err := d.state.Update(threadsDocument, &index, func() error {
+		if index.Rounds == nil {
+			index.Rounds = map[string]*roundThreads{}
+		}
+		index.Rounds[key] = &threads
+		for _, other := range archived {
+			if old := index.Rounds[other]; old != nil {
+				old.Archived = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("Could not save the round's threads: %s", err))
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return "\n\n" + strings.Join(notes, "\n")
+}
+
+// createStewardsThread opens a private thread for the round in the admin
+// channel, or the announcement channel without one, and adds the admins.
+func (d *DiscordClient) createStewardsThread(conf config.BotConfig, round config.Round) (snowflake.ID, error) {
+	channel := conf.DiscordAdminChannelId
+	if channel == 0 {
+		channel = conf.DiscordChannelId
+	}
+	invitable := false
+	thread, err := d.rest.CreateThread(channel, discord.GuildPrivateThreadCreate{
+		Name:                fmt.Sprintf("%s stewards", round),
+		AutoArchiveDuration: discord.AutoArchiveDuration1w,
+		Invitable:           &invitable,
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range adminUsers {
+		if err := d.rest.AddThreadMember(thread.ID(), id); err != nil {
+			return thread.ID(), fmt.Errorf("failed adding <@%s>: %w", id, err)
+		}
+	}
+	return thread.ID(), nil
+}
+
+func (d *DiscordClient) archiveRoundThreads(threads *roundThreads) error {
+	return d.setRoundThreadsArchived(threads, true)
+}
+
+func (d *DiscordClient) setRoundThreadsArchived(threads *roundThreads, archived bool) error {
+	for _, id := range []snowflake.ID{threads.DiscussionID, threads.StewardsID} {
+		if id == 0 {
+			continue
+		}
+		if _, err := d.rest.UpdateChannel(id, discord.GuildThreadUpdate{Archived: &archived}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forgetRoundThread drops a deleted thread from the round with the given
+// roundKey, and the round once it has no threads left.
+func (d *DiscordClient) forgetRoundThread(key string, id snowflake.ID) error {
+	var index threadIndex
+	return d.state.Update(threadsDocument, &index, func() error {
+		threads := index.Rounds[key]
+		if threads == nil {
+			return nil
+		}
+		if threads.DiscussionID == id {
+			threads.DiscussionID = 0
+		}
+		if threads.StewardsID == id {
+			threads.StewardsID = 0
+		}
+		if threads.DiscussionID == 0 && threads.StewardsID == 0 {
+			delete(index.Rounds, key)
+		}
+		return nil
+	})
+}
+
+// reopenRoundThreads unarchives the threads of the round with the given
+// roundKey, returning what it did.
+func (d *DiscordClient) reopenRoundThreads(key string) (string, error) {
+	var index threadIndex
+	if err := d.state.Load(threadsDocument, &index); err != nil {
+		return "", err
+	}
+	threads := index.Rounds[key]
+	if threads == nil {
+		return "", nil
+	}
+	if err := d.setRoundThreadsArchived(threads, false); err != nil {
+		return "", err
+	}
+	err := d.state.Update(threadsDocument, &index, func() error {
+		if threads := index.Rounds[key]; threads != nil {
+			threads.Archived = false
+		}
+		return nil
+	})
+	return fmt.Sprintf("Reopened the %s threads", threads.Round), err
+}
+
+// loadRoundThreads returns the threads race setup opened for round, or nil
+// if it opened none.
+func (d *DiscordClient) loadRoundThreads(season string, round config.Round) (*roundThreads, error) {
+	var index threadIndex
+	if err := d.state.Load(threadsDocument, &index); err != nil {
+		return nil, err
+	}
+	return index.Rounds[roundKey(season, round)], nil
+}
+
+// postToStewards posts text in the round's stewards thread without pinging,
+// or adding to the thread, anyone it mentions. Rounds without a stewards
+// thread are skipped.
+func (d *DiscordClient) postToStewards(round config.Round, text string) error {
+	msg := buildMessage(text)
+	msg.AllowedMentions = &discord.AllowedMentions{}
+	_, err := d.postToRoundThread(d.snapshotConfig().Season, round, threadStewards, msg)
+	return err
+}
+
+// postToRoundThread posts msg in the round's thread of the given kind,
+// reporting false when the round has no such thread.
+func (d *DiscordClient) postToRoundThread(season string, round config.Round, kind string, msg discord.MessageCreate) (bool, error) {
+	threads, err := d.loadRoundThreads(season, round)
+	if err != nil {
+		return false, err
+	}
+	id := threads.id(kind)
+	if id == 0 {
+		return false, nil
+	}
+	if _, err := d.rest.CreateMessage(id, msg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,185 @@
+package discord
+
+import (
+	"errors"
+	"os"
+
+	dgo "github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/geofffranks/rookies-bot/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("round threads", func() {
+	var (
+		client       *DiscordClient
+		stub         *stubRest
+		stateDir     string
+		nextThreadID uint64
+		fromMessage  []dgo.ThreadCreateFromMessage
+		private      map[snowflake.ID]dgo.ThreadCreate
+		members      map[snowflake.ID][]snowflake.ID
+		archived     []snowflake.ID
+		posted       map[snowflake.ID][]string
+		monza, imola *config.RoundConfig
+	)
+
+	thread := func(id uint64) *dgo.GuildThread {
+		channel := dgo.GuildThread{}
+		Expect(channel.UnmarshalJSON([]byte(`{"id":"` + snowflakeID(id).String() + `","type":11}`))).To(Succeed())
+		return &channel
+	}
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = os.MkdirTemp("", "rookies-bot-threads")
+		Expect(err).NotTo(HaveOccurred())
+
+		nextThreadID = 900
+		fromMessage = nil
+		private = map[snowflake.ID]dgo.ThreadCreate{}
+		members = map[snowflake.ID][]snowflake.ID{}
+		archived = nil
+		posted = map[snowflake.ID][]string{}
+		stub = &stubRest{
+			createThreadFromMessageFn: func(channelID snowflake.ID, messageID snowflake.ID, t dgo.ThreadCreateFromMessage, opts ...rest.RequestOpt) (*dgo.GuildThread, error) {
+				Expect(channelID).To(Equal(snowflakeID(111)))
+				Expect(messageID).To(Equal(snowflakeID(42)))
+				fromMessage = append(fromMessage, t)
+				nextThreadID++
+				return thread(nextThreadID), nil
+			},
+			createThreadFn: func(channelID snowflake.ID, t dgo.ThreadCreate, opts ...rest.RequestOpt) (*dgo.GuildThread, error) {
+				nextThreadID++
+				private[channelID] = t
+				return thread(nextThreadID), nil
+			},
+			addThreadMemberFn: func(threadID snowflake.ID, userID snowflake.ID, opts ...rest.RequestOpt) error {
+				members[threadID] = append(members[threadID], userID)
+				return nil
+			},
+			updateChannelFn: func(channelID snowflake.ID, u dgo.ChannelUpdate, opts ...rest.RequestOpt) (dgo.Channel, error) {
+				update := u.(dgo.GuildThreadUpdate)
+				Expect(*update.Archived).To(BeTrue())
+				archived = append(archived, channelID)
+				return dgo.GuildThread{}, nil
+			},
+			createMessageFn: func(channelID snowflake.ID, m dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+				posted[channelID] = append(posted[channelID], m.Content)
+				return &dgo.Message{}, nil
+			},
+		}
+		client = NewTestDiscordClient(stub, snowflakeID(1), &config.Config{BotConfig: config.BotConfig{
+			Season:                "2026 Fall",
+			StateDir:              stateDir,
+			DiscordChannelId:      snowflakeID(111),
+			DiscordAdminChannelId: snowflakeID(222),
+		}}, nil)
+
+		monza = &config.RoundConfig{NextRound: config.Round{Number: 4, Track: "Monza"}}
+		imola = &config.RoundConfig{PreviousRound: monza.NextRound, NextRound: config.Round{Number: 5, Track: "Imola"}}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
+	})
+
+	announcement := &dgo.Message{ID: snowflakeID(42)}
+
+	It("opens a discussion thread on the announcement and a private stewards thread", func() {
+		Expect(client.createRoundThreads(monza, announcement, nil)).To(BeEmpty())
+
+		Expect(fromMessage).To(HaveLen(1))
+		Expect(fromMessage[0].Name).To(Equal("Round 4 - Monza"))
+		Expect(private).To(HaveKey(snowflakeID(222)))
+		stewards := private[snowflakeID(222)].(dgo.GuildPrivateThreadCreate)
+		Expect(stewards.Name).To(Equal("Round 4 - Monza stewards"))
+		Expect(*stewards.Invitable).To(BeFalse())
+		Expect(members[snowflakeID(902)]).To(Equal(adminUsers))
+
+		threads, err := client.loadRoundThreads("2026 Fall", monza.NextRound)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(threads.DiscussionID).To(Equal(snowflakeID(901)))
+		Expect(threads.StewardsID).To(Equal(snowflakeID(902)))
+		Expect(threads.Archived).To(BeFalse())
+	})
+
+	It("reuses the round's threads when race setup is re-run", func() {
+		client.createRoundThreads(monza, announcement, nil)
+		Expect(client.createRoundThreads(monza, announcement, nil)).To(BeEmpty())
+		Expect(fromMessage).To(HaveLen(1))
+		Expect(archived).To(BeEmpty())
+	})
+
+	It("archives earlier rounds' threads when the next round is set up", func() {
+		client.createRoundThreads(monza, announcement, nil)
+		Expect(client.createRoundThreads(imola, announcement, nil)).To(BeEmpty())
+		Expect(archived).To(ConsistOf(snowflakeID(901), snowflakeID(902)))
+
+		threads, err := client.loadRoundThreads("2026 Fall", monza.NextRound)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(threads.Archived).To(BeTrue())
+
+		archived = nil
+		spa := &config.RoundConfig{NextRound: config.Round{Number: 6, Track: "Spa"}}
+		client.createRoundThreads(spa, announcement, nil)
+		Expect(archived).To(ConsistOf(snowflakeID(903), snowflakeID(904)))
+	})
+
+	It("notes failures without losing the threads it did open", func() {
+		stub.createThreadFn = func(channelID snowflake.ID, t dgo.ThreadCreate, opts ...rest.RequestOpt) (*dgo.GuildThread, error) {
+			return nil, errors.New("missing permissions")
+		}
+		Expect(client.createRoundThreads(monza, announcement, nil)).To(ContainSubstring("Could not create the stewards thread: missing permissions"))
+		threads, err := client.loadRoundThreads("2026 Fall", monza.NextRound)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(threads.DiscussionID).To(Equal(snowflakeID(901)))
+		Expect(threads.StewardsID).To(BeZero())
+	})
+
+	It("leaves the state store free while it calls Discord", func() {
+		stub.createThreadFromMessageFn = func(channelID snowflake.ID, messageID snowflake.ID, t dgo.ThreadCreateFromMessage, opts ...rest.RequestOpt) (*dgo.GuildThread, error) {
+			loaded := make(chan error, 1)
+			go func() {
+				var index threadIndex
+				loaded <- client.state.Load(threadsDocument, &index)
+			}()
+			Eventually(loaded).Should(Receive(BeNil()))
+			nextThreadID++
+			return thread(nextThreadID), nil
+		}
+		Expect(client.createRoundThreads(monza, announcement, nil)).To(BeEmpty())
+	})
+
+	It("posts to a round's thread, if it has one", func() {
+		client.createRoundThreads(monza, announcement, nil)
+
+		ok, err := client.postToRoundThread("2026 Fall", monza.NextRound, threadStewards, buildMessage("incident report"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(posted[snowflakeID(902)]).To(Equal([]string{"incident report"}))
+
+		ok, err = client.postToRoundThread("2026 Fall", imola.NextRound, threadDiscussion, buildMessage("hello"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("posts steward notices without pinging or adding anyone", func() {
+		var allowed *dgo.AllowedMentions
+		stub.createMessageFn = func(channelID snowflake.ID, m dgo.MessageCreate, opts ...rest.RequestOpt) (*dgo.Message, error) {
+			posted[channelID] = append(posted[channelID], m.Content)
+			allowed = m.AllowedMentions
+			return &dgo.Message{}, nil
+		}
+		client.createRoundThreads(monza, announcement, nil)
+
+		Expect(client.postToStewards(monza.NextRound, "<@7> missed the briefing")).To(Succeed())
+		Expect(posted[snowflakeID(902)]).To(Equal([]string{"<@7> missed the briefing"}))
+		Expect(allowed).To(Equal(&dgo.AllowedMentions{}))
+
+		Expect(client.postToStewards(imola.NextRound, "no thread")).To(Succeed())
+		Expect(posted).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/events"
+)
+
+func (d *DiscordClient) validateTemplates(event *events.MessageCreate) {
+	msg, _ := d.runValidateTemplates(d.ctx)
+	sendBotResponse(event, msg, "")
+}
+
+// runValidateTemplates checks the briefing and penalty tracker templates
+// still work for race setup: that the bot can copy them into their folders,
+// and that the briefing template marks where the penalties go and uses the
+// configured placeholders and no others. It reports whether every check
+// passed.
+func (d *DiscordClient) runValidateTemplates(ctx context.Context) (string, bool) {
+	checks := d.checkDrive(ctx)
+	if d.gcloud != nil {
+		checks = append(checks, d.checkBriefingContent(ctx)...)
+	}
+
+	var b strings.Builder
+	b.WriteString("**Template check**\n")
+	healthy := true
+	for _, check := range checks {
+		b.WriteString(check.String() + "\n")
+		healthy = healthy && check.err == nil
+	}
+	return strings.TrimSuffix(b.String(), "\n"), healthy
+}
+
+func (d *DiscordClient) checkBriefingContent(ctx context.Context) []statusCheck {
+	conf := d.snapshotConfig()
+	tmpl := conf.BriefingTemplate
+
+	content, err := d.gcloud.CheckBriefingContent(ctx, conf.BriefingTemplateDocID, tmpl)
+	if err != nil {
+		return []statusCheck{{
+			name: "Briefing template contents",
+			err:  err,
+			hint: "Share it with the bot's service account and check `briefing_template_doc_id`.",
+		}}
+	}
+
+	marker := statusCheck{
+		name:   "Penalties marker",
+		detail: content.Marker,
+		hint: fmt.Sprintf("Add a paragraph reading %s, or a named range called %q, where the penalties go in %q.",
+			tmpl.Marker(), tmpl.Range(), content.Name),
+	}
+	if content.Marker == "" {
+		marker.err = fmt.Errorf("not found")
+	}
+	if content.Legacy {
+		marker.err = fmt.Errorf("only the %s marks where the penalties go", content.Marker)
+		marker.hint = fmt.Sprintf("The penalties still go above that heading, but add a paragraph reading %s, or a named range called %q, just above it in %q so the template says where they go.",
+			tmpl.Marker(), tmpl.Range(), content.Name)
+	}
+
+	placeholders := statusCheck{
+		name:   "Placeholders",
+		detail: fmt.Sprintf("all %d used", len(tmpl.PlaceholderList())),
+		hint:   fmt.Sprintf("Put them back in %q, or drop them from `briefing_template.placeholders`.", content.Name),
+	}
+	if len(content.Missing) > 0 {
+		placeholders.err = fmt.Errorf("missing %s", strings.Join(content.Missing, ", "))
+	}
+
+	unknown := statusCheck{
+		name:   "Unknown placeholders",
+		detail: "none",
+		hint:   fmt.Sprintf("Add them to `briefing_template.placeholders`, or take them out of %q.", content.Name),
+	}
+	if len(content.Unknown) > 0 {
+		unknown.err = fmt.Errorf("%s would be left as is", strings.Join(content.Unknown, ", "))
+	}
+	return []statusCheck{marker, placeholders, unknown}
+}
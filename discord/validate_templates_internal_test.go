@@ -0,0 +1,108 @@
+package discord
+
+import (
+	"context"
+	"errors"
+
+	"github.com/geofffranks/rookies-bot/config"
+	"github.com/geofffranks/rookies-bot/gcloud"
+	"github.com/geofffranks/rookies-bot/gcloud/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/drive/v3"
+)
+
+var _ = Describe("validating templates", func() {
+	var (
+		client    *DiscordClient
+		fakeDrive *fakes.FakeDriveServicer
+		fakeDocs  *fakes.FakeDocsServicer
+	)
+
+	BeforeEach(func() {
+		fakeDrive = &fakes.FakeDriveServicer{}
+		fakeDrive.GetFileCalls(func(ctx context.Context, id string) (*drive.File, error) {
+			if id == "briefing-folder" || id == "tracker-folder" {
+				return &drive.File{Name: id, MimeType: "application/vnd.google-apps.folder", Capabilities: &drive.FileCapabilities{CanAddChildren: true}}, nil
+			}
+			return &drive.File{Name: id, Capabilities: &drive.FileCapabilities{CanCopy: true}}, nil
+		})
+
+		doc := makeBriefingDoc()
+		doc.Title = "Briefing Template"
+		doc.Body.Content[0].Paragraph.Elements[0].TextRun.Content = "Round [num] at [Track Name]: [group1] then [group2] at [briefing time], [SEASON]\n"
+		fakeDocs = &fakes.FakeDocsServicer{}
+		fakeDocs.GetDocumentReturns(doc, nil)
+
+		client = NewTestDiscordClient(&stubRest{}, snowflakeID(1), &config.Config{BotConfig: config.BotConfig{
+			BriefingTemplateDocID: "briefing-template",
+			BriefingFolderID:      "briefing-folder",
+			TrackerTemplateDocID:  "tracker-template",
+			TrackerFolderID:       "tracker-folder",
+		}}, &gcloud.Client{Docs: fakeDocs, Drive: fakeDrive})
+	})
+
+	It("passes a template race setup can use", func() {
+		report, ok := client.runValidateTemplates(context.Background())
+		Expect(report).To(Equal("**Template check**\n" +
+			"✅ Briefing template: \"briefing-template\"\n" +
+			"✅ Penalty tracker template: \"tracker-template\"\n" +
+			"✅ Briefing folder: \"briefing-folder\"\n" +
+			"✅ Penalty tracker folder: \"tracker-folder\"\n" +
+			"✅ Penalties marker: \"[penalties]\" paragraph\n" +
+			"✅ Placeholders: all 6 used\n" +
+			"✅ Unknown placeholders: none"))
+		Expect(ok).To(BeTrue())
+		_, id := fakeDocs.GetDocumentArgsForCall(0)
+		Expect(id).To(Equal("briefing-template"))
+	})
+
+	It("reports what an editor broke, with hints", func() {
+		doc := makeBriefingDoc()
+		doc.Title = "Briefing Template"
+		doc.Body.Content[0].Paragraph.Elements[0].TextRun.Content = "Round [num] at [Track]: [group1] then [group2] at [briefing time]\n"
+		doc.Body.Content[1].Paragraph.Elements[0].TextRun.Content = "Penalties go here\n"
+		doc.Body.Content[2].Paragraph.Elements[0].TextRun.Content = "Watch live\n"
+		fakeDocs.GetDocumentReturns(doc, nil)
+
+		report, ok := client.runValidateTemplates(context.Background())
+		Expect(ok).To(BeFalse())
+		Expect(report).To(ContainSubstring("❌ Penalties marker: not found\n" +
+			"    ↳ Add a paragraph reading [penalties], or a named range called \"penalties\", where the penalties go in \"Briefing Template\"."))
+		Expect(report).To(ContainSubstring("❌ Placeholders: missing [Track Name], [SEASON]\n" +
+			"    ↳ Put them back in \"Briefing Template\", or drop them from `briefing_template.placeholders`."))
+		Expect(report).To(ContainSubstring("❌ Unknown placeholders: [Track] would be left as is\n" +
+			"    ↳ Add them to `briefing_template.placeholders`, or take them out of \"Briefing Template\"."))
+	})
+
+	It("asks for a marker in a template that only has the legacy Stream heading", func() {
+		doc := makeBriefingDoc()
+		doc.Title = "Briefing Template"
+		doc.Body.Content[0].Paragraph.Elements[0].TextRun.Content = "Round [num] at [Track Name]: [group1] then [group2] at [briefing time], [SEASON]\n"
+		doc.Body.Content[1].Paragraph.Elements[0].TextRun.Content = "Penalties\n"
+		fakeDocs.GetDocumentReturns(doc, nil)
+
+		report, ok := client.runValidateTemplates(context.Background())
+		Expect(ok).To(BeFalse())
+		Expect(report).To(ContainSubstring("❌ Penalties marker: only the legacy \"Stream\" heading marks where the penalties go\n" +
+			"    ↳ The penalties still go above that heading, but add a paragraph reading [penalties], or a named range called \"penalties\", just above it in \"Briefing Template\" so the template says where they go."))
+		Expect(report).To(ContainSubstring("✅ Placeholders: all 6 used"))
+	})
+
+	It("reports when the briefing template can't be read", func() {
+		fakeDocs.GetDocumentReturns(nil, errors.New("403 forbidden"))
+		report, ok := client.runValidateTemplates(context.Background())
+		Expect(ok).To(BeFalse())
+		Expect(report).To(HaveSuffix("❌ Briefing template contents: 403 forbidden\n" +
+			"    ↳ Share it with the bot's service account and check `briefing_template_doc_id`."))
+	})
+
+	It("reports when Google APIs are not connected", func() {
+		client.gcloud = nil
+		report, ok := client.runValidateTemplates(context.Background())
+		Expect(ok).To(BeFalse())
+		Expect(report).To(Equal("**Template check**\n" +
+			"❌ Google Drive: not connected\n" +
+			"    ↳ Point GOOGLE_APPLICATION_CREDENTIALS at the service account key and restart the bot."))
+	})
+})
@@ -41,6 +41,49 @@ type FakeDriveServicer struct {
 		result1 *drive.File
 		result2 error
 	}
+	CreatePermissionStub        func(context.Context, string, *drive.Permission) error
+	createPermissionMutex       sync.RWMutex
+	createPermissionArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *drive.Permission
+	}
+	createPermissionReturns struct {
+		result1 error
+	}
+	createPermissionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ExportFileStub        func(context.Context, string, string) ([]byte, error)
+	exportFileMutex       sync.RWMutex
+	exportFileArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	exportFileReturns struct {
+		result1 []byte
+		result2 error
+	}
+	exportFileReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	FindFileStub        func(context.Context, string, string) (*drive.File, error)
+	findFileMutex       sync.RWMutex
+	findFileArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	findFileReturns struct {
+		result1 *drive.File
+		result2 error
+	}
+	findFileReturnsOnCall map[int]struct {
+		result1 *drive.File
+		result2 error
+	}
 	FindFolderStub        func(context.Context, string, string) (*drive.File, error)
 	findFolderMutex       sync.RWMutex
 	findFolderArgsForCall []struct {
@@ -70,6 +113,32 @@ type FakeDriveServicer struct {
 		result1 *drive.File
 		result2 error
 	}
+	ListPermissionsStub        func(context.Context, string) ([]*drive.Permission, error)
+	listPermissionsMutex       sync.RWMutex
+	listPermissionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listPermissionsReturns struct {
+		result1 []*drive.Permission
+		result2 error
+	}
+	listPermissionsReturnsOnCall map[int]struct {
+		result1 []*drive.Permission
+		result2 error
+	}
+	TrashFileStub        func(context.Context, string) error
+	trashFileMutex       sync.RWMutex
+	trashFileArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	trashFileReturns struct {
+		result1 error
+	}
+	trashFileReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -207,6 +276,201 @@ func (fake *FakeDriveServicer) CreateFolderReturnsOnCall(i int, result1 *drive.F
 	}{result1, result2}
 }
 
+func (fake *FakeDriveServicer) CreatePermission(arg1 context.Context, arg2 string, arg3 *drive.Permission) error {
+	fake.createPermissionMutex.Lock()
+	ret, specificReturn := fake.createPermissionReturnsOnCall[len(fake.createPermissionArgsForCall)]
+	fake.createPermissionArgsForCall = append(fake.createPermissionArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *drive.Permission
+	}{arg1, arg2, arg3})
+	stub := fake.CreatePermissionStub
+	fakeReturns := fake.createPermissionReturns
+	fake.recordInvocation("CreatePermission", []interface{}{arg1, arg2, arg3})
+	fake.createPermissionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriveServicer) CreatePermissionCallCount() int {
+	fake.createPermissionMutex.RLock()
+	defer fake.createPermissionMutex.RUnlock()
+	return len(fake.createPermissionArgsForCall)
+}
+
+func (fake *FakeDriveServicer) CreatePermissionCalls(stub func(context.Context, string, *drive.Permission) error) {
+	fake.createPermissionMutex.Lock()
+	defer fake.createPermissionMutex.Unlock()
+	fake.CreatePermissionStub = stub
+}
+
+func (fake *FakeDriveServicer) CreatePermissionArgsForCall(i int) (context.Context, string, *drive.Permission) {
+	fake.createPermissionMutex.RLock()
+	defer fake.createPermissionMutex.RUnlock()
+	argsForCall := fake.createPermissionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeDriveServicer) CreatePermissionReturns(result1 error) {
+	fake.createPermissionMutex.Lock()
+	defer fake.createPermissionMutex.Unlock()
+	fake.CreatePermissionStub = nil
+	fake.createPermissionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriveServicer) CreatePermissionReturnsOnCall(i int, result1 error) {
+	fake.createPermissionMutex.Lock()
+	defer fake.createPermissionMutex.Unlock()
+	fake.CreatePermissionStub = nil
+	if fake.createPermissionReturnsOnCall == nil {
+		fake.createPermissionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createPermissionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriveServicer) ExportFile(arg1 context.Context, arg2 string, arg3 string) ([]byte, error) {
+	fake.exportFileMutex.Lock()
+	ret, specificReturn := fake.exportFileReturnsOnCall[len(fake.exportFileArgsForCall)]
+	fake.exportFileArgsForCall = append(fake.exportFileArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ExportFileStub
+	fakeReturns := fake.exportFileReturns
+	fake.recordInvocation("ExportFile", []interface{}{arg1, arg2, arg3})
+	fake.exportFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDriveServicer) ExportFileCallCount() int {
+	fake.exportFileMutex.RLock()
+	defer fake.exportFileMutex.RUnlock()
+	return len(fake.exportFileArgsForCall)
+}
+
+func (fake *FakeDriveServicer) ExportFileCalls(stub func(context.Context, string, string) ([]byte, error)) {
+	fake.exportFileMutex.Lock()
+	defer fake.exportFileMutex.Unlock()
+	fake.ExportFileStub = stub
+}
+
+func (fake *FakeDriveServicer) ExportFileArgsForCall(i int) (context.Context, string, string) {
+	fake.exportFileMutex.RLock()
+	defer fake.exportFileMutex.RUnlock()
+	argsForCall := fake.exportFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeDriveServicer) ExportFileReturns(result1 []byte, result2 error) {
+	fake.exportFileMutex.Lock()
+	defer fake.exportFileMutex.Unlock()
+	fake.ExportFileStub = nil
+	fake.exportFileReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDriveServicer) ExportFileReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.exportFileMutex.Lock()
+	defer fake.exportFileMutex.Unlock()
+	fake.ExportFileStub = nil
+	if fake.exportFileReturnsOnCall == nil {
+		fake.exportFileReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.exportFileReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDriveServicer) FindFile(arg1 context.Context, arg2 string, arg3 string) (*drive.File, error) {
+	fake.findFileMutex.Lock()
+	ret, specificReturn := fake.findFileReturnsOnCall[len(fake.findFileArgsForCall)]
+	fake.findFileArgsForCall = append(fake.findFileArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.FindFileStub
+	fakeReturns := fake.findFileReturns
+	fake.recordInvocation("FindFile", []interface{}{arg1, arg2, arg3})
+	fake.findFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDriveServicer) FindFileCallCount() int {
+	fake.findFileMutex.RLock()
+	defer fake.findFileMutex.RUnlock()
+	return len(fake.findFileArgsForCall)
+}
+
+func (fake *FakeDriveServicer) FindFileCalls(stub func(context.Context, string, string) (*drive.File, error)) {
+	fake.findFileMutex.Lock()
+	defer fake.findFileMutex.Unlock()
+	fake.FindFileStub = stub
+}
+
+func (fake *FakeDriveServicer) FindFileArgsForCall(i int) (context.Context, string, string) {
+	fake.findFileMutex.RLock()
+	defer fake.findFileMutex.RUnlock()
+	argsForCall := fake.findFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeDriveServicer) FindFileReturns(result1 *drive.File, result2 error) {
+	fake.findFileMutex.Lock()
+	defer fake.findFileMutex.Unlock()
+	fake.FindFileStub = nil
+	fake.findFileReturns = struct {
+		result1 *drive.File
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDriveServicer) FindFileReturnsOnCall(i int, result1 *drive.File, result2 error) {
+	fake.findFileMutex.Lock()
+	defer fake.findFileMutex.Unlock()
+	fake.FindFileStub = nil
+	if fake.findFileReturnsOnCall == nil {
+		fake.findFileReturnsOnCall = make(map[int]struct {
+			result1 *drive.File
+			result2 error
+		})
+	}
+	fake.findFileReturnsOnCall[i] = struct {
+		result1 *drive.File
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeDriveServicer) FindFolder(arg1 context.Context, arg2 string, arg3 string) (*drive.File, error) {
 	fake.findFolderMutex.Lock()
 	ret, specificReturn := fake.findFolderReturnsOnCall[len(fake.findFolderArgsForCall)]
@@ -338,9 +602,154 @@ func (fake *FakeDriveServicer) GetFileReturnsOnCall(i int, result1 *drive.File,
 	}{result1, result2}
 }
 
+func (fake *FakeDriveServicer) ListPermissions(arg1 context.Context, arg2 string) ([]*drive.Permission, error) {
+	fake.listPermissionsMutex.Lock()
+	ret, specificReturn := fake.listPermissionsReturnsOnCall[len(fake.listPermissionsArgsForCall)]
+	fake.listPermissionsArgsForCall = append(fake.listPermissionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListPermissionsStub
+	fakeReturns := fake.listPermissionsReturns
+	fake.recordInvocation("ListPermissions", []interface{}{arg1, arg2})
+	fake.listPermissionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDriveServicer) ListPermissionsCallCount() int {
+	fake.listPermissionsMutex.RLock()
+	defer fake.listPermissionsMutex.RUnlock()
+	return len(fake.listPermissionsArgsForCall)
+}
+
+func (fake *FakeDriveServicer) ListPermissionsCalls(stub func(context.Context, string) ([]*drive.Permission, error)) {
+	fake.listPermissionsMutex.Lock()
+	defer fake.listPermissionsMutex.Unlock()
+	fake.ListPermissionsStub = stub
+}
+
+func (fake *FakeDriveServicer) ListPermissionsArgsForCall(i int) (context.Context, string) {
+	fake.listPermissionsMutex.RLock()
+	defer fake.listPermissionsMutex.RUnlock()
+	argsForCall := fake.listPermissionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDriveServicer) ListPermissionsReturns(result1 []*drive.Permission, result2 error) {
+	fake.listPermissionsMutex.Lock()
+	defer fake.listPermissionsMutex.Unlock()
+	fake.ListPermissionsStub = nil
+	fake.listPermissionsReturns = struct {
+		result1 []*drive.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDriveServicer) ListPermissionsReturnsOnCall(i int, result1 []*drive.Permission, result2 error) {
+	fake.listPermissionsMutex.Lock()
+	defer fake.listPermissionsMutex.Unlock()
+	fake.ListPermissionsStub = nil
+	if fake.listPermissionsReturnsOnCall == nil {
+		fake.listPermissionsReturnsOnCall = make(map[int]struct {
+			result1 []*drive.Permission
+			result2 error
+		})
+	}
+	fake.listPermissionsReturnsOnCall[i] = struct {
+		result1 []*drive.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDriveServicer) TrashFile(arg1 context.Context, arg2 string) error {
+	fake.trashFileMutex.Lock()
+	ret, specificReturn := fake.trashFileReturnsOnCall[len(fake.trashFileArgsForCall)]
+	fake.trashFileArgsForCall = append(fake.trashFileArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.TrashFileStub
+	fakeReturns := fake.trashFileReturns
+	fake.recordInvocation("TrashFile", []interface{}{arg1, arg2})
+	fake.trashFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriveServicer) TrashFileCallCount() int {
+	fake.trashFileMutex.RLock()
+	defer fake.trashFileMutex.RUnlock()
+	return len(fake.trashFileArgsForCall)
+}
+
+func (fake *FakeDriveServicer) TrashFileCalls(stub func(context.Context, string) error) {
+	fake.trashFileMutex.Lock()
+	defer fake.trashFileMutex.Unlock()
+	fake.TrashFileStub = stub
+}
+
+func (fake *FakeDriveServicer) TrashFileArgsForCall(i int) (context.Context, string) {
+	fake.trashFileMutex.RLock()
+	defer fake.trashFileMutex.RUnlock()
+	argsForCall := fake.trashFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDriveServicer) TrashFileReturns(result1 error) {
+	fake.trashFileMutex.Lock()
+	defer fake.trashFileMutex.Unlock()
+	fake.TrashFileStub = nil
+	fake.trashFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriveServicer) TrashFileReturnsOnCall(i int, result1 error) {
+	fake.trashFileMutex.Lock()
+	defer fake.trashFileMutex.Unlock()
+	fake.TrashFileStub = nil
+	if fake.trashFileReturnsOnCall == nil {
+		fake.trashFileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.trashFileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeDriveServicer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.copyFileMutex.RLock()
+	defer fake.copyFileMutex.RUnlock()
+	fake.createFolderMutex.RLock()
+	defer fake.createFolderMutex.RUnlock()
+	fake.createPermissionMutex.RLock()
+	defer fake.createPermissionMutex.RUnlock()
+	fake.exportFileMutex.RLock()
+	defer fake.exportFileMutex.RUnlock()
+	fake.findFileMutex.RLock()
+	defer fake.findFileMutex.RUnlock()
+	fake.findFolderMutex.RLock()
+	defer fake.findFolderMutex.RUnlock()
+	fake.getFileMutex.RLock()
+	defer fake.getFileMutex.RUnlock()
+	fake.listPermissionsMutex.RLock()
+	defer fake.listPermissionsMutex.RUnlock()
+	fake.trashFileMutex.RLock()
+	defer fake.trashFileMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -3,7 +3,12 @@ package gcloud
 import (
 	"context"
 	"fmt"
+	"io"
+	"regexp"
+	"slices"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/geofffranks/rookies-bot/config"
 	"github.com/geofffranks/rookies-bot/models"
@@ -16,9 +21,13 @@ import (
 type Client struct {
 	Docs  DocsServicer
 	Drive DriveServicer
+	// ctx cancels the calls made by methods that don't take a context.
+	// Nil means they are never cancelled.
+	ctx context.Context
 }
 
-// NewClient creates a Client using real Google API credentials from the environment.
+// NewClient creates a Client using real Google API credentials from the
+// environment. Its calls are cancelled along with ctx.
 func NewClient(ctx context.Context) (*Client, error) {
 	docsService, err := docs.NewService(ctx)
 	if err != nil {
@@ -31,9 +40,25 @@ func NewClient(ctx context.Context) (*Client, error) {
 	return &Client{
 		Docs:  &realDocsService{svc: docsService},
 		Drive: &realDriveService{svc: driveService},
+		ctx:   ctx,
 	}, nil
 }
 
+// WithContext returns a copy of the client whose calls are cancelled along
+// with ctx.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
 // --- Real adapters (wrap the Google SDK) ---
 
 type realDocsService struct{ svc *docs.Service }
@@ -56,12 +81,12 @@ func (r *realDriveService) CopyFile(ctx context.Context, templateID, folderID, t
 }
 
 func (r *realDriveService) GetFile(ctx context.Context, id string) (*drive.File, error) {
-	return r.svc.Files.Get(id).Fields("id, name, parents").Context(ctx).Do()
+	return r.svc.Files.Get(id).Fields("id, name, parents, mimeType, capabilities(canCopy, canAddChildren)").Context(ctx).Do()
 }
 
 func (r *realDriveService) FindFolder(ctx context.Context, parentID, name string) (*drive.File, error) {
 	escaped := strings.ReplaceAll(name, "'", `\'`)
-	q := fmt.Sprintf("'%s' in parents and name = '%s' and mimeType = 'application/vnd.google-apps.folder' and trashed = false", parentID, escaped)
+	q := fmt.Sprintf("'%s' in parents and name = '%s' and mimeType = '%s' and trashed = false", parentID, escaped, folderMimeType)
 	list, err := r.svc.Files.List().Q(q).Fields("files(id, name)").Context(ctx).Do()
 	if err != nil {
 		return nil, err
@@ -72,42 +97,294 @@ func (r *realDriveService) FindFolder(ctx context.Context, parentID, name string
 	return list.Files[0], nil
 }
 
+func (r *realDriveService) FindFile(ctx context.Context, folderID, name string) (*drive.File, error) {
+	escaped := strings.ReplaceAll(name, "'", `\'`)
+	q := fmt.Sprintf("'%s' in parents and name = '%s' and mimeType != '%s' and trashed = false", folderID, escaped, folderMimeType)
+	list, err := r.svc.Files.List().Q(q).OrderBy("createdTime").Fields("files(id, name)").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Files) == 0 {
+		return nil, nil
+	}
+	return list.Files[0], nil
+}
+
 func (r *realDriveService) CreateFolder(ctx context.Context, parentID, name string) (*drive.File, error) {
 	folder := &drive.File{
 		Name:     name,
 		Parents:  []string{parentID},
-		MimeType: "application/vnd.google-apps.folder",
+		MimeType: folderMimeType,
 	}
 	return r.svc.Files.Create(folder).Fields("id").Context(ctx).Do()
 }
 
+func (r *realDriveService) TrashFile(ctx context.Context, id string) error {
+	_, err := r.svc.Files.Update(id, &drive.File{Trashed: true}).Fields("id").Context(ctx).Do()
+	return err
+}
+
+func (r *realDriveService) ExportFile(ctx context.Context, id, mimeType string) ([]byte, error) {
+	resp, err := r.svc.Files.Export(id, mimeType).Context(ctx).Download()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (r *realDriveService) CreatePermission(ctx context.Context, fileID string, permission *drive.Permission) error {
+	call := r.svc.Permissions.Create(fileID, permission).Fields("id").Context(ctx)
+	if permission.Type == "user" {
+		call = call.SendNotificationEmail(false)
+	}
+	_, err := call.Do()
+	return err
+}
+
+func (r *realDriveService) ListPermissions(ctx context.Context, fileID string) ([]*drive.Permission, error) {
+	list, err := r.svc.Permissions.List(fileID).Fields("permissions(id, type, role, emailAddress)").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return list.Permissions, nil
+}
+
 // --- Methods ---
 
-func (c *Client) GenerateBriefing(conf *config.Config, penalties *models.Penalties) (string, error) {
-	ctx := context.Background()
+// folderMimeType is the MIME type Drive gives folders.
+const folderMimeType = "application/vnd.google-apps.folder"
+
+// penaltiesHeading heads the penalty sections inserted into a briefing doc.
+const penaltiesHeading = "Drivers Serving Penalties Tonight"
+
+// legacyPenaltiesHeading starts the heading that briefing templates from
+// before the penalties marker had the penalty sections inserted above.
+const legacyPenaltiesHeading = "Stream"
+
+// briefingDocURLPrefix is the URL of a briefing doc, without its ID.
+const briefingDocURLPrefix = "https://docs.google.com/document/d/"
+
+// trackerURLPrefix is the URL of a penalty tracker, without its ID.
+const trackerURLPrefix = "https://docs.google.com/spreadsheets/d/"
 
-	briefingFile, err := c.Drive.CopyFile(ctx, conf.BriefingTemplateDocID, conf.BriefingFolderID,
-		fmt.Sprintf("Drivers Briefing Round %d at %s", conf.NextRound.Number, conf.NextRound.Track))
+// BriefingDocID returns the document ID from a briefing doc URL returned by
+// GenerateBriefing.
+func BriefingDocID(url string) string {
+	return strings.TrimPrefix(url, briefingDocURLPrefix)
+}
+
+// TrackerID returns the spreadsheet ID from a penalty tracker URL returned by
+// GeneratePenaltyTracker.
+func TrackerID(url string) string {
+	return strings.TrimPrefix(url, trackerURLPrefix)
+}
+
+// pdfMimeType is the MIME type of a PDF export.
+const pdfMimeType = "application/pdf"
+
+// ExportBriefingPDF exports the briefing doc at url, as returned by
+// GenerateBriefing, as a PDF.
+func (c *Client) ExportBriefingPDF(url string) ([]byte, error) {
+	pdf, err := c.Drive.ExportFile(c.context(), BriefingDocID(url), pdfMimeType)
 	if err != nil {
-		return "", fmt.Errorf("failed to copy Briefing Template to Briefing folder: %s", err)
+		return nil, fmt.Errorf("failed exporting the Briefing Doc as PDF: %s", err)
+	}
+	if len(pdf) == 0 {
+		return nil, fmt.Errorf("failed exporting the Briefing Doc as PDF: Drive returned an empty file")
 	}
+	return pdf, nil
+}
 
-	briefingDoc, err := c.Docs.GetDocument(ctx, briefingFile.Id)
+// TrashFile moves a file the bot created to the Drive trash.
+func (c *Client) TrashFile(id string) error {
+	return c.Drive.TrashFile(c.context(), id)
+}
+
+// GenerateBriefing copies the briefing template for the next round and fills
+// it in, spelling out briefingTime in the schedule's display timezones. The
+// template is checked before it is copied, so one missing its penalties
+// marker or with a placeholder that can't be filled in leaves no copy. The
+// copy is shared as conf.Sharing sets, and trashed when it can't be. When
+// the round's briefing doc is already in the briefing folder it is reused
+// instead, and refilled unless conf.ExistingDocs is ExistingDocsKeep. When
+// filling in the doc fails, its URL is still returned so the caller can clean
+// it up.
+func (c *Client) GenerateBriefing(conf *config.Config, penalties *models.Penalties, briefingTime time.Time) (string, error) {
+	ctx := c.context()
+	title := fmt.Sprintf("Drivers Briefing Round %d at %s", conf.NextRound.Number, conf.NextRound.Track)
+
+	briefingFile, err := c.Drive.FindFile(ctx, conf.BriefingFolderID, title)
 	if err != nil {
-		return "", fmt.Errorf("failed getting Briefing Doc: %s", err)
+		return "", fmt.Errorf("failed searching the Briefing folder for %q: %s", title, err)
+	}
+	if briefingFile != nil && conf.ExistingDocs == config.ExistingDocsKeep {
+		return briefingDocURLPrefix + briefingFile.Id, nil
+	}
+	if briefingFile == nil {
+		templateDoc, err := c.Docs.GetDocument(ctx, conf.BriefingTemplateDocID)
+		if err != nil {
+			return "", fmt.Errorf("failed getting Briefing Template: %s", err)
+		}
+		if _, err := generateUpdates(conf, penalties, templateDoc, briefingTime); err != nil {
+			return "", fmt.Errorf("failed processing Briefing Template: %s", err)
+		}
+		briefingFile, err = c.Drive.CopyFile(ctx, conf.BriefingTemplateDocID, conf.BriefingFolderID, title)
+		if err != nil {
+			return "", fmt.Errorf("failed to copy Briefing Template to Briefing folder: %s", err)
+		}
+		if err := c.share(ctx, briefingFile.Id, BriefingPermissions(conf.Sharing)); err != nil {
+			// Left in the folder, the unshared copy would be reused next run.
+			if trashErr := c.Drive.TrashFile(ctx, briefingFile.Id); trashErr != nil {
+				return briefingDocURLPrefix + briefingFile.Id, fmt.Errorf("failed sharing the Briefing Doc: %s (and could not trash the copy: %s)", err, trashErr)
+			}
+			return "", fmt.Errorf("failed sharing the Briefing Doc: %s", err)
+		}
 	}
 
-	updates, err := generateUpdates(conf, penalties, briefingDoc)
+	url := briefingDocURLPrefix + briefingFile.Id
+
+	briefingDoc, err := c.Docs.GetDocument(ctx, briefingFile.Id)
 	if err != nil {
-		return "", fmt.Errorf("failed processing Briefing Template: %s", err)
+		return url, fmt.Errorf("failed getting Briefing Doc: %s", err)
+	}
+
+	var updates *docs.BatchUpdateDocumentRequest
+	if start, end, ok := penaltySections(briefingDoc); ok {
+		// An earlier run filled the doc in, so only its penalties are stale.
+		updates = &docs.BatchUpdateDocumentRequest{Requests: replacePenaltyRequests(start, end, penalties)}
+	} else {
+		updates, err = generateUpdates(conf, penalties, briefingDoc, briefingTime)
+		if err != nil {
+			return url, fmt.Errorf("failed processing Briefing Template: %s", err)
+		}
 	}
 
 	_, err = c.Docs.BatchUpdateDocument(ctx, briefingFile.Id, updates)
 	if err != nil {
-		return "", fmt.Errorf("could not update the Briefing Doc: %s", err)
+		return url, fmt.Errorf("could not update the Briefing Doc: %s", err)
+	}
+
+	return url, nil
+}
+
+// UpdateBriefingPenalties replaces the penalty sections GenerateBriefing
+// inserted into the briefing doc with penalties.
+func (c *Client) UpdateBriefingPenalties(docID string, penalties *models.Penalties) error {
+	ctx := c.context()
+
+	briefingDoc, err := c.Docs.GetDocument(ctx, docID)
+	if err != nil {
+		return fmt.Errorf("failed getting Briefing Doc: %s", err)
+	}
+
+	sectionsStart, sectionsEnd, ok := penaltySections(briefingDoc)
+	if !ok {
+		return fmt.Errorf("could not find the %q section in the Briefing Doc", penaltiesHeading)
+	}
+
+	requests := replacePenaltyRequests(sectionsStart, sectionsEnd, penalties)
+	_, err = c.Docs.BatchUpdateDocument(ctx, docID, &docs.BatchUpdateDocumentRequest{Requests: requests})
+	if err != nil {
+		return fmt.Errorf("could not update the Briefing Doc: %s", err)
+	}
+	return nil
+}
+
+// penaltySections finds the penalty sections GenerateBriefing inserted into
+// doc: their heading and the subheadings and bulleted entries following it.
+// It reports false when the doc has none.
+func penaltySections(doc *docs.Document) (start, end int64, ok bool) {
+	start = -1
+	for _, elem := range doc.Body.Content {
+		if start < 0 {
+			if strings.HasPrefix(headingText(elem, "HEADING_3"), penaltiesHeading) {
+				start = elem.StartIndex
+			}
+			continue
+		}
+		p := elem.Paragraph
+		if p == nil || (p.Bullet == nil && (p.ParagraphStyle == nil || p.ParagraphStyle.NamedStyleType != "HEADING_4")) {
+			return start, elem.StartIndex, true
+		}
+		end = elem.EndIndex
+	}
+	if start < 0 {
+		return 0, 0, false
+	}
+	// The sections end the doc, whose final newline can't be deleted.
+	return start, end - 1, true
+}
+
+// replacePenaltyRequests deletes the penalty sections between start and end
+// and inserts penalties in their place.
+func replacePenaltyRequests(start, end int64, penalties *models.Penalties) []*docs.Request {
+	requests := []*docs.Request{{
+		DeleteContentRange: &docs.DeleteContentRangeRequest{
+			Range: &docs.Range{StartIndex: start, EndIndex: end},
+		},
+	}}
+	return append(requests, penaltyRequests(start, penalties)...)
+}
+
+// headingText returns the text of elem when it is a paragraph styled as
+// style, or "" otherwise.
+func headingText(elem *docs.StructuralElement, style string) string {
+	p := elem.Paragraph
+	if p == nil || p.ParagraphStyle == nil || p.ParagraphStyle.NamedStyleType != style {
+		return ""
+	}
+	return paragraphText(elem)
+}
+
+// paragraphText returns the text of elem when it is a paragraph, or ""
+// otherwise. Elements without text, such as images, are skipped.
+func paragraphText(elem *docs.StructuralElement) string {
+	if elem.Paragraph == nil {
+		return ""
+	}
+	var text strings.Builder
+	for _, e := range elem.Paragraph.Elements {
+		if e.TextRun != nil {
+			text.WriteString(e.TextRun.Content)
+		}
+	}
+	return text.String()
+}
+
+// penaltiesMarker finds where in the briefing template the penalty sections
+// go: the named range tmpl.Range(), or else the paragraph reading
+// tmpl.Marker(). Its content is replaced by the sections. Templates with
+// neither have the sections inserted above their legacy "Stream" heading.
+func penaltiesMarker(doc *docs.Document, tmpl config.BriefingTemplate) (start, end int64, err error) {
+	if ranges, ok := doc.NamedRanges[tmpl.Range()]; ok && len(ranges.NamedRanges) > 0 && len(ranges.NamedRanges[0].Ranges) > 0 {
+		r := ranges.NamedRanges[0].Ranges[0]
+		return r.StartIndex, r.EndIndex, nil
+	}
+	for i, elem := range doc.Body.Content {
+		if strings.TrimSpace(paragraphText(elem)) != tmpl.Marker() {
+			continue
+		}
+		if i == len(doc.Body.Content)-1 {
+			// The doc's final newline can't be deleted.
+			return elem.StartIndex, elem.EndIndex - 1, nil
+		}
+		return elem.StartIndex, elem.EndIndex, nil
+	}
+	if start, ok := legacyPenaltiesMarker(doc); ok {
+		return start, start, nil
 	}
+	return 0, 0, fmt.Errorf("the Briefing Template has no %q named range or %q paragraph marking where the penalties go", tmpl.Range(), tmpl.Marker())
+}
 
-	return fmt.Sprintf("https://docs.google.com/document/d/%s", briefingFile.Id), nil
+// legacyPenaltiesMarker finds the start of doc's legacy "Stream" heading.
+func legacyPenaltiesMarker(doc *docs.Document) (int64, bool) {
+	for _, elem := range doc.Body.Content {
+		if strings.HasPrefix(headingText(elem, "HEADING_3"), legacyPenaltiesHeading) {
+			return elem.StartIndex, true
+		}
+	}
+	return 0, false
 }
 
 // EnsureSeasonFolder finds or creates a folder named seasonName as a sibling of
@@ -138,107 +415,391 @@ func (c *Client) EnsureSeasonFolder(ctx context.Context, currentFolderID, season
 	return created.Id, nil
 }
 
-func (c *Client) GeneratePenaltyTracker(conf *config.Config) (string, error) {
-	ctx := context.Background()
-	file, err := c.Drive.CopyFile(ctx, conf.TrackerTemplateDocID, conf.TrackerFolderID,
-		fmt.Sprintf("%s Rookies Round %d - %s", conf.Season, conf.NextRound.Number, conf.NextRound.Track))
+// BriefingPermissions are the permissions GenerateBriefing gives the briefing
+// docs it copies.
+func BriefingPermissions(sharing config.Sharing) []*drive.Permission {
+	if sharing.BriefingLink == "" {
+		return nil
+	}
+	return []*drive.Permission{{Type: "anyone", Role: sharing.BriefingLink}}
+}
+
+// TrackerPermissions are the permissions GeneratePenaltyTracker gives the
+// penalty trackers it copies.
+func TrackerPermissions(sharing config.Sharing) []*drive.Permission {
+	var permissions []*drive.Permission
+	for _, email := range sharing.TrackerEditors {
+		permissions = append(permissions, &drive.Permission{Type: "user", Role: "writer", EmailAddress: email})
+	}
+	return permissions
+}
+
+// roleRanks orders Drive roles by what they allow, so a file shared more
+// widely than asked still passes CheckSharing.
+var roleRanks = map[string]int{
+	"reader":        1,
+	"commenter":     2,
+	"writer":        3,
+	"fileOrganizer": 4,
+	"organizer":     5,
+	"owner":         6,
+}
+
+// describePermission names who permission shares a file with, as Drive's
+// sharing dialog would.
+func describePermission(permission *drive.Permission) string {
+	role := map[string]string{"reader": "viewer", "commenter": "commenter", "writer": "editor"}[permission.Role]
+	if role == "" {
+		role = permission.Role
+	}
+	if permission.Type == "anyone" {
+		return fmt.Sprintf("anyone with the link as %s", role)
+	}
+	return fmt.Sprintf("%s as %s", permission.EmailAddress, role)
+}
+
+func (c *Client) share(ctx context.Context, fileID string, permissions []*drive.Permission) error {
+	for _, permission := range permissions {
+		if err := c.Drive.CreatePermission(ctx, fileID, permission); err != nil {
+			return fmt.Errorf("could not share it with %s: %s", describePermission(permission), err)
+		}
+	}
+	return nil
+}
+
+// CheckSharing confirms the file with the given ID is shared with everyone
+// in want, at least as widely as asked.
+func (c *Client) CheckSharing(ctx context.Context, id string, want []*drive.Permission) error {
+	have, err := c.Drive.ListPermissions(ctx, id)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, w := range want {
+		granted := slices.ContainsFunc(have, func(h *drive.Permission) bool {
+			return h.Type == w.Type && strings.EqualFold(h.EmailAddress, w.EmailAddress) && roleRanks[h.Role] >= roleRanks[w.Role]
+		})
+		if !granted {
+			missing = append(missing, describePermission(w))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("not shared with %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// CheckTemplate confirms the bot can see and copy the template doc with the
+// given ID, returning it.
+func (c *Client) CheckTemplate(ctx context.Context, id string) (*drive.File, error) {
+	if id == "" {
+		return nil, fmt.Errorf("no template doc is configured")
+	}
+	file, err := c.Drive.GetFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if file.MimeType == folderMimeType {
+		return nil, fmt.Errorf("%q is a folder, not a doc", file.Name)
+	}
+	if file.Capabilities != nil && !file.Capabilities.CanCopy {
+		return nil, fmt.Errorf("the bot cannot copy %q", file.Name)
+	}
+	return file, nil
+}
+
+// CheckFolder confirms the bot can see and add files to the folder with the
+// given ID, returning it.
+func (c *Client) CheckFolder(ctx context.Context, id string) (*drive.File, error) {
+	if id == "" {
+		return nil, fmt.Errorf("no folder is configured")
+	}
+	file, err := c.Drive.GetFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if file.MimeType != folderMimeType {
+		return nil, fmt.Errorf("%q is not a folder", file.Name)
+	}
+	if file.Capabilities != nil && !file.Capabilities.CanAddChildren {
+		return nil, fmt.Errorf("the bot cannot add files to %q", file.Name)
+	}
+	return file, nil
+}
+
+// BriefingTemplateCheck is what CheckBriefingContent found in the briefing
+// template.
+type BriefingTemplateCheck struct {
+	// Name is the template doc's title.
+	Name string
+	// Marker describes where the penalties go, or is empty when the
+	// template doesn't mark it.
+	Marker string
+	// Legacy is set when only the legacy "Stream" heading marks where the
+	// penalties go.
+	Legacy bool
+	// Missing lists the configured placeholders the template doesn't use.
+	Missing []string
+	// Unknown lists the bracketed text in the template that isn't a
+	// configured placeholder, so would be left as it is.
+	Unknown []string
+}
+
+// bracketed matches text that looks like a placeholder.
+var bracketed = regexp.MustCompile(`\[[^\[\]\n]+\]`)
+
+// CheckBriefingContent reads the briefing template with the given ID and
+// checks it marks where the penalties go and uses each of tmpl's
+// placeholders, noting any other bracketed text.
+func (c *Client) CheckBriefingContent(ctx context.Context, id string, tmpl config.BriefingTemplate) (*BriefingTemplateCheck, error) {
+	if id == "" {
+		return nil, fmt.Errorf("no template doc is configured")
+	}
+	doc, err := c.Docs.GetDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &BriefingTemplateCheck{Name: doc.Title}
+	if start, end, err := penaltiesMarker(doc, tmpl); err == nil {
+		if _, ok := doc.NamedRanges[tmpl.Range()]; ok {
+			check.Marker = fmt.Sprintf("named range %q", tmpl.Range())
+		} else if legacy, ok := legacyPenaltiesMarker(doc); ok && start == legacy && end == legacy {
+			check.Marker = fmt.Sprintf("legacy %q heading", legacyPenaltiesHeading)
+			check.Legacy = true
+		} else {
+			check.Marker = fmt.Sprintf("%q paragraph", tmpl.Marker())
+		}
+	}
+
+	var text strings.Builder
+	documentText(&text, doc.Body.Content)
+	known := map[string]bool{tmpl.Marker(): true}
+	for _, placeholder := range tmpl.PlaceholderList() {
+		known[placeholder.Text] = true
+		if !strings.Contains(text.String(), placeholder.Text) {
+			check.Missing = append(check.Missing, placeholder.Text)
+		}
+	}
+	for _, found := range bracketed.FindAllString(text.String(), -1) {
+		if !known[found] && !slices.Contains(check.Unknown, found) {
+			check.Unknown = append(check.Unknown, found)
+		}
+	}
+	return check, nil
+}
+
+// documentText writes the text of content, including that in tables, to b.
+func documentText(b *strings.Builder, content []*docs.StructuralElement) {
+	for _, elem := range content {
+		b.WriteString(paragraphText(elem))
+		if elem.Table == nil {
+			continue
+		}
+		for _, row := range elem.Table.TableRows {
+			for _, cell := range row.TableCells {
+				documentText(b, cell.Content)
+			}
+		}
+	}
+}
+
+// PreviewBriefing renders what GenerateBriefing would write into a copy of
+// the briefing template, without touching Drive: the penalty sections it
+// inserts in place of the penalties marker, then each placeholder
+// replacement.
+func PreviewBriefing(conf *config.Config, penalties *models.Penalties, briefingTime time.Time) (string, error) {
+	marker := conf.BriefingTemplate.Marker() + "\n"
+	template := &docs.Document{Body: &docs.Body{Content: []*docs.StructuralElement{{
+		StartIndex: 1,
+		EndIndex:   1 + int64(len(marker)),
+		Paragraph: &docs.Paragraph{
+			Elements: []*docs.ParagraphElement{{TextRun: &docs.TextRun{Content: marker}}},
+		},
+	}}}}
+	updates, err := generateUpdates(conf, penalties, template, briefingTime)
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s", file.Id), nil
+
+	// Every section is inserted at the same index, so the doc reads them in
+	// the reverse of the order they were inserted.
+	var inserted, replaced []string
+	for i, req := range updates.Requests {
+		switch {
+		case req.InsertText != nil:
+			line := strings.TrimSuffix(req.InsertText.Text, "\n")
+			if i+1 < len(updates.Requests) && updates.Requests[i+1].CreateParagraphBullets != nil {
+				line = "  • " + line
+			}
+			inserted = append([]string{line}, inserted...)
+		case req.ReplaceAllText != nil:
+			replaced = append(replaced, fmt.Sprintf("%s → %s", req.ReplaceAllText.ContainsText.Text, req.ReplaceAllText.ReplaceText))
+		}
+	}
+	return fmt.Sprintf("%s\n\n%s", strings.Join(inserted, "\n"), strings.Join(replaced, "\n")), nil
+}
+
+// GeneratePenaltyTracker copies the penalty tracker template for the next
+// round and shares it with the stewards, or reuses the round's tracker when it
+// is already in the tracker folder. A copy that can't be shared is trashed.
+func (c *Client) GeneratePenaltyTracker(conf *config.Config) (string, error) {
+	ctx := c.context()
+	title := fmt.Sprintf("%s Rookies Round %d - %s", conf.Season, conf.NextRound.Number, conf.NextRound.Track)
+	file, err := c.Drive.FindFile(ctx, conf.TrackerFolderID, title)
+	if err != nil {
+		return "", fmt.Errorf("failed searching the tracker folder for %q: %s", title, err)
+	}
+	if file == nil {
+		file, err = c.Drive.CopyFile(ctx, conf.TrackerTemplateDocID, conf.TrackerFolderID, title)
+		if err != nil {
+			return "", err
+		}
+		if err := c.share(ctx, file.Id, TrackerPermissions(conf.Sharing)); err != nil {
+			if trashErr := c.Drive.TrashFile(ctx, file.Id); trashErr != nil {
+				return "", fmt.Errorf("failed sharing the penalty tracker: %s (and could not trash the copy: %s)", err, trashErr)
+			}
+			return "", fmt.Errorf("failed sharing the penalty tracker: %s", err)
+		}
+	}
+	return trackerURLPrefix + file.Id, nil
 }
 
-func generateUpdates(conf *config.Config, penalties *models.Penalties, doc *docs.Document) (*docs.BatchUpdateDocumentRequest, error) {
+// BriefingValues are what the briefing template's placeholders are filled in
+// from.
+type BriefingValues struct {
+	Round  config.Round
+	Season string
+	// Group1 and Group2 are the car numbers, "ODD" or "EVEN", in the first
+	// and second qualifying groups.
+	Group1, Group2 string
+	// BriefingTime is the briefing time in the schedule's display timezones.
+	BriefingTime string
+}
+
+// generateUpdates replaces the penalties marker in doc with the penalty
+// sections, then fills in each placeholder.
+func generateUpdates(conf *config.Config, penalties *models.Penalties, doc *docs.Document, briefingTime time.Time) (*docs.BatchUpdateDocumentRequest, error) {
+	start, end, err := penaltiesMarker(doc, conf.BriefingTemplate)
+	if err != nil {
+		return nil, err
+	}
+
 	requests := []*docs.Request{}
+	if end > start {
+		requests = append(requests, &docs.Request{
+			DeleteContentRange: &docs.DeleteContentRangeRequest{
+				Range: &docs.Range{StartIndex: start, EndIndex: end},
+			},
+		})
+	}
+	requests = append(requests, penaltyRequests(start, penalties)...)
 
-	// Grab index of "Stream" heading, and work backwards when building new text
-	var penaltyStartIndex int64
-	for _, elem := range doc.Body.Content {
-		if elem.Paragraph != nil && elem.Paragraph.ParagraphStyle != nil && len(elem.Paragraph.Elements) > 0 &&
-			elem.Paragraph.ParagraphStyle.NamedStyleType == "HEADING_3" &&
-			strings.HasPrefix(elem.Paragraph.Elements[0].TextRun.Content, "Stream") {
-			penaltyStartIndex = elem.StartIndex
-		}
+	replacements, err := placeholderRequests(conf, briefingTime)
+	if err != nil {
+		return nil, err
+	}
+	requests = append(requests, replacements...)
+
+	return &docs.BatchUpdateDocumentRequest{
+		Requests: requests,
+	}, nil
+}
+
+// placeholderRequests replaces each of the configured placeholders with its
+// value for the next round.
+func placeholderRequests(conf *config.Config, briefingTime time.Time) ([]*docs.Request, error) {
+	displayTime, err := conf.Schedule.DisplayTime(briefingTime)
+	if err != nil {
+		return nil, err
+	}
+	values := BriefingValues{
+		Round:        conf.NextRound,
+		Season:       conf.Season,
+		Group1:       "ODD",
+		Group2:       "EVEN",
+		BriefingTime: displayTime,
+	}
+	if conf.NextRound.Number%2 == 0 {
+		values.Group1, values.Group2 = "EVEN", "ODD"
 	}
 
-	if penaltyStartIndex < 0 {
-		return nil, fmt.Errorf("could not find H3 'Stream' to start inserting penalty data ahead of")
+	var requests []*docs.Request
+	for _, placeholder := range conf.BriefingTemplate.PlaceholderList() {
+		tmpl, err := template.New(placeholder.Text).Option("missingkey=error").Parse(placeholder.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid placeholder %q: %s", placeholder.Text, err)
+		}
+		var value strings.Builder
+		if err := tmpl.Execute(&value, values); err != nil {
+			return nil, fmt.Errorf("could not fill in placeholder %q: %s", placeholder.Text, err)
+		}
+		requests = append(requests, replaceText(placeholder.Text, value.String()))
 	}
+	return requests, nil
+}
+
+// penaltyRequests inserts the penalty sections, headed by penaltiesHeading,
+// at index. They are built back to front, each inserted ahead of the last.
+func penaltyRequests(index int64, penalties *models.Penalties) []*docs.Request {
+	requests := []*docs.Request{}
 
 	// Pit Starts R2
 	if len(penalties.PitStartsR2CarriedOver)+len(penalties.PitStartsR2) == 0 {
-		requests = append(requests, generatePenaltyEntry(penaltyStartIndex, "None!\n")...)
+		requests = append(requests, generatePenaltyEntry(index, "None!\n")...)
 	} else {
 		for _, driver := range penalties.PitStartsR2CarriedOver {
-			requests = append(requests, generatePenaltyEntry(penaltyStartIndex, fmt.Sprintf("#%03d - %s %s (carried over)\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
+			requests = append(requests, generatePenaltyEntry(index, fmt.Sprintf("#%03d - %s %s (carried over)\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
 		}
 		for _, driver := range penalties.PitStartsR2 {
-			requests = append(requests, generatePenaltyEntry(penaltyStartIndex, fmt.Sprintf("#%03d - %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
+			requests = append(requests, generatePenaltyEntry(index, fmt.Sprintf("#%03d - %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
 		}
 	}
-	requests = append(requests, generateHeading(penaltyStartIndex, "HEADING_4", "Race 2 Pit Starts\n")...)
+	requests = append(requests, generateHeading(index, "HEADING_4", "Race 2 Pit Starts\n")...)
 
 	// Quali Bans
 	if len(penalties.QualiBansR2CarriedOver)+len(penalties.QualiBansR2) == 0 {
-		requests = append(requests, generatePenaltyEntry(penaltyStartIndex, "None!\n")...)
+		requests = append(requests, generatePenaltyEntry(index, "None!\n")...)
 	} else {
 		for _, driver := range penalties.QualiBansR2CarriedOver {
-			requests = append(requests, generatePenaltyEntry(penaltyStartIndex, fmt.Sprintf("#%03d - %s %s (carried over)\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
+			requests = append(requests, generatePenaltyEntry(index, fmt.Sprintf("#%03d - %s %s (carried over)\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
 		}
 		for _, driver := range penalties.QualiBansR2 {
-			requests = append(requests, generatePenaltyEntry(penaltyStartIndex, fmt.Sprintf("#%03d - %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
+			requests = append(requests, generatePenaltyEntry(index, fmt.Sprintf("#%03d - %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
 		}
 	}
-	requests = append(requests, generateHeading(penaltyStartIndex, "HEADING_4", "Race 2 Quali Bans\n")...)
+	requests = append(requests, generateHeading(index, "HEADING_4", "Race 2 Quali Bans\n")...)
 
 	// Pit Starts R1
 	if len(penalties.PitStartsR1CarriedOver)+len(penalties.PitStartsR1) == 0 {
 
-		requests = append(requests, generatePenaltyEntry(penaltyStartIndex, "None!\n")...)
+		requests = append(requests, generatePenaltyEntry(index, "None!\n")...)
 	} else {
 		for _, driver := range penalties.PitStartsR1CarriedOver {
-			requests = append(requests, generatePenaltyEntry(penaltyStartIndex, fmt.Sprintf("#%03d - %s %s (carried over)\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
+			requests = append(requests, generatePenaltyEntry(index, fmt.Sprintf("#%03d - %s %s (carried over)\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
 		}
 		for _, driver := range penalties.PitStartsR1 {
-			requests = append(requests, generatePenaltyEntry(penaltyStartIndex, fmt.Sprintf("#%03d - %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
+			requests = append(requests, generatePenaltyEntry(index, fmt.Sprintf("#%03d - %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
 		}
 	}
-	requests = append(requests, generateHeading(penaltyStartIndex, "HEADING_4", "Race 1 Pit Starts\n")...)
+	requests = append(requests, generateHeading(index, "HEADING_4", "Race 1 Pit Starts\n")...)
 
 	// Quali Bans
 	if len(penalties.QualiBansR1CarriedOver)+len(penalties.QualiBansR1) == 0 {
-		requests = append(requests, generatePenaltyEntry(penaltyStartIndex, "None!\n")...)
+		requests = append(requests, generatePenaltyEntry(index, "None!\n")...)
 	} else {
 		for _, driver := range penalties.QualiBansR1CarriedOver {
-			requests = append(requests, generatePenaltyEntry(penaltyStartIndex, fmt.Sprintf("#%03d - %s %s (carried over)\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
+			requests = append(requests, generatePenaltyEntry(index, fmt.Sprintf("#%03d - %s %s (carried over)\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
 		}
 		for _, driver := range penalties.QualiBansR1 {
-			requests = append(requests, generatePenaltyEntry(penaltyStartIndex, fmt.Sprintf("#%03d - %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
+			requests = append(requests, generatePenaltyEntry(index, fmt.Sprintf("#%03d - %s %s\n", driver.CarNumber, driver.FirstName, driver.LastName))...)
 		}
 	}
-	requests = append(requests, generateHeading(penaltyStartIndex, "HEADING_4", "Race 1 Quali Bans\n")...)
+	requests = append(requests, generateHeading(index, "HEADING_4", "Race 1 Quali Bans\n")...)
 
 	// Penalties Heading
-	requests = append(requests, generateHeading(penaltyStartIndex, "HEADING_3", "Drivers Serving Penalties Tonight\n")...)
-
-	// Now replace all templated text
-	requests = append(requests, replaceText("[num]", fmt.Sprintf("%d", conf.NextRound.Number)))
-	requests = append(requests, replaceText("[Track Name]", conf.NextRound.Track))
-
-	group1 := "ODD"
-	group2 := "EVEN"
-	if conf.NextRound.Number%2 == 0 {
-		group1 = "EVEN"
-		group2 = "ODD"
-	}
-	requests = append(requests, replaceText("[group1]", group1))
-	requests = append(requests, replaceText("[group2]", group2))
-	requests = append(requests, replaceText("[briefing time]", "7:30PM Eastern/4:30PM Pacific"))
-	requests = append(requests, replaceText("[SEASON]", conf.Season))
-
-	return &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
-	}, nil
+	requests = append(requests, generateHeading(index, "HEADING_3", penaltiesHeading+"\n")...)
+	return requests
 }
 
 func replaceText(find, replace string) *docs.Request {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/geofffranks/rookies-bot/config"
 	"github.com/geofffranks/rookies-bot/gcloud"
@@ -16,14 +17,28 @@ import (
 	"google.golang.org/api/drive/v3"
 )
 
-// makeDoc builds a minimal docs.Document with a HEADING_3 "Stream" element
-// at the given start index for testing generateUpdates.
-func makeDoc(streamIndex int64) *docs.Document {
+// briefingTime is Monday 9 March 2026, 7:30PM Eastern.
+var briefingTime = time.Date(2026, time.March, 9, 23, 30, 0, 0, time.UTC)
+
+// makeDoc builds a minimal briefing template with the penalties marker
+// paragraph at the given start index, followed by a HEADING_3 "Stream".
+func makeDoc(markerIndex int64) *docs.Document {
 	return &docs.Document{
 		Body: &docs.Body{
 			Content: []*docs.StructuralElement{
 				{
-					StartIndex: streamIndex,
+					StartIndex: markerIndex,
+					EndIndex:   markerIndex + 12,
+					Paragraph: &docs.Paragraph{
+						ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"},
+						Elements: []*docs.ParagraphElement{
+							{TextRun: &docs.TextRun{Content: "[penalties]\n"}},
+						},
+					},
+				},
+				{
+					StartIndex: markerIndex + 12,
+					EndIndex:   markerIndex + 19,
 					Paragraph: &docs.Paragraph{
 						ParagraphStyle: &docs.ParagraphStyle{
 							NamedStyleType: "HEADING_3",
@@ -102,6 +117,79 @@ var _ = Describe("Client", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("drive unavailable"))
 		})
+
+		It("gives the configured stewards edit access to the copy", func() {
+			conf.Sharing.TrackerEditors = []string{"steward@example.com", "chief@example.com"}
+			fakeDriveService.CopyFileReturns(&drive.File{Id: "new-tracker-id"}, nil)
+
+			_, err := client.GeneratePenaltyTracker(conf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeDriveService.CreatePermissionCallCount()).To(Equal(2))
+			_, fileID, permission := fakeDriveService.CreatePermissionArgsForCall(0)
+			Expect(fileID).To(Equal("new-tracker-id"))
+			Expect(permission).To(Equal(&drive.Permission{Type: "user", Role: "writer", EmailAddress: "steward@example.com"}))
+			_, _, permission = fakeDriveService.CreatePermissionArgsForCall(1)
+			Expect(permission.EmailAddress).To(Equal("chief@example.com"))
+		})
+
+		It("trashes a copy it can't share", func() {
+			conf.Sharing.TrackerEditors = []string{"steward@example.com"}
+			fakeDriveService.CopyFileReturns(&drive.File{Id: "new-tracker-id"}, nil)
+			fakeDriveService.CreatePermissionReturns(errors.New("sharing outside the domain is not allowed"))
+
+			_, err := client.GeneratePenaltyTracker(conf)
+			Expect(err).To(MatchError("failed sharing the penalty tracker: could not share it with steward@example.com as editor: sharing outside the domain is not allowed"))
+			Expect(fakeDriveService.TrashFileCallCount()).To(Equal(1))
+			_, id := fakeDriveService.TrashFileArgsForCall(0)
+			Expect(id).To(Equal("new-tracker-id"))
+		})
+
+		It("reuses the round's tracker when it is already in the folder", func() {
+			fakeDriveService.FindFileReturns(&drive.File{Id: "old-tracker-id"}, nil)
+
+			url, err := client.GeneratePenaltyTracker(conf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(url).To(Equal("https://docs.google.com/spreadsheets/d/old-tracker-id"))
+			_, folderID, name := fakeDriveService.FindFileArgsForCall(0)
+			Expect(folderID).To(Equal("tracker-folder-id"))
+			Expect(name).To(Equal("2026 Rookies Round 5 - Monza"))
+			Expect(fakeDriveService.CopyFileCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when searching the folder fails", func() {
+			fakeDriveService.FindFileReturns(nil, errors.New("rate limited"))
+			_, err := client.GeneratePenaltyTracker(conf)
+			Expect(err).To(MatchError(`failed searching the tracker folder for "2026 Rookies Round 5 - Monza": rate limited`))
+			Expect(fakeDriveService.CopyFileCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("TrashFile", func() {
+		It("trashes the file in Drive", func() {
+			Expect(client.TrashFile("briefing-id")).To(Succeed())
+			Expect(fakeDriveService.TrashFileCallCount()).To(Equal(1))
+			_, id := fakeDriveService.TrashFileArgsForCall(0)
+			Expect(id).To(Equal("briefing-id"))
+		})
+
+		It("returns an error when Drive fails", func() {
+			fakeDriveService.TrashFileReturns(errors.New("forbidden"))
+			Expect(client.TrashFile("briefing-id")).To(MatchError("forbidden"))
+		})
+	})
+
+	Describe("WithContext", func() {
+		It("makes calls with the client's context", func() {
+			type key struct{}
+			ctx := context.WithValue(context.Background(), key{}, "root")
+			Expect(client.WithContext(ctx).TrashFile("briefing-id")).To(Succeed())
+			callCtx, _ := fakeDriveService.TrashFileArgsForCall(0)
+			Expect(callCtx.Value(key{})).To(Equal("root"))
+
+			Expect(client.TrashFile("briefing-id")).To(Succeed())
+			callCtx, _ = fakeDriveService.TrashFileArgsForCall(1)
+			Expect(callCtx.Value(key{})).To(BeNil())
+		})
 	})
 
 	Describe("GenerateBriefing", func() {
@@ -112,7 +200,7 @@ var _ = Describe("Client", func() {
 		})
 
 		It("copies the template, fetches the doc, sends updates, returns URL", func() {
-			url, err := client.GenerateBriefing(conf, penalties)
+			url, err := client.GenerateBriefing(conf, penalties, briefingTime)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(url).To(Equal("https://docs.google.com/document/d/new-briefing-id"))
 
@@ -122,33 +210,258 @@ var _ = Describe("Client", func() {
 			Expect(folderID).To(Equal("briefing-folder-id"))
 			Expect(title).To(Equal("Drivers Briefing Round 5 at Monza"))
 
-			Expect(fakeDocsService.GetDocumentCallCount()).To(Equal(1))
+			Expect(fakeDocsService.GetDocumentCallCount()).To(Equal(2))
 			_, docID := fakeDocsService.GetDocumentArgsForCall(0)
+			Expect(docID).To(Equal("tmpl-doc-id"))
+			_, docID = fakeDocsService.GetDocumentArgsForCall(1)
 			Expect(docID).To(Equal("new-briefing-id"))
 
 			Expect(fakeDocsService.BatchUpdateDocumentCallCount()).To(Equal(1))
 		})
 
+		It("lets anyone with the link view the copy when configured", func() {
+			_, err := client.GenerateBriefing(conf, penalties, briefingTime)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeDriveService.CreatePermissionCallCount()).To(Equal(0))
+
+			conf.Sharing.BriefingLink = config.LinkRoleReader
+			_, err = client.GenerateBriefing(conf, penalties, briefingTime)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeDriveService.CreatePermissionCallCount()).To(Equal(1))
+			_, fileID, permission := fakeDriveService.CreatePermissionArgsForCall(0)
+			Expect(fileID).To(Equal("new-briefing-id"))
+			Expect(permission).To(Equal(&drive.Permission{Type: "anyone", Role: "reader"}))
+		})
+
+		It("trashes a copy it can't share, so the next run copies and shares it again", func() {
+			conf.Sharing.BriefingLink = config.LinkRoleReader
+			fakeDriveService.CreatePermissionReturnsOnCall(0, errors.New("forbidden"))
+			url, err := client.GenerateBriefing(conf, penalties, briefingTime)
+			Expect(err).To(MatchError("failed sharing the Briefing Doc: could not share it with anyone with the link as viewer: forbidden"))
+			Expect(url).To(BeEmpty())
+			Expect(fakeDocsService.BatchUpdateDocumentCallCount()).To(Equal(0))
+			Expect(fakeDriveService.TrashFileCallCount()).To(Equal(1))
+			_, id := fakeDriveService.TrashFileArgsForCall(0)
+			Expect(id).To(Equal("new-briefing-id"))
+
+			fakeDriveService.CopyFileReturns(&drive.File{Id: "second-briefing-id"}, nil)
+			url, err = client.GenerateBriefing(conf, penalties, briefingTime)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(url).To(Equal("https://docs.google.com/document/d/second-briefing-id"))
+			Expect(fakeDriveService.CopyFileCallCount()).To(Equal(2))
+			Expect(fakeDriveService.CreatePermissionCallCount()).To(Equal(2))
+			_, fileID, _ := fakeDriveService.CreatePermissionArgsForCall(1)
+			Expect(fileID).To(Equal("second-briefing-id"))
+		})
+
+		It("returns the copy's URL when it can neither share nor trash it", func() {
+			conf.Sharing.BriefingLink = config.LinkRoleReader
+			fakeDriveService.CreatePermissionReturns(errors.New("forbidden"))
+			fakeDriveService.TrashFileReturns(errors.New("rate limited"))
+			url, err := client.GenerateBriefing(conf, penalties, briefingTime)
+			Expect(err).To(MatchError("failed sharing the Briefing Doc: could not share it with anyone with the link as viewer: forbidden (and could not trash the copy: rate limited)"))
+			Expect(url).To(Equal("https://docs.google.com/document/d/new-briefing-id"))
+		})
+
 		It("returns an error when Drive copy fails", func() {
 			fakeDriveService.CopyFileReturns(nil, errors.New("copy failed"))
-			_, err := client.GenerateBriefing(conf, penalties)
+			_, err := client.GenerateBriefing(conf, penalties, briefingTime)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("copy failed"))
 		})
 
 		It("returns an error when GetDocument fails", func() {
 			fakeDocsService.GetDocumentReturns(nil, errors.New("docs api down"))
-			_, err := client.GenerateBriefing(conf, penalties)
+			_, err := client.GenerateBriefing(conf, penalties, briefingTime)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("docs api down"))
 		})
 
+		It("checks the template before copying it", func() {
+			fakeDocsService.GetDocumentReturns(&docs.Document{Body: &docs.Body{}}, nil)
+			_, err := client.GenerateBriefing(conf, penalties, briefingTime)
+			Expect(err).To(MatchError(`failed processing Briefing Template: the Briefing Template has no "penalties" named range or "[penalties]" paragraph marking where the penalties go`))
+			Expect(fakeDriveService.CopyFileCallCount()).To(Equal(0))
+		})
+
+		It("does not copy a template whose placeholders can't be filled in", func() {
+			conf.BriefingTemplate.Placeholders = map[string]string{"[host]": "{{.Host}}"}
+			_, err := client.GenerateBriefing(conf, penalties, briefingTime)
+			Expect(err).To(MatchError(ContainSubstring(`failed processing Briefing Template: could not fill in placeholder "[host]"`)))
+			Expect(fakeDriveService.CopyFileCallCount()).To(Equal(0))
+		})
+
+		It("still returns the copy's URL when filling it in fails", func() {
+			fakeDocsService.GetDocumentReturnsOnCall(1, nil, errors.New("docs api down"))
+			url, err := client.GenerateBriefing(conf, penalties, briefingTime)
+			Expect(err).To(HaveOccurred())
+			Expect(url).To(Equal("https://docs.google.com/document/d/new-briefing-id"))
+		})
+
 		It("returns an error when BatchUpdate fails", func() {
 			fakeDocsService.BatchUpdateDocumentReturns(nil, errors.New("batch failed"))
-			_, err := client.GenerateBriefing(conf, penalties)
+			_, err := client.GenerateBriefing(conf, penalties, briefingTime)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("batch failed"))
 		})
+
+		Context("when the round's briefing doc is already in the folder", func() {
+			BeforeEach(func() {
+				fakeDriveService.FindFileReturns(&drive.File{Id: "old-briefing-id"}, nil)
+				fakeDocsService.GetDocumentReturns(&docs.Document{Body: &docs.Body{Content: []*docs.StructuralElement{
+					{StartIndex: 10, Paragraph: &docs.Paragraph{
+						ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "HEADING_3"},
+						Elements:       []*docs.ParagraphElement{{TextRun: &docs.TextRun{Content: "Drivers Serving Penalties Tonight\n"}}},
+					}},
+					{StartIndex: 80, Paragraph: &docs.Paragraph{
+						ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "HEADING_3"},
+						Elements:       []*docs.ParagraphElement{{TextRun: &docs.TextRun{Content: "Stream\n"}}},
+					}},
+				}}}, nil)
+			})
+
+			It("replaces its penalty sections in place", func() {
+				url, err := client.GenerateBriefing(conf, penalties, briefingTime)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(url).To(Equal("https://docs.google.com/document/d/old-briefing-id"))
+
+				_, folderID, name := fakeDriveService.FindFileArgsForCall(0)
+				Expect(folderID).To(Equal("briefing-folder-id"))
+				Expect(name).To(Equal("Drivers Briefing Round 5 at Monza"))
+				Expect(fakeDriveService.CopyFileCallCount()).To(Equal(0))
+
+				_, docID, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
+				Expect(docID).To(Equal("old-briefing-id"))
+				Expect(req.Requests[0].DeleteContentRange.Range.StartIndex).To(Equal(int64(10)))
+				Expect(req.Requests[0].DeleteContentRange.Range.EndIndex).To(Equal(int64(80)))
+				Expect(insertedTexts(req)).To(ContainElement("Drivers Serving Penalties Tonight\n"))
+			})
+
+			It("fills it in from scratch when an earlier run never did", func() {
+				fakeDocsService.GetDocumentReturns(makeDoc(10), nil)
+				_, err := client.GenerateBriefing(conf, penalties, briefingTime)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeDriveService.CopyFileCallCount()).To(Equal(0))
+
+				_, _, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
+				Expect(req.Requests[0].DeleteContentRange.Range.StartIndex).To(Equal(int64(10)))
+				Expect(req.Requests[0].DeleteContentRange.Range.EndIndex).To(Equal(int64(22)))
+				Expect(req.Requests[len(req.Requests)-1].ReplaceAllText.ContainsText.Text).To(Equal("[SEASON]"))
+			})
+
+			It("returns it unchanged when configured to keep existing docs", func() {
+				conf.ExistingDocs = config.ExistingDocsKeep
+				url, err := client.GenerateBriefing(conf, penalties, briefingTime)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(url).To(Equal("https://docs.google.com/document/d/old-briefing-id"))
+				Expect(fakeDriveService.CopyFileCallCount()).To(Equal(0))
+				Expect(fakeDocsService.GetDocumentCallCount()).To(Equal(0))
+				Expect(fakeDocsService.BatchUpdateDocumentCallCount()).To(Equal(0))
+			})
+		})
+
+		It("returns an error when searching the folder fails", func() {
+			fakeDriveService.FindFileReturns(nil, errors.New("rate limited"))
+			_, err := client.GenerateBriefing(conf, penalties, briefingTime)
+			Expect(err).To(MatchError(`failed searching the Briefing folder for "Drivers Briefing Round 5 at Monza": rate limited`))
+			Expect(fakeDriveService.CopyFileCallCount()).To(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("UpdateBriefingPenalties", func() {
+	var (
+		fakeDocsService *fakes.FakeDocsServicer
+		client          *gcloud.Client
+	)
+
+	heading := func(index int64, style, text string) *docs.StructuralElement {
+		return &docs.StructuralElement{
+			StartIndex: index,
+			Paragraph: &docs.Paragraph{
+				ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: style},
+				Elements:       []*docs.ParagraphElement{{TextRun: &docs.TextRun{Content: text}}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		fakeDocsService = new(fakes.FakeDocsServicer)
+		client = &gcloud.Client{Docs: fakeDocsService, Drive: new(fakes.FakeDriveServicer)}
+		fakeDocsService.GetDocumentReturns(&docs.Document{Body: &docs.Body{Content: []*docs.StructuralElement{
+			heading(1, "HEADING_3", "Stream\n"),
+			heading(10, "HEADING_3", "Drivers Serving Penalties Tonight\n"),
+			heading(45, "HEADING_4", "Race 1 Quali Bans\n"),
+			{StartIndex: 63, Paragraph: &docs.Paragraph{Bullet: &docs.Bullet{}, Elements: []*docs.ParagraphElement{{InlineObjectElement: &docs.InlineObjectElement{}}}}},
+			heading(80, "HEADING_3", "Stream\n"),
+		}}}, nil)
+		fakeDocsService.BatchUpdateDocumentReturns(&docs.BatchUpdateDocumentResponse{}, nil)
+	})
+
+	It("replaces the inserted penalty sections", func() {
+		penalties := &models.Penalties{
+			PitStartsR1: []models.Driver{{CarNumber: 7, FirstName: "Kimi", LastName: "Antonelli"}},
+		}
+		Expect(client.UpdateBriefingPenalties("briefing-id", penalties)).To(Succeed())
+
+		_, docID, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
+		Expect(docID).To(Equal("briefing-id"))
+		Expect(req.Requests[0].DeleteContentRange.Range.StartIndex).To(Equal(int64(10)))
+		Expect(req.Requests[0].DeleteContentRange.Range.EndIndex).To(Equal(int64(80)))
+		for _, r := range req.Requests[1:] {
+			if r.InsertText != nil {
+				Expect(r.InsertText.Location.Index).To(Equal(int64(10)))
+			}
+		}
+		texts := insertedTexts(req)
+		Expect(texts).To(ContainElement("#007 - Kimi Antonelli\n"))
+		Expect(texts[len(texts)-1]).To(Equal("Drivers Serving Penalties Tonight\n"))
+	})
+
+	It("returns an error when the doc has no penalty sections", func() {
+		fakeDocsService.GetDocumentReturns(makeDoc(10), nil)
+		err := client.UpdateBriefingPenalties("briefing-id", &models.Penalties{})
+		Expect(err).To(MatchError(ContainSubstring(`could not find the "Drivers Serving Penalties Tonight" section`)))
+		Expect(fakeDocsService.BatchUpdateDocumentCallCount()).To(Equal(0))
+	})
+
+	It("returns the document ID of a briefing doc URL", func() {
+		Expect(gcloud.BriefingDocID("https://docs.google.com/document/d/briefing-id")).To(Equal("briefing-id"))
+	})
+
+	It("returns the spreadsheet ID of a penalty tracker URL", func() {
+		Expect(gcloud.TrackerID("https://docs.google.com/spreadsheets/d/tracker-id")).To(Equal("tracker-id"))
+	})
+})
+
+var _ = Describe("PreviewBriefing", func() {
+	It("renders the inserted penalty sections and placeholder replacements in doc order", func() {
+		conf := &config.Config{
+			BotConfig:   config.BotConfig{Season: "2026"},
+			RoundConfig: config.RoundConfig{NextRound: config.Round{Number: 5, Track: "Monza"}},
+		}
+		penalties := &models.Penalties{
+			QualiBansR1:            []models.Driver{{CarNumber: 7, FirstName: "Kimi", LastName: "Raikkonen"}},
+			PitStartsR2CarriedOver: []models.Driver{{CarNumber: 44, FirstName: "Lewis", LastName: "Hamilton"}},
+		}
+
+		preview, err := gcloud.PreviewBriefing(conf, penalties, briefingTime)
+		Expect(err).NotTo(HaveOccurred())
+		sections, replacements, found := strings.Cut(preview, "\n\n")
+		Expect(found).To(BeTrue())
+		Expect(sections).To(Equal(strings.Join([]string{
+			"Drivers Serving Penalties Tonight",
+			"Race 1 Quali Bans",
+			"  • #007 - Kimi Raikkonen",
+			"Race 1 Pit Starts",
+			"  • None!",
+			"Race 2 Quali Bans",
+			"  • None!",
+			"Race 2 Pit Starts",
+			"  • #044 - Lewis Hamilton (carried over)",
+		}, "\n")))
+		Expect(replacements).To(HavePrefix("[num] → 5\n[Track Name] → Monza\n[group1] → ODD\n[group2] → EVEN\n[briefing time] → "))
+		Expect(replacements).To(HaveSuffix("\n[SEASON] → 2026"))
 	})
 })
 
@@ -176,7 +489,7 @@ var _ = Describe("generateUpdates (via GenerateBriefing)", func() {
 	})
 
 	It("includes a replaceText request for [num] with the round number", func() {
-		_, err := client.GenerateBriefing(conf, &models.Penalties{})
+		_, err := client.GenerateBriefing(conf, &models.Penalties{}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 
 		_, _, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
@@ -189,11 +502,30 @@ var _ = Describe("generateUpdates (via GenerateBriefing)", func() {
 		Expect(texts).To(ContainElement("[num]->4"))
 		Expect(texts).To(ContainElement("[Track Name]->Silverstone"))
 		Expect(texts).To(ContainElement("[SEASON]->2026"))
+		Expect(texts).To(ContainElement("[briefing time]->7:30PM Eastern/4:30PM Pacific"))
+	})
+
+	It("spells out the briefing time in the configured display timezones", func() {
+		conf.Schedule.DisplayTimezones = []config.DisplayTimezone{
+			{Timezone: "Europe/London", Label: "UK"},
+			{Timezone: "Europe/Berlin"},
+		}
+		_, err := client.GenerateBriefing(conf, &models.Penalties{}, briefingTime)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
+		texts := make([]string, 0)
+		for _, r := range req.Requests {
+			if r.ReplaceAllText != nil {
+				texts = append(texts, fmt.Sprintf("%s->%s", r.ReplaceAllText.ContainsText.Text, r.ReplaceAllText.ReplaceText))
+			}
+		}
+		Expect(texts).To(ContainElement("[briefing time]->11:30PM UK/12:30AM CET"))
 	})
 
 	It("sets group1=ODD and group2=EVEN for odd round numbers", func() {
 		conf.NextRound.Number = 3
-		_, err := client.GenerateBriefing(conf, &models.Penalties{})
+		_, err := client.GenerateBriefing(conf, &models.Penalties{}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 
 		_, _, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
@@ -209,7 +541,7 @@ var _ = Describe("generateUpdates (via GenerateBriefing)", func() {
 
 	It("sets group1=EVEN and group2=ODD for even round numbers", func() {
 		conf.NextRound.Number = 4
-		_, err := client.GenerateBriefing(conf, &models.Penalties{})
+		_, err := client.GenerateBriefing(conf, &models.Penalties{}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 
 		_, _, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
@@ -256,7 +588,7 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 	It("includes '(carried over)' for QualiBansR1CarriedOver driver", func() {
 		_, err := client.GenerateBriefing(conf, &models.Penalties{
 			QualiBansR1CarriedOver: []models.Driver{{FirstName: "Alice", LastName: "Anderson", CarNumber: 1}},
-		})
+		}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		texts := getCapturedTexts()
 		Expect(strings.Join(texts, " ")).To(ContainSubstring("Alice"))
@@ -266,7 +598,7 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 	It("includes '(carried over)' for QualiBansR2CarriedOver driver", func() {
 		_, err := client.GenerateBriefing(conf, &models.Penalties{
 			QualiBansR2CarriedOver: []models.Driver{{FirstName: "Bob", LastName: "Brown", CarNumber: 2}},
-		})
+		}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		texts := getCapturedTexts()
 		Expect(strings.Join(texts, " ")).To(ContainSubstring("Bob"))
@@ -276,7 +608,7 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 	It("includes '(carried over)' for PitStartsR1CarriedOver driver", func() {
 		_, err := client.GenerateBriefing(conf, &models.Penalties{
 			PitStartsR1CarriedOver: []models.Driver{{FirstName: "Carol", LastName: "Chen", CarNumber: 3}},
-		})
+		}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		texts := getCapturedTexts()
 		Expect(strings.Join(texts, " ")).To(ContainSubstring("Carol"))
@@ -286,7 +618,7 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 	It("includes '(carried over)' for PitStartsR2CarriedOver driver", func() {
 		_, err := client.GenerateBriefing(conf, &models.Penalties{
 			PitStartsR2CarriedOver: []models.Driver{{FirstName: "Dave", LastName: "Davis", CarNumber: 4}},
-		})
+		}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		texts := getCapturedTexts()
 		Expect(strings.Join(texts, " ")).To(ContainSubstring("Dave"))
@@ -296,7 +628,7 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 	It("includes driver without '(carried over)' for QualiBansR1", func() {
 		_, err := client.GenerateBriefing(conf, &models.Penalties{
 			QualiBansR1: []models.Driver{{FirstName: "Eve", LastName: "Edwards", CarNumber: 5}},
-		})
+		}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		texts := getCapturedTexts()
 		joined := strings.Join(texts, " ")
@@ -307,7 +639,7 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 	It("includes driver without '(carried over)' for QualiBansR2", func() {
 		_, err := client.GenerateBriefing(conf, &models.Penalties{
 			QualiBansR2: []models.Driver{{FirstName: "Frank", LastName: "Flynn", CarNumber: 6}},
-		})
+		}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		texts := getCapturedTexts()
 		joined := strings.Join(texts, " ")
@@ -318,7 +650,7 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 	It("includes driver without '(carried over)' for PitStartsR1", func() {
 		_, err := client.GenerateBriefing(conf, &models.Penalties{
 			PitStartsR1: []models.Driver{{FirstName: "Grace", LastName: "Green", CarNumber: 7}},
-		})
+		}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		texts := getCapturedTexts()
 		joined := strings.Join(texts, " ")
@@ -329,7 +661,7 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 	It("includes driver without '(carried over)' for PitStartsR2", func() {
 		_, err := client.GenerateBriefing(conf, &models.Penalties{
 			PitStartsR2: []models.Driver{{FirstName: "Hank", LastName: "Harris", CarNumber: 8}},
-		})
+		}, briefingTime)
 		Expect(err).NotTo(HaveOccurred())
 		texts := getCapturedTexts()
 		joined := strings.Join(texts, " ")
@@ -337,11 +669,7 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 		Expect(joined).NotTo(ContainSubstring("carried over"))
 	})
 
-	// BUG DOCUMENTATION: penaltyStartIndex is int64, starts at 0, and the guard
-	// is `if penaltyStartIndex < 0`. Since 0 is never < 0, a doc with no Stream
-	// heading silently uses index 0 instead of returning an error. This test
-	// documents the current (buggy) behavior so any future fix is caught.
-	It("silently uses index 0 when doc has no Stream heading (documents bug)", func() {
+	It("returns an error when the doc has no penalties marker", func() {
 		noStreamDoc := &docs.Document{
 			Body: &docs.Body{
 				Content: []*docs.StructuralElement{
@@ -361,9 +689,73 @@ var _ = Describe("generateUpdates carried-over penalties", func() {
 		fakeDocsService.GetDocumentReturns(noStreamDoc, nil)
 		fakeDocsService.BatchUpdateDocumentReturns(&docs.BatchUpdateDocumentResponse{}, nil)
 
-		// BUG: should return error "no Stream heading found", but currently succeeds
-		_, err := client.GenerateBriefing(conf, &models.Penalties{})
-		Expect(err).NotTo(HaveOccurred()) // documents current buggy behavior
+		_, err := client.GenerateBriefing(conf, &models.Penalties{}, briefingTime)
+		Expect(err).To(MatchError(ContainSubstring(`has no "penalties" named range or "[penalties]" paragraph`)))
+		Expect(fakeDocsService.BatchUpdateDocumentCallCount()).To(Equal(0))
+	})
+
+	It("inserts the penalties above the Stream heading of a legacy template", func() {
+		doc := makeDoc(40)
+		doc.Body.Content = doc.Body.Content[1:]
+		fakeDocsService.GetDocumentReturns(doc, nil)
+
+		_, err := client.GenerateBriefing(conf, &models.Penalties{}, briefingTime)
+		Expect(err).NotTo(HaveOccurred())
+		_, _, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
+		Expect(req.Requests[0].DeleteContentRange).To(BeNil())
+		Expect(req.Requests[0].InsertText.Location.Index).To(Equal(int64(52)))
+	})
+
+	It("inserts the penalties at the named range", func() {
+		doc := makeDoc(40)
+		doc.NamedRanges = map[string]docs.NamedRanges{
+			"penalties": {Name: "penalties", NamedRanges: []*docs.NamedRange{{Ranges: []*docs.Range{{StartIndex: 3, EndIndex: 3}}}}},
+		}
+		fakeDocsService.GetDocumentReturns(doc, nil)
+
+		_, err := client.GenerateBriefing(conf, &models.Penalties{}, briefingTime)
+		Expect(err).NotTo(HaveOccurred())
+		_, _, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
+		Expect(req.Requests[0].DeleteContentRange).To(BeNil())
+		Expect(req.Requests[0].InsertText.Location.Index).To(Equal(int64(3)))
+	})
+
+	It("finds a configured marker split across text runs and skips elements without text", func() {
+		conf.BriefingTemplate.PenaltiesMarker = "<<penalties>>"
+		fakeDocsService.GetDocumentReturns(&docs.Document{Body: &docs.Body{Content: []*docs.StructuralElement{
+			{StartIndex: 1, EndIndex: 3, Paragraph: &docs.Paragraph{Elements: []*docs.ParagraphElement{{InlineObjectElement: &docs.InlineObjectElement{}}, {TextRun: &docs.TextRun{Content: "\n"}}}}},
+			{StartIndex: 3, EndIndex: 17, Paragraph: &docs.Paragraph{Elements: []*docs.ParagraphElement{
+				{TextRun: &docs.TextRun{Content: "<<pen"}},
+				{InlineObjectElement: &docs.InlineObjectElement{}},
+				{TextRun: &docs.TextRun{Content: "alties>>\n"}},
+			}}},
+		}}}, nil)
+
+		_, err := client.GenerateBriefing(conf, &models.Penalties{}, briefingTime)
+		Expect(err).NotTo(HaveOccurred())
+		_, _, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
+		// The marker ends the doc, so its final newline is kept.
+		Expect(req.Requests[0].DeleteContentRange.Range.StartIndex).To(Equal(int64(3)))
+		Expect(req.Requests[0].DeleteContentRange.Range.EndIndex).To(Equal(int64(16)))
+	})
+
+	It("fills in placeholders from the config", func() {
+		conf.BriefingTemplate.Placeholders = map[string]string{
+			"[Track Name]": "{{.Round.Track}} GP",
+			"[host]":       "Rookies {{.Season}}",
+		}
+		_, err := client.GenerateBriefing(conf, &models.Penalties{}, briefingTime)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _, req := fakeDocsService.BatchUpdateDocumentArgsForCall(0)
+		texts := make([]string, 0)
+		for _, r := range req.Requests {
+			if r.ReplaceAllText != nil {
+				texts = append(texts, fmt.Sprintf("%s->%s", r.ReplaceAllText.ContainsText.Text, r.ReplaceAllText.ReplaceText))
+			}
+		}
+		Expect(texts).To(ContainElement("[Track Name]->Silverstone GP"))
+		Expect(texts[len(texts)-1]).To(Equal("[host]->Rookies 2026"))
 	})
 })
 
@@ -429,3 +821,181 @@ var _ = Describe("EnsureSeasonFolder", func() {
 		Expect(err.Error()).To(ContainSubstring("create failed"))
 	})
 })
+
+var _ = Describe("CheckTemplate and CheckFolder", func() {
+	var (
+		fakeDrive *fakes.FakeDriveServicer
+		client    *gcloud.Client
+	)
+
+	BeforeEach(func() {
+		fakeDrive = new(fakes.FakeDriveServicer)
+		client = &gcloud.Client{Drive: fakeDrive}
+	})
+
+	It("accepts a doc the bot can copy", func() {
+		fakeDrive.GetFileReturns(&drive.File{Id: "template", Name: "Briefing", MimeType: "application/vnd.google-apps.document",
+			Capabilities: &drive.FileCapabilities{CanCopy: true}}, nil)
+		file, err := client.CheckTemplate(context.Background(), "template")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Name).To(Equal("Briefing"))
+	})
+
+	It("rejects templates that are missing, folders or not copyable", func() {
+		_, err := client.CheckTemplate(context.Background(), "")
+		Expect(err).To(MatchError("no template doc is configured"))
+
+		fakeDrive.GetFileReturns(nil, errors.New("404 not found"))
+		_, err = client.CheckTemplate(context.Background(), "template")
+		Expect(err).To(MatchError("404 not found"))
+
+		fakeDrive.GetFileReturns(&drive.File{Name: "Briefings", MimeType: "application/vnd.google-apps.folder"}, nil)
+		_, err = client.CheckTemplate(context.Background(), "template")
+		Expect(err).To(MatchError(`"Briefings" is a folder, not a doc`))
+
+		fakeDrive.GetFileReturns(&drive.File{Name: "Briefing", Capabilities: &drive.FileCapabilities{}}, nil)
+		_, err = client.CheckTemplate(context.Background(), "template")
+		Expect(err).To(MatchError(`the bot cannot copy "Briefing"`))
+	})
+
+	It("accepts a folder the bot can add files to", func() {
+		fakeDrive.GetFileReturns(&drive.File{Name: "Briefings", MimeType: "application/vnd.google-apps.folder",
+			Capabilities: &drive.FileCapabilities{CanAddChildren: true}}, nil)
+		_, err := client.CheckFolder(context.Background(), "folder")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects folders that are missing, docs or read-only", func() {
+		_, err := client.CheckFolder(context.Background(), "")
+		Expect(err).To(MatchError("no folder is configured"))
+
+		fakeDrive.GetFileReturns(&drive.File{Name: "Briefing", MimeType: "application/vnd.google-apps.document"}, nil)
+		_, err = client.CheckFolder(context.Background(), "folder")
+		Expect(err).To(MatchError(`"Briefing" is not a folder`))
+
+		fakeDrive.GetFileReturns(&drive.File{Name: "Briefings", MimeType: "application/vnd.google-apps.folder", Capabilities: &drive.FileCapabilities{}}, nil)
+		_, err = client.CheckFolder(context.Background(), "folder")
+		Expect(err).To(MatchError(`the bot cannot add files to "Briefings"`))
+	})
+})
+
+var _ = Describe("CheckSharing", func() {
+	var (
+		fakeDriveService *fakes.FakeDriveServicer
+		client           *gcloud.Client
+		sharing          config.Sharing
+	)
+
+	BeforeEach(func() {
+		fakeDriveService = new(fakes.FakeDriveServicer)
+		client = &gcloud.Client{Drive: fakeDriveService}
+		sharing = config.Sharing{BriefingLink: config.LinkRoleReader, TrackerEditors: []string{"steward@example.com", "chief@example.com"}}
+	})
+
+	It("accepts a file shared at least as widely as configured", func() {
+		fakeDriveService.ListPermissionsReturns([]*drive.Permission{
+			{Type: "user", Role: "owner", EmailAddress: "bot@project.iam.gserviceaccount.com"},
+			{Type: "anyone", Role: "commenter"},
+			{Type: "user", Role: "writer", EmailAddress: "Steward@Example.com"},
+			{Type: "user", Role: "organizer", EmailAddress: "chief@example.com"},
+		}, nil)
+		Expect(client.CheckSharing(context.Background(), "file-id", gcloud.BriefingPermissions(sharing))).To(Succeed())
+		Expect(client.CheckSharing(context.Background(), "file-id", gcloud.TrackerPermissions(sharing))).To(Succeed())
+		_, id := fakeDriveService.ListPermissionsArgsForCall(0)
+		Expect(id).To(Equal("file-id"))
+	})
+
+	It("reports what the file isn't shared with", func() {
+		fakeDriveService.ListPermissionsReturns([]*drive.Permission{
+			{Type: "user", Role: "reader", EmailAddress: "steward@example.com"},
+		}, nil)
+		Expect(client.CheckSharing(context.Background(), "file-id", gcloud.BriefingPermissions(sharing))).To(MatchError("not shared with anyone with the link as viewer"))
+		Expect(client.CheckSharing(context.Background(), "file-id", gcloud.TrackerPermissions(sharing))).To(MatchError("not shared with steward@example.com as editor, chief@example.com as editor"))
+	})
+
+	It("returns an error when the permissions can't be listed", func() {
+		fakeDriveService.ListPermissionsReturns(nil, errors.New("404 file not found"))
+		Expect(client.CheckSharing(context.Background(), "file-id", gcloud.TrackerPermissions(sharing))).To(MatchError("404 file not found"))
+	})
+})
+
+var _ = Describe("CheckBriefingContent", func() {
+	var (
+		fakeDocs *fakes.FakeDocsServicer
+		client   *gcloud.Client
+	)
+
+	paragraph := func(text ...string) *docs.StructuralElement {
+		p := &docs.Paragraph{}
+		for _, t := range text {
+			p.Elements = append(p.Elements, &docs.ParagraphElement{TextRun: &docs.TextRun{Content: t}})
+		}
+		return &docs.StructuralElement{Paragraph: p}
+	}
+
+	BeforeEach(func() {
+		fakeDocs = new(fakes.FakeDocsServicer)
+		client = &gcloud.Client{Docs: fakeDocs}
+		fakeDocs.GetDocumentReturns(&docs.Document{Title: "Briefing Template", Body: &docs.Body{Content: []*docs.StructuralElement{
+			paragraph("Round [num] at [Track Name]\n"),
+			paragraph("[SEASON] briefing at [briefing ", "time]\n"),
+			paragraph("[penalties]\n"),
+			{Table: &docs.Table{TableRows: []*docs.TableRow{{TableCells: []*docs.TableCell{
+				{Content: []*docs.StructuralElement{paragraph("[group1] first\n")}},
+				{Content: []*docs.StructuralElement{paragraph("[group2] second\n")}},
+			}}}}},
+		}}}, nil)
+	})
+
+	It("accepts a template with the marker and every placeholder", func() {
+		check, err := client.CheckBriefingContent(context.Background(), "briefing-template", config.BriefingTemplate{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check).To(Equal(&gcloud.BriefingTemplateCheck{Name: "Briefing Template", Marker: `"[penalties]" paragraph`}))
+		_, id := fakeDocs.GetDocumentArgsForCall(0)
+		Expect(id).To(Equal("briefing-template"))
+	})
+
+	It("prefers the named range", func() {
+		fakeDocs.GetDocumentReturns(&docs.Document{
+			NamedRanges: map[string]docs.NamedRanges{"bans": {NamedRanges: []*docs.NamedRange{{Ranges: []*docs.Range{{StartIndex: 4, EndIndex: 4}}}}}},
+			Body:        &docs.Body{},
+		}, nil)
+		check, err := client.CheckBriefingContent(context.Background(), "briefing-template", config.BriefingTemplate{PenaltiesRange: "bans"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Marker).To(Equal(`named range "bans"`))
+	})
+
+	It("reports a missing marker, missing placeholders and unknown bracketed text", func() {
+		check, err := client.CheckBriefingContent(context.Background(), "briefing-template", config.BriefingTemplate{
+			PenaltiesMarker: "{penalties}",
+			Placeholders:    map[string]string{"[group2]": "", "[host]": "Rookies"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Marker).To(BeEmpty())
+		Expect(check.Missing).To(Equal([]string{"[host]"}))
+		Expect(check.Unknown).To(Equal([]string{"[penalties]", "[group2]"}))
+	})
+
+	It("flags a legacy template marked only by its Stream heading", func() {
+		fakeDocs.GetDocumentReturns(&docs.Document{Title: "Old Template", Body: &docs.Body{Content: []*docs.StructuralElement{
+			{Paragraph: &docs.Paragraph{
+				ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "HEADING_3"},
+				Elements:       []*docs.ParagraphElement{{TextRun: &docs.TextRun{Content: "Stream\n"}}},
+			}},
+		}}}, nil)
+		check, err := client.CheckBriefingContent(context.Background(), "briefing-template", config.BriefingTemplate{Placeholders: map[string]string{
+			"[num]": "", "[Track Name]": "", "[group1]": "", "[group2]": "", "[briefing time]": "", "[SEASON]": "",
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check).To(Equal(&gcloud.BriefingTemplateCheck{Name: "Old Template", Marker: `legacy "Stream" heading`, Legacy: true}))
+	})
+
+	It("returns an error when the template can't be read", func() {
+		_, err := client.CheckBriefingContent(context.Background(), "", config.BriefingTemplate{})
+		Expect(err).To(MatchError("no template doc is configured"))
+
+		fakeDocs.GetDocumentReturns(nil, errors.New("404 not found"))
+		_, err = client.CheckBriefingContent(context.Background(), "briefing-template", config.BriefingTemplate{})
+		Expect(err).To(MatchError("404 not found"))
+	})
+})
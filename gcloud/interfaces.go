@@ -20,5 +20,10 @@ type DriveServicer interface {
 	CopyFile(ctx context.Context, templateID, folderID, title string) (*drive.File, error)
 	GetFile(ctx context.Context, id string) (*drive.File, error)
 	FindFolder(ctx context.Context, parentID, name string) (*drive.File, error)
+	FindFile(ctx context.Context, folderID, name string) (*drive.File, error)
 	CreateFolder(ctx context.Context, parentID, name string) (*drive.File, error)
+	TrashFile(ctx context.Context, id string) error
+	ExportFile(ctx context.Context, id, mimeType string) ([]byte, error)
+	CreatePermission(ctx context.Context, fileID string, permission *drive.Permission) error
+	ListPermissions(ctx context.Context, fileID string) ([]*drive.Permission, error)
 }
@@ -18,6 +18,12 @@ func main() {
 				Description: "Starts a long-running discord bot for rookies-bot",
 				Action:      r.bot,
 			},
+			{
+				Name:        "doctor",
+				Usage:       "doctor",
+				Description: "Checks the bot can reach SimGrid, Google Drive and Discord with the permissions it needs",
+				Action:      r.doctor,
+			},
 		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{Name: "config", Aliases: []string{"c"}, Value: "config.yml"},
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"flag"
 	"os"
+	"syscall"
 
 	"github.com/geofffranks/rookies-bot/config"
 	"github.com/geofffranks/rookies-bot/discord"
@@ -124,4 +125,33 @@ var _ = Describe("Runner.bot", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(fakeDC.CloseCallCount()).To(Equal(1))
 	})
+
+	It("shuts down on SIGTERM", func() {
+		stop <- syscall.SIGTERM
+		Expect(r.bot(nil)).To(Succeed())
+		Expect(fakeDC.CloseCallCount()).To(Equal(1))
+	})
+})
+
+var _ = Describe("Runner.doctor", func() {
+	var (
+		r      *Runner
+		fakeDC *fakes.FakeBotDiscordClient
+	)
+
+	BeforeEach(func() {
+		fakeDC = new(fakes.FakeBotDiscordClient)
+		r = &Runner{dc: fakeDC}
+	})
+
+	It("succeeds when every check passes", func() {
+		fakeDC.DoctorReturns("all good", true)
+		Expect(r.doctor(newTestCLIContext())).To(Succeed())
+		Expect(fakeDC.DoctorCallCount()).To(Equal(1))
+	})
+
+	It("fails when a check fails", func() {
+		fakeDC.DoctorReturns("something broke", false)
+		Expect(r.doctor(newTestCLIContext())).To(MatchError("some checks failed"))
+	})
 })
@@ -1,6 +1,11 @@
 package models
 
-import "github.com/geofffranks/rookies-bot/config"
+import (
+	"sort"
+	"strings"
+
+	"github.com/geofffranks/rookies-bot/config"
+)
 
 type DriverLookup map[int]Driver
 
@@ -9,6 +14,13 @@ type Driver struct {
 	LastName      string
 	DiscordHandle string
 	CarNumber     int
+	// SteamID is the driver's Steam64 ID, which SimGrid uses as the player ID.
+	SteamID string
+}
+
+// Name returns the driver's full name.
+func (d Driver) Name() string {
+	return strings.TrimSpace(d.FirstName + " " + d.LastName)
 }
 
 type Penalties struct {
@@ -48,6 +60,29 @@ func (p *Penalties) UniqueDriverNumbers() []int {
 	))
 }
 
+// UniqueDrivers returns every driver serving any penalty, once each, ordered
+// by car number.
+func (p *Penalties) UniqueDrivers() []Driver {
+	seen := map[int]Driver{}
+	for _, list := range [][]Driver{
+		p.QualiBansR1, p.QualiBansR1CarriedOver,
+		p.QualiBansR2, p.QualiBansR2CarriedOver,
+		p.PitStartsR1, p.PitStartsR1CarriedOver,
+		p.PitStartsR2, p.PitStartsR2CarriedOver,
+	} {
+		for _, driver := range list {
+			seen[driver.CarNumber] = driver
+		}
+	}
+
+	drivers := make([]Driver, 0, len(seen))
+	for _, driver := range seen {
+		drivers = append(drivers, driver)
+	}
+	sort.Slice(drivers, func(i, j int) bool { return drivers[i].CarNumber < drivers[j].CarNumber })
+	return drivers
+}
+
 func uniqueDrivers(drivers []Driver) []int {
 	l := map[int]struct{}{}
 
@@ -98,4 +98,30 @@ var _ = Describe("Penalties", func() {
 			Expect(result).To(ConsistOf(11))
 		})
 	})
+
+	Describe("UniqueDrivers()", func() {
+		It("returns each penalized driver once, ordered by car number", func() {
+			p := models.Penalties{
+				QualiBansR1:            []models.Driver{driver3},
+				PitStartsR1CarriedOver: []models.Driver{driver1, driver3},
+				PitStartsR2:            []models.Driver{driver2},
+			}
+			Expect(p.UniqueDrivers()).To(Equal([]models.Driver{driver1, driver2, driver3}))
+		})
+
+		It("returns an empty slice when no drivers have penalties", func() {
+			p := models.Penalties{}
+			Expect(p.UniqueDrivers()).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("Driver", func() {
+	It("Name() joins first and last name", func() {
+		Expect(models.Driver{FirstName: "Max", LastName: "V"}.Name()).To(Equal("Max V"))
+	})
+
+	It("Name() trims when a name part is missing", func() {
+		Expect(models.Driver{FirstName: "Max"}.Name()).To(Equal("Max"))
+	})
 })
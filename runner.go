@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/geofffranks/rookies-bot/config"
 	"github.com/geofffranks/rookies-bot/discord"
@@ -54,9 +55,11 @@ func (r *Runner) before(cCtx *cli.Context) error {
 	return nil
 }
 
-// bot is the urfave/cli action for the "bot" subcommand.
+// bot is the urfave/cli action for the "bot" subcommand. It runs until
+// SIGINT or SIGTERM (what `docker stop` sends), then shuts the bot down,
+// letting commands in flight finish within the configured shutdown timeout.
 func (r *Runner) bot(_ *cli.Context) error {
-	ctx := context.TODO()
+	ctx := context.Background()
 	if err := r.dc.OpenGateway(ctx); err != nil {
 		return err
 	}
@@ -66,10 +69,23 @@ func (r *Runner) bot(_ *cli.Context) error {
 	stop := r.stopChan
 	if stop == nil {
 		stop = make(chan os.Signal, 1)
-		signal.Notify(stop, os.Interrupt)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(stop)
 	}
-	<-stop
+	sig := <-stop
 
+	fmt.Printf("Received %s, shutting down.\n", sig)
 	r.dc.Close(ctx)
 	return nil
 }
+
+// doctor is the urfave/cli action for the "doctor" subcommand. It prints the
+// status checks and fails when any of them do.
+func (r *Runner) doctor(cCtx *cli.Context) error {
+	report, ok := r.dc.Doctor(cCtx.Context)
+	fmt.Println(report)
+	if !ok {
+		return fmt.Errorf("some checks failed")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package scheduler_test
+
+import (
+	"time"
+
+	"github.com/geofffranks/rookies-bot/scheduler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Spec", func() {
+	var eastern *time.Location
+
+	BeforeEach(func() {
+		var err error
+		eastern, err = time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	next := func(expr string, after time.Time) time.Time {
+		spec, err := scheduler.Parse(expr)
+		Expect(err).NotTo(HaveOccurred())
+		return spec.Next(after, eastern)
+	}
+
+	It("finds the next weekly run in the given timezone", func() {
+		// Wednesday 4 March 2026.
+		after := time.Date(2026, time.March, 4, 12, 0, 0, 0, eastern)
+		Expect(next("0 10 * * mon", after)).To(BeTemporally("==", time.Date(2026, time.March, 9, 10, 0, 0, 0, eastern)))
+		Expect(next("0 18 * * 4", after)).To(BeTemporally("==", time.Date(2026, time.March, 5, 18, 0, 0, 0, eastern)))
+	})
+
+	It("is strictly after the given time", func() {
+		after := time.Date(2026, time.March, 9, 10, 0, 0, 0, eastern)
+		Expect(next("0 10 * * mon", after)).To(BeTemporally("==", time.Date(2026, time.March, 16, 10, 0, 0, 0, eastern)))
+	})
+
+	It("supports steps, ranges and lists", func() {
+		after := time.Date(2026, time.March, 4, 12, 7, 0, 0, eastern)
+		Expect(next("*/15 * * * *", after)).To(BeTemporally("==", time.Date(2026, time.March, 4, 12, 15, 0, 0, eastern)))
+		Expect(next("0 9-17/4 * * *", after)).To(BeTemporally("==", time.Date(2026, time.March, 4, 13, 0, 0, 0, eastern)))
+		Expect(next("30 8 1,15 * *", after)).To(BeTemporally("==", time.Date(2026, time.March, 15, 8, 30, 0, 0, eastern)))
+	})
+
+	It("treats 7 as Sunday", func() {
+		after := time.Date(2026, time.March, 4, 12, 0, 0, 0, eastern)
+		Expect(next("0 0 * * 7", after)).To(BeTemporally("==", time.Date(2026, time.March, 8, 0, 0, 0, 0, eastern)))
+	})
+
+	It("matches either day field when both are restricted", func() {
+		after := time.Date(2026, time.March, 4, 12, 0, 0, 0, eastern)
+		Expect(next("0 0 6 * mon", after)).To(BeTemporally("==", time.Date(2026, time.March, 6, 0, 0, 0, 0, eastern)))
+	})
+
+	It("returns the zero time when nothing ever matches", func() {
+		Expect(next("0 0 31 feb *", time.Now()).IsZero()).To(BeTrue())
+	})
+
+	It("rejects malformed expressions", func() {
+		for _, expr := range []string{"0 10 * *", "60 * * * *", "0 10 * * funday", "*/0 * * * *", "5-1 * * * *"} {
+			_, err := scheduler.Parse(expr)
+			Expect(err).To(HaveOccurred(), expr)
+		}
+	})
+})
@@ -0,0 +1,260 @@
+// Package scheduler runs recurring bot jobs on cron schedules. Each job runs
+// at most once per key (e.g. once per round), and the schedule survives
+// restarts: a run that came due while the bot was down happens at startup.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/geofffranks/rookies-bot/state"
+)
+
+// stateDocument is the state document holding each job's run history.
+const stateDocument = "scheduler.yml"
+
+// Job is a recurring task.
+type Job struct {
+	// Name identifies the job in state and reports, e.g. "race-setup".
+	Name string
+	Spec *Spec
+	// Key returns the unit of work the job would act on right now, e.g.
+	// "2026 Fall/round-4". A job never runs twice for the same key.
+	Key func() (string, error)
+	// Run performs the job and returns a summary for the report.
+	Run func(ctx context.Context) (string, error)
+}
+
+// Result describes one due job: it either ran, was skipped, or failed.
+type Result struct {
+	Job     string
+	Key     string
+	Skipped bool
+	Output  string
+	Err     error
+}
+
+// String formats the result for an admin report.
+func (r Result) String() string {
+	switch {
+	case r.Err != nil && r.Key == "":
+		return fmt.Sprintf("⏰ Scheduled %s failed: %s", r.Job, r.Err)
+	case r.Err != nil:
+		return fmt.Sprintf("⏰ Scheduled %s for %s failed: %s", r.Job, r.Key, r.Err)
+	case r.Skipped:
+		return fmt.Sprintf("⏰ Skipped scheduled %s: it already ran for %s", r.Job, r.Key)
+	default:
+		return fmt.Sprintf("⏰ Scheduled %s for %s:\n%s", r.Job, r.Key, r.Output)
+	}
+}
+
+// JobState is the persisted run history of one job.
+type JobState struct {
+	// Spec is the cron expression NextRun was computed from; NextRun is
+	// recomputed when the configured expression changes.
+	Spec    string    `yaml:"spec"`
+	NextRun time.Time `yaml:"next_run"`
+	LastRun time.Time `yaml:"last_run,omitempty"`
+	// LastKey is the last key the job completed for, whether run by the
+	// scheduler or marked by hand.
+	LastKey   string `yaml:"last_key,omitempty"`
+	LastError string `yaml:"last_error,omitempty"`
+}
+
+type jobStates struct {
+	Jobs map[string]JobState `yaml:"jobs"`
+}
+
+// Scheduler runs jobs when they come due, in the league timezone.
+type Scheduler struct {
+	store  *state.Store
+	loc    *time.Location
+	report func(Result)
+	now    func() time.Time
+
+	mu   sync.Mutex
+	jobs []Job
+}
+
+// New returns a Scheduler persisting to store, evaluating cron expressions in
+// loc, and passing every result to report.
+func New(store *state.Store, loc *time.Location, report func(Result)) *Scheduler {
+	return &Scheduler{store: store, loc: loc, report: report, now: time.Now}
+}
+
+// Add registers a job.
+func (s *Scheduler) Add(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+}
+
+// Jobs returns the persisted state of every registered job, by name. NextRun
+// is zero for a job RunDue has not scheduled yet.
+func (s *Scheduler) Jobs() (map[string]JobState, error) {
+	var states jobStates
+	if err := s.store.Load(stateDocument, &states); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make(map[string]JobState, len(s.jobs))
+	for _, job := range s.jobs {
+		st := states.Jobs[job.Name]
+		if st.Spec != job.Spec.String() {
+			// Not yet (re)scheduled by RunDue.
+			st.Spec = job.Spec.String()
+			st.NextRun = time.Time{}
+		}
+		jobs[job.Name] = st
+	}
+	return jobs, nil
+}
+
+// MarkRun records that name completed for key outside the scheduler (e.g. an
+// admin ran the command by hand), so the scheduled run skips it.
+func (s *Scheduler) MarkRun(name, key string) error {
+	var states jobStates
+	return s.store.Update(stateDocument, &states, func() error {
+		if states.Jobs == nil {
+			states.Jobs = map[string]JobState{}
+		}
+		st := states.Jobs[name]
+		st.LastKey = key
+		st.LastRun = s.now()
+		st.LastError = ""
+		states.Jobs[name] = st
+		return nil
+	})
+}
+
+// ClearRun forgets that name completed for key (e.g. an admin rolled the run
+// back), so the next scheduled run does it again. Runs for other keys are
+// left alone.
+func (s *Scheduler) ClearRun(name, key string) error {
+	var states jobStates
+	return s.store.Update(stateDocument, &states, func() error {
+		st, ok := states.Jobs[name]
+		if !ok || st.LastKey != key {
+			return nil
+		}
+		st.LastKey = ""
+		states.Jobs[name] = st
+		return nil
+	})
+}
+
+// Run calls RunDue whenever a job comes due until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		s.RunDue(ctx)
+
+		wait := time.Minute
+		if next, ok := s.nextRun(); ok {
+			wait = min(max(next.Sub(s.now()), time.Second), time.Hour)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// RunDue runs every job whose next run time has passed and reports each
+// result. A job seen for the first time is scheduled but not run.
+func (s *Scheduler) RunDue(ctx context.Context) []Result {
+	s.mu.Lock()
+	jobs := append([]Job{}, s.jobs...)
+	s.mu.Unlock()
+
+	var results []Result
+	for _, job := range jobs {
+		result, ran, err := s.runIfDue(ctx, job)
+		if err != nil {
+			result = Result{Job: job.Name, Err: fmt.Errorf("failed updating schedule state: %w", err)}
+			ran = true
+		}
+		if !ran {
+			continue
+		}
+		results = append(results, result)
+		if s.report != nil {
+			s.report(result)
+		}
+	}
+	return results
+}
+
+func (s *Scheduler) runIfDue(ctx context.Context, job Job) (Result, bool, error) {
+	now := s.now()
+	var states jobStates
+	if err := s.store.Load(stateDocument, &states); err != nil {
+		return Result{}, false, err
+	}
+	st := states.Jobs[job.Name]
+
+	if st.Spec != job.Spec.String() || st.NextRun.IsZero() {
+		st.Spec = job.Spec.String()
+		st.NextRun = job.Spec.Next(now, s.loc)
+		return Result{}, false, s.saveJob(job.Name, st)
+	}
+	if now.Before(st.NextRun) {
+		return Result{}, false, nil
+	}
+
+	st.NextRun = job.Spec.Next(now, s.loc)
+	st.LastRun = now
+	result := Result{Job: job.Name}
+
+	result.Key, result.Err = job.Key()
+	switch {
+	case result.Err != nil:
+	case result.Key == st.LastKey:
+		result.Skipped = true
+	default:
+		result.Output, result.Err = job.Run(ctx)
+	}
+
+	st.LastError = ""
+	if result.Err != nil {
+		st.LastError = result.Err.Error()
+	} else {
+		st.LastKey = result.Key
+	}
+	return result, true, s.saveJob(job.Name, st)
+}
+
+// saveJob writes one job's state, keeping any LastKey marked by hand while
+// the job was running.
+func (s *Scheduler) saveJob(name string, st JobState) error {
+	var states jobStates
+	return s.store.Update(stateDocument, &states, func() error {
+		if states.Jobs == nil {
+			states.Jobs = map[string]JobState{}
+		}
+		if current, ok := states.Jobs[name]; ok && current.LastRun.After(st.LastRun) {
+			st.LastKey = current.LastKey
+		}
+		states.Jobs[name] = st
+		return nil
+	})
+}
+
+func (s *Scheduler) nextRun() (time.Time, bool) {
+	jobs, err := s.Jobs()
+	if err != nil {
+		return time.Time{}, false
+	}
+	var next time.Time
+	for _, st := range jobs {
+		if !st.NextRun.IsZero() && (next.IsZero() || st.NextRun.Before(next)) {
+			next = st.NextRun
+		}
+	}
+	return next, !next.IsZero()
+}
@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/geofffranks/rookies-bot/state"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduler", func() {
+	var (
+		dir      string
+		store    *state.Store
+		eastern  *time.Location
+		now      time.Time
+		key      string
+		runs     int
+		runErr   error
+		reported []Result
+		sched    *Scheduler
+	)
+
+	newScheduler := func() *Scheduler {
+		s := New(store, eastern, func(r Result) { reported = append(reported, r) })
+		s.now = func() time.Time { return now }
+		spec, err := Parse("0 10 * * mon")
+		Expect(err).NotTo(HaveOccurred())
+		s.Add(Job{
+			Name: "race-setup",
+			Spec: spec,
+			Key:  func() (string, error) { return key, nil },
+			Run: func(ctx context.Context) (string, error) {
+				runs++
+				return "done", runErr
+			},
+		})
+		return s
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "rookies-bot-scheduler")
+		Expect(err).NotTo(HaveOccurred())
+		store = state.NewStore(dir)
+		eastern, err = time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Wednesday 4 March 2026.
+		now = time.Date(2026, time.March, 4, 12, 0, 0, 0, eastern)
+		key, runs, runErr, reported = "2026/round-3", 0, nil, nil
+		sched = newScheduler()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("schedules new jobs without running them", func() {
+		Expect(sched.RunDue(context.Background())).To(BeEmpty())
+		Expect(runs).To(Equal(0))
+
+		jobs, err := sched.Jobs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs["race-setup"].NextRun).To(BeTemporally("==", time.Date(2026, time.March, 9, 10, 0, 0, 0, eastern)))
+	})
+
+	It("runs due jobs and reports the result", func() {
+		sched.RunDue(context.Background())
+		now = time.Date(2026, time.March, 9, 10, 0, 30, 0, eastern)
+
+		results := sched.RunDue(context.Background())
+		Expect(runs).To(Equal(1))
+		Expect(results).To(ConsistOf(Result{Job: "race-setup", Key: "2026/round-3", Output: "done"}))
+		Expect(reported).To(Equal(results))
+
+		jobs, err := sched.Jobs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs["race-setup"].LastKey).To(Equal("2026/round-3"))
+		Expect(jobs["race-setup"].NextRun).To(BeTemporally("==", time.Date(2026, time.March, 16, 10, 0, 0, 0, eastern)))
+	})
+
+	It("skips jobs that already ran for the key", func() {
+		sched.RunDue(context.Background())
+		Expect(sched.MarkRun("race-setup", "2026/round-3")).To(Succeed())
+		now = time.Date(2026, time.March, 9, 10, 0, 0, 0, eastern)
+
+		results := sched.RunDue(context.Background())
+		Expect(runs).To(Equal(0))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Skipped).To(BeTrue())
+		Expect(results[0].String()).To(ContainSubstring("already ran for 2026/round-3"))
+	})
+
+	It("runs a key again once its run is cleared", func() {
+		sched.RunDue(context.Background())
+		Expect(sched.MarkRun("race-setup", "2026/round-3")).To(Succeed())
+		Expect(sched.ClearRun("race-setup", "2026/round-2")).To(Succeed())
+		now = time.Date(2026, time.March, 9, 10, 0, 0, 0, eastern)
+		Expect(sched.RunDue(context.Background())[0].Skipped).To(BeTrue())
+
+		Expect(sched.ClearRun("race-setup", "2026/round-3")).To(Succeed())
+		now = time.Date(2026, time.March, 16, 10, 0, 0, 0, eastern)
+		results := sched.RunDue(context.Background())
+		Expect(results[0].Skipped).To(BeFalse())
+		Expect(runs).To(Equal(1))
+	})
+
+	It("retries a failed key on the next run", func() {
+		sched.RunDue(context.Background())
+		runErr = errors.New("simgrid down")
+		now = time.Date(2026, time.March, 9, 10, 0, 0, 0, eastern)
+
+		results := sched.RunDue(context.Background())
+		Expect(results[0].String()).To(Equal("⏰ Scheduled race-setup for 2026/round-3 failed: simgrid down"))
+
+		runErr = nil
+		now = time.Date(2026, time.March, 16, 10, 0, 0, 0, eastern)
+		sched.RunDue(context.Background())
+		Expect(runs).To(Equal(2))
+	})
+
+	It("runs a job that came due while the bot was down after a restart", func() {
+		sched.RunDue(context.Background())
+
+		now = time.Date(2026, time.March, 9, 14, 0, 0, 0, eastern)
+		restarted := newScheduler()
+		restarted.RunDue(context.Background())
+		Expect(runs).To(Equal(1))
+	})
+
+	It("reschedules when the cron expression changes", func() {
+		sched.RunDue(context.Background())
+
+		spec, err := Parse("0 18 * * thu")
+		Expect(err).NotTo(HaveOccurred())
+		sched.jobs[0].Spec = spec
+		sched.RunDue(context.Background())
+
+		jobs, err := sched.Jobs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs["race-setup"].Spec).To(Equal("0 18 * * thu"))
+		Expect(jobs["race-setup"].NextRun).To(BeTemporally("==", time.Date(2026, time.March, 5, 18, 0, 0, 0, eastern)))
+	})
+})
@@ -1,6 +1,7 @@
 package simgrid
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,6 +18,7 @@ type SimGridClient struct {
 	token      string
 	httpClient *http.Client
 	BaseURL    string
+	ctx        context.Context
 }
 
 func NewClient(apitoken string) *SimGridClient {
@@ -24,9 +26,18 @@ func NewClient(apitoken string) *SimGridClient {
 		token:      apitoken,
 		httpClient: &http.Client{},
 		BaseURL:    "https://www.thesimgrid.com/api/v1",
+		ctx:        context.Background(),
 	}
 }
 
+// WithContext returns a copy of the client whose requests are cancelled
+// along with ctx.
+func (sgc *SimGridClient) WithContext(ctx context.Context) *SimGridClient {
+	c := *sgc
+	c.ctx = ctx
+	return &c
+}
+
 type EntryListResp struct {
 	Entries []Entry `json:"entries"`
 }
@@ -44,6 +55,9 @@ type Driver struct {
 
 type Race struct {
 	Track Track `json:"track"`
+	// StartsAt is the race start as an RFC3339 timestamp, when SimGrid has
+	// one scheduled.
+	StartsAt string `json:"starts_at"`
 }
 
 type Track struct {
@@ -212,12 +226,13 @@ func (sgc *SimGridClient) GetNextRound(id string, prev config.Round) (*config.Ro
 	}
 
 	nextRoundNum := prev.Number + 1
-	nextTrack := ""
+	next := &config.Round{Number: nextRoundNum}
 	if len(championship.Races) >= nextRoundNum {
-		nextTrack = championship.Races[nextRoundNum-1].Track.Name
+		next.Track = championship.Races[nextRoundNum-1].Track.Name
+		next.Date = championship.Races[nextRoundNum-1].StartsAt
 	}
 
-	return &config.Round{Number: nextRoundNum, Track: nextTrack}, nil
+	return next, nil
 }
 
 func (sgc *SimGridClient) BuildDriverLookup(id string) (models.DriverLookup, error) {
@@ -255,6 +270,7 @@ func (sgc *SimGridClient) BuildDriverLookup(id string) (models.DriverLookup, err
 			LastName:      user.LastName,
 			DiscordHandle: user.DiscordHandle,
 			CarNumber:     user.CarNumber,
+			SteamID:       user.SteamID,
 		}
 	}
 	return parsedUsers, nil
@@ -262,7 +278,7 @@ func (sgc *SimGridClient) BuildDriverLookup(id string) (models.DriverLookup, err
 
 func (sgc *SimGridClient) makeRequest(method, url string) (*http.Response, error) {
 	toReq := fmt.Sprintf("%s%s", sgc.BaseURL, url)
-	req, err := http.NewRequest(method, toReq, nil)
+	req, err := http.NewRequestWithContext(sgc.ctx, method, toReq, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package simgrid_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -122,7 +123,7 @@ var _ = Describe("SimGridClient", func() {
 					Races: []simgrid.Race{
 						{Track: simgrid.Track{Name: "Spa"}},
 						{Track: simgrid.Track{Name: "Monza"}},
-						{Track: simgrid.Track{Name: "Silverstone"}},
+						{Track: simgrid.Track{Name: "Silverstone"}, StartsAt: "2026-03-10T00:30:00Z"},
 					},
 				})
 			})
@@ -131,6 +132,7 @@ var _ = Describe("SimGridClient", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(next.Number).To(Equal(3))
 			Expect(next.Track).To(Equal("Silverstone"))
+			Expect(next.Date).To(Equal("2026-03-10T00:30:00Z"))
 		})
 
 		It("returns an empty track when the race list is exhausted", func() {
@@ -182,6 +184,23 @@ var _ = Describe("SimGridClient", func() {
 		})
 	})
 
+	Describe("WithContext", func() {
+		It("cancels requests along with the context", func() {
+			mux.HandleFunc("/championships/24877", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"id":24877}`))
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			cancelled := client.WithContext(ctx)
+			cancel()
+
+			_, err := cancelled.GetChampionship("24877")
+			Expect(err).To(MatchError(context.Canceled))
+
+			_, err = client.GetChampionship("24877")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Describe("GetChampionship", func() {
 		It("returns the parsed detail including host and start date", func() {
 			mux.HandleFunc("/championships/24877", func(w http.ResponseWriter, r *http.Request) {
@@ -248,6 +267,7 @@ var _ = Describe("SimGridClient", func() {
 			Expect(lookup).To(HaveLen(2))
 			Expect(lookup[33].DiscordHandle).To(Equal("maxv"))
 			Expect(lookup[44].DiscordHandle).To(Equal("lewish"))
+			Expect(lookup[33].SteamID).To(Equal("111"))
 		})
 
 		It("skips entry drivers with blank first and last name", func() {
@@ -0,0 +1,135 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Store persists bot state as YAML documents under a directory. Each document
+// is one file, named by a slash-separated path relative to the directory
+// (e.g. "identities.yml" or "rounds/2026-fall/round-3.yml").
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore returns a Store rooted at dir. An empty dir yields a store that
+// reads nothing and refuses to save.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the directory documents are stored under.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Load reads the document name into v. A missing document leaves v untouched
+// and is not an error.
+func (s *Store) Load(name string, v any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(name, v)
+}
+
+// Save writes v as the document name, replacing any previous version.
+func (s *Store) Save(name string, v any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(name, v)
+}
+
+// Update loads name into v, calls fn, and saves v, all while holding the
+// store lock so concurrent read-modify-write cycles never lose an update.
+// Nothing is saved when fn returns an error.
+func (s *Store) Update(name string, v any, fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(name, v); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return s.save(name, v)
+}
+
+// SaveFile writes data as-is to the file name, for state kept in another
+// format than YAML, such as a PDF. Delete removes it.
+func (s *Store) SaveFile(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dir == "" {
+		return fmt.Errorf("cannot save state %s: no state_dir configured", name)
+	}
+	return s.write(name, data)
+}
+
+// Delete removes the document name. Deleting a missing document is not an
+// error.
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dir == "" {
+		return nil
+	}
+	err := os.Remove(s.path(name))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed deleting state %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(name))
+}
+
+func (s *Store) load(name string, v any) error {
+	if s.dir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed reading state %s: %w", name, err)
+	}
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed parsing state %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Store) save(name string, v any) error {
+	if s.dir == "" {
+		return fmt.Errorf("cannot save state %s: no state_dir configured", name)
+	}
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed serializing state %s: %w", name, err)
+	}
+	return s.write(name, data)
+}
+
+// write writes to a temporary file and renames it into place, so a crash
+// mid-write never leaves a truncated file behind.
+func (s *Store) write(name string, data []byte) error {
+	path := s.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed creating state directory for %s: %w", name, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed writing state %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed writing state %s: %w", name, err)
+	}
+	return nil
+}
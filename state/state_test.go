@@ -0,0 +1,137 @@
+package state_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/geofffranks/rookies-bot/state"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type doc struct {
+	Count int      `yaml:"count"`
+	Names []string `yaml:"names"`
+}
+
+var _ = Describe("Store", func() {
+	var (
+		tmpDir string
+		store  *state.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "rookies-bot-state-test")
+		Expect(err).NotTo(HaveOccurred())
+		store = state.NewStore(tmpDir)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("round-trips a document", func() {
+		Expect(store.Save("doc.yml", &doc{Count: 3, Names: []string{"a"}})).To(Succeed())
+		var got doc
+		Expect(store.Load("doc.yml", &got)).To(Succeed())
+		Expect(got).To(Equal(doc{Count: 3, Names: []string{"a"}}))
+	})
+
+	It("leaves the value untouched when the document does not exist", func() {
+		got := doc{Count: 7}
+		Expect(store.Load("missing.yml", &got)).To(Succeed())
+		Expect(got.Count).To(Equal(7))
+	})
+
+	It("creates nested directories for slash-separated names", func() {
+		Expect(store.Save("rounds/fall/round-1.yml", &doc{Count: 1})).To(Succeed())
+		_, err := os.Stat(filepath.Join(tmpDir, "rounds", "fall", "round-1.yml"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns an error for a corrupt document", func() {
+		Expect(os.WriteFile(filepath.Join(tmpDir, "bad.yml"), []byte("}{"), 0600)).To(Succeed())
+		var got doc
+		err := store.Load("bad.yml", &got)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("bad.yml"))
+	})
+
+	It("deletes documents and tolerates deleting missing ones", func() {
+		Expect(store.Save("doc.yml", &doc{Count: 1})).To(Succeed())
+		Expect(store.Delete("doc.yml")).To(Succeed())
+		Expect(store.Delete("doc.yml")).To(Succeed())
+		got := doc{}
+		Expect(store.Load("doc.yml", &got)).To(Succeed())
+		Expect(got.Count).To(Equal(0))
+	})
+
+	It("saves files as they are", func() {
+		Expect(store.SaveFile("briefings/2026-fall/round-3.pdf", []byte("%PDF-1.7"))).To(Succeed())
+		data, err := os.ReadFile(filepath.Join(tmpDir, "briefings", "2026-fall", "round-3.pdf"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("%PDF-1.7"))
+
+		Expect(store.Delete("briefings/2026-fall/round-3.pdf")).To(Succeed())
+		Expect(filepath.Join(tmpDir, "briefings", "2026-fall", "round-3.pdf")).NotTo(BeAnExistingFile())
+	})
+
+	Describe("Update", func() {
+		It("does not save when fn fails", func() {
+			Expect(store.Save("doc.yml", &doc{Count: 1})).To(Succeed())
+			var d doc
+			err := store.Update("doc.yml", &d, func() error {
+				d.Count = 99
+				return fmt.Errorf("nope")
+			})
+			Expect(err).To(MatchError("nope"))
+
+			var got doc
+			Expect(store.Load("doc.yml", &got)).To(Succeed())
+			Expect(got.Count).To(Equal(1))
+		})
+
+		It("serializes concurrent read-modify-write cycles", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					var d doc
+					Expect(store.Update("counter.yml", &d, func() error {
+						d.Count++
+						return nil
+					})).To(Succeed())
+				}()
+			}
+			wg.Wait()
+
+			var got doc
+			Expect(store.Load("counter.yml", &got)).To(Succeed())
+			Expect(got.Count).To(Equal(20))
+		})
+	})
+
+	Context("without a directory", func() {
+		BeforeEach(func() {
+			store = state.NewStore("")
+		})
+
+		It("loads nothing", func() {
+			got := doc{Count: 2}
+			Expect(store.Load("doc.yml", &got)).To(Succeed())
+			Expect(got.Count).To(Equal(2))
+		})
+
+		It("refuses to save", func() {
+			err := store.Save("doc.yml", &doc{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("state_dir"))
+			Expect(store.SaveFile("briefing.pdf", nil)).To(MatchError(ContainSubstring("state_dir")))
+		})
+	})
+})